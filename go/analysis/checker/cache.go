@@ -0,0 +1,585 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package checker
+
+// This file defines the persistent cache of analysis results
+// enabled by Options.CacheDir.
+//
+// Each cache entry holds the facts and diagnostics produced by one
+// action. Its key is a hash of:
+//
+//   - the analysis driver executable, which determines the behavior
+//     of every analyzer;
+//   - the analyzer's name, fact types, and flag values;
+//   - the package's files and those aspects of its configuration
+//     that affect type checking, along with the export data of each
+//     of its direct imports (which summarizes all the type
+//     information the analyzer can observe from dependencies);
+//   - the hash of the facts inherited from each dependency (vertical
+//     edge), so that the entry is invalidated only when facts change;
+//   - the keys of each prerequisite analyzer (horizontal edge).
+//
+// Entries are stored as files named by their key beneath the cache
+// directory. The cache is an optimization: any failure to read or
+// write an entry is treated as a miss.
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"flag"
+	"fmt"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/gcexportdata"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/objectpath"
+)
+
+// A cache is the persistent cache of action results used by
+// one call to Analyze.
+type cache struct {
+	dir      string
+	salt     [sha256.Size]byte // identity of the driver
+	readFile func(string) ([]byte, error)
+
+	mu       sync.Mutex
+	packages map[*packages.Package]*packageHashes
+}
+
+// packageHashes holds the memoized hashes of one package.
+type packageHashes struct {
+	content func() [sha256.Size]byte // files, configuration, and imports
+	export  func() [sha256.Size]byte // export data, or content if unavailable
+}
+
+// newCache returns a cache in the specified directory.
+// The hashes of packages are computed as needed by each action.
+func newCache(dir string, readFile func(string) ([]byte, error)) *cache {
+	return &cache{
+		dir:      dir,
+		salt:     executableHash(),
+		readFile: readFile,
+		packages: make(map[*packages.Package]*packageHashes),
+	}
+}
+
+// hashes returns the memoized hashes of pkg.
+func (c *cache) hashes(pkg *packages.Package) *packageHashes {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ph, ok := c.packages[pkg]
+	if !ok {
+		ph = new(packageHashes)
+		ph.content = sync.OnceValue(func() [sha256.Size]byte { return c.hashContent(pkg) })
+		ph.export = sync.OnceValue(func() [sha256.Size]byte {
+			if sum, ok := hashExportData(pkg); ok {
+				return sum
+			}
+			// Fall back to the complete contents of the package.
+			return ph.content()
+		})
+		c.packages[pkg] = ph
+	}
+	return ph
+}
+
+// hashContent returns the hash of the files and configuration of
+// pkg, and of the export data of its direct imports.
+func (c *cache) hashContent(pkg *packages.Package) (sum [sha256.Size]byte) {
+	h := sha256.New()
+	fmt.Fprintf(h, "package %s %s %s illtyped=%t\n", pkg.ID, pkg.PkgPath, pkg.Name, pkg.IllTyped)
+	if mod := pkg.Module; mod != nil {
+		fmt.Fprintf(h, "module %s %s %s\n", mod.Path, mod.Version, mod.GoVersion)
+	}
+	if sizes := pkg.TypesSizes; sizes != nil {
+		fmt.Fprintf(h, "sizes %d %d\n",
+			sizes.Sizeof(types.Typ[types.Uintptr]),
+			sizes.Alignof(types.Typ[types.Int64]))
+	}
+	for _, err := range pkg.Errors {
+		fmt.Fprintf(h, "error %s\n", err)
+	}
+	for _, files := range [][]string{pkg.CompiledGoFiles, pkg.OtherFiles, pkg.IgnoredFiles} {
+		for _, filename := range files {
+			content, err := c.readFile(filename)
+			fmt.Fprintf(h, "file %s %d %v\n", filename, len(content), err)
+			h.Write(content)
+		}
+		fmt.Fprintf(h, "\n")
+	}
+	paths := make([]string, 0, len(pkg.Imports))
+	for path := range pkg.Imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(h, "import %s\n", path)
+		sum := c.hashes(pkg.Imports[path]).export()
+		h.Write(sum[:])
+	}
+	h.Sum(sum[:0])
+	return
+}
+
+// hashExportData returns the hash of the export data of pkg,
+// and reports whether it was successful.
+func hashExportData(pkg *packages.Package) (sum [sha256.Size]byte, ok bool) {
+	if pkg.Types == nil || pkg.Fset == nil || pkg.IllTyped {
+		return sum, false
+	}
+	h := sha256.New()
+	if err := gcexportdata.Write(h, pkg.Fset, pkg.Types); err != nil {
+		return sum, false
+	}
+	h.Sum(sum[:0])
+	return sum, true
+}
+
+// executableHash returns the hash of the running executable, which
+// implements the analyzers, or of the Go runtime version if the
+// executable cannot be read.
+var executableHash = sync.OnceValue(func() (sum [sha256.Size]byte) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if exe, err := os.Executable(); err == nil {
+		if f, err := os.Open(exe); err == nil {
+			io.Copy(h, f)
+			f.Close()
+		}
+	}
+	h.Sum(sum[:0])
+	return
+})
+
+// cacheKey returns the key of the cache entry for act,
+// executing the vertical dependencies of act (and of its
+// prerequisites) as needed to obtain their facts.
+// It fails if any of those dependencies failed.
+func (act *Action) cacheKey() ([sha256.Size]byte, error) {
+	act.keyOnce.Do(func() {
+		var vdeps []*Action
+		for _, dep := range act.Deps {
+			if dep.Package != act.Package {
+				vdeps = append(vdeps, dep)
+			}
+		}
+		execAll(vdeps)
+
+		h := sha256.New()
+		salt := act.cache.salt
+		h.Write(salt[:])
+		pkg := act.cache.hashes(act.Package).content()
+		h.Write(pkg[:])
+		a := act.Analyzer
+		fmt.Fprintf(h, "analyzer %s\n", a.Name)
+		for _, f := range a.FactTypes {
+			fmt.Fprintf(h, "fact %v\n", reflect.TypeOf(f))
+		}
+		a.Flags.VisitAll(func(f *flag.Flag) {
+			fmt.Fprintf(h, "flag -%s=%s\n", f.Name, f.Value)
+		})
+		for _, dep := range act.Deps {
+			if dep.Package != act.Package {
+				// Vertical edge: facts from a dependency.
+				if dep.Err != nil {
+					act.keyErr = fmt.Errorf("failed prerequisite: %s", dep)
+					return
+				}
+				h.Write(dep.factsHash[:])
+			} else {
+				// Horizontal edge: result of a prerequisite.
+				key, err := dep.cacheKey()
+				if err != nil {
+					act.keyErr = err
+					return
+				}
+				h.Write(key[:])
+			}
+		}
+		h.Sum(act.key[:0])
+	})
+	return act.key, act.keyErr
+}
+
+// execCached attempts to obtain the facts and diagnostics of act
+// from the cache, and reports whether it was successful.
+func (act *Action) execCached() bool {
+	key, err := act.cacheKey()
+	if err != nil {
+		return false
+	}
+
+	// Reading the cache is subject to the same limits as execution.
+	release, err := act.sched.acquire()
+	if err != nil {
+		return false // cancelled
+	}
+	defer release()
+
+	t0 := time.Now()
+	defer func() { act.Duration = time.Since(t0) }()
+
+	data, err := os.ReadFile(act.cache.filename(key))
+	if err != nil {
+		return false
+	}
+	var entry cacheEntry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entry); err != nil {
+		return false
+	}
+
+	act.objectFacts = make(map[objectFactKey]analysis.Fact)
+	act.packageFacts = make(map[packageFactKey]analysis.Fact)
+	for _, dep := range act.Deps {
+		if dep.Package != act.Package {
+			inheritFacts(act, dep)
+		}
+	}
+	if err := act.decodeFacts(entry.Facts); err != nil {
+		return false
+	}
+	diags, err := act.decodeDiagnostics(entry.Diagnostics)
+	if err != nil {
+		return false
+	}
+	act.Diagnostics = diags
+	act.factsHash = act.hashFacts(entry.Facts)
+	return true
+}
+
+// put computes the hash of the facts of act, which has just been
+// executed, for use by dependent actions, and records its facts and
+// diagnostics in the cache if they may be reused.
+func (c *cache) put(act *Action) {
+	if act.Err != nil {
+		return
+	}
+	facts, err := act.encodeFacts()
+	if err != nil {
+		act.Err = err
+		return
+	}
+	act.factsHash = act.hashFacts(facts)
+
+	// The results of prerequisite analyzers are never obtained
+	// from the cache, so don't bother saving their facts.
+	if act.needed {
+		return
+	}
+	key, err := act.cacheKey()
+	if err != nil {
+		return
+	}
+	diags, err := act.encodeDiagnostics()
+	if err != nil {
+		return // e.g. diagnostic in a file unknown to the FileSet
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(cacheEntry{facts, diags}); err != nil {
+		return
+	}
+	c.write(key, buf.Bytes()) // ignore error
+}
+
+// filename returns the name of the file holding the entry with
+// the specified key.
+func (c *cache) filename(key [sha256.Size]byte) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%x", key[:1]), fmt.Sprintf("%x-analysis", key))
+}
+
+// write atomically writes the entry for the specified key.
+func (c *cache) write(key [sha256.Size]byte, data []byte) error {
+	filename := c.filename(key)
+	if err := os.MkdirAll(filepath.Dir(filename), 0777); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filename)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// hashFacts returns the hash of the facts visible to dependents of
+// act: its own encoded facts plus those it inherited.
+func (act *Action) hashFacts(facts []cacheFact) (sum [sha256.Size]byte) {
+	if len(act.Analyzer.FactTypes) == 0 {
+		return // no facts
+	}
+	h := sha256.New()
+	for _, fact := range facts {
+		fmt.Fprintf(h, "%s %s %d\n", fact.Object, fact.Type, len(fact.Data))
+		h.Write(fact.Data)
+	}
+	for _, dep := range act.Deps {
+		if dep.Package != act.Package {
+			h.Write(dep.factsHash[:])
+		}
+	}
+	h.Sum(sum[:0])
+	return
+}
+
+// A cacheEntry is the persistent form of the outputs of one action.
+type cacheEntry struct {
+	Facts       []cacheFact
+	Diagnostics []cacheDiagnostic
+}
+
+// A cacheFact is the persistent form of a fact exported by an action.
+type cacheFact struct {
+	Object objectpath.Path // empty for a package fact
+	Type   string          // fact type, e.g. "*printf.isWrapper"
+	Data   []byte          // gob encoding of fact
+}
+
+// encodeFacts returns the facts exported by act, in a deterministic
+// order. Facts about objects that are not addressable by an
+// objectpath, such as local variables, are discarded, as they are by
+// unitchecker.
+func (act *Action) encodeFacts() ([]cacheFact, error) {
+	var facts []cacheFact
+	encode := func(path objectpath.Path, fact analysis.Fact) error {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(fact); err != nil {
+			return fmt.Errorf("internal error: encoding of %T fact failed in %v: %v", fact, act, err)
+		}
+		facts = append(facts, cacheFact{path, reflect.TypeOf(fact).String(), buf.Bytes()})
+		return nil
+	}
+	for key, fact := range act.objectFacts {
+		if key.obj.Pkg() != act.Package.Types {
+			continue // inherited
+		}
+		path, err := objectpath.For(key.obj)
+		if err != nil {
+			continue
+		}
+		if err := encode(path, fact); err != nil {
+			return nil, err
+		}
+	}
+	for key, fact := range act.packageFacts {
+		if key.pkg != act.Package.Types {
+			continue // inherited
+		}
+		if err := encode("", fact); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(facts, func(i, j int) bool {
+		x, y := facts[i], facts[j]
+		if x.Object != y.Object {
+			return x.Object < y.Object
+		}
+		return x.Type < y.Type
+	})
+	return facts, nil
+}
+
+// decodeFacts adds the specified encoded facts to those of act.
+func (act *Action) decodeFacts(facts []cacheFact) error {
+	types := make(map[string]reflect.Type)
+	for _, f := range act.Analyzer.FactTypes {
+		t := reflect.TypeOf(f)
+		types[t.String()] = t
+	}
+	for _, f := range facts {
+		t, ok := types[f.Type]
+		if !ok {
+			return fmt.Errorf("unknown fact type %s", f.Type)
+		}
+		fact := reflect.New(t.Elem()).Interface().(analysis.Fact)
+		if err := gob.NewDecoder(bytes.NewReader(f.Data)).Decode(fact); err != nil {
+			return err
+		}
+		if f.Object == "" {
+			act.packageFacts[packageFactKey{act.Package.Types, t}] = fact
+		} else {
+			obj, err := objectpath.Object(act.Package.Types, f.Object)
+			if err != nil {
+				return err
+			}
+			act.objectFacts[objectFactKey{obj, t}] = fact
+		}
+	}
+	return nil
+}
+
+// A cachePos is the persistent form of a token.Pos.
+type cachePos struct {
+	File   string // empty for token.NoPos
+	Offset int
+}
+
+type cacheDiagnostic struct {
+	Pos, End       cachePos
+	Category       string
+	Message        string
+	URL            string
+	SuggestedFixes []cacheSuggestedFix
+	Related        []cacheRelated
+}
+
+type cacheSuggestedFix struct {
+	Message   string
+	TextEdits []cacheTextEdit
+}
+
+type cacheTextEdit struct {
+	Pos, End cachePos
+	NewText  []byte
+}
+
+type cacheRelated struct {
+	Pos, End cachePos
+	Message  string
+}
+
+// encodeDiagnostics returns the diagnostics of act in persistent form.
+func (act *Action) encodeDiagnostics() ([]cacheDiagnostic, error) {
+	fset := act.Package.Fset
+	var err error
+	encodePos := func(pos token.Pos) cachePos {
+		if !pos.IsValid() {
+			return cachePos{}
+		}
+		tf := fset.File(pos)
+		if tf == nil {
+			err = fmt.Errorf("no file for position %d", pos)
+			return cachePos{}
+		}
+		return cachePos{tf.Name(), tf.Offset(pos)}
+	}
+	diags := make([]cacheDiagnostic, 0, len(act.Diagnostics))
+	for _, d := range act.Diagnostics {
+		cd := cacheDiagnostic{
+			Pos:      encodePos(d.Pos),
+			End:      encodePos(d.End),
+			Category: d.Category,
+			Message:  d.Message,
+			URL:      d.URL,
+		}
+		for _, fix := range d.SuggestedFixes {
+			cfix := cacheSuggestedFix{Message: fix.Message}
+			for _, edit := range fix.TextEdits {
+				cfix.TextEdits = append(cfix.TextEdits, cacheTextEdit{
+					Pos:     encodePos(edit.Pos),
+					End:     encodePos(edit.End),
+					NewText: edit.NewText,
+				})
+			}
+			cd.SuggestedFixes = append(cd.SuggestedFixes, cfix)
+		}
+		for _, rel := range d.Related {
+			cd.Related = append(cd.Related, cacheRelated{
+				Pos:     encodePos(rel.Pos),
+				End:     encodePos(rel.End),
+				Message: rel.Message,
+			})
+		}
+		diags = append(diags, cd)
+	}
+	return diags, err
+}
+
+// decodeDiagnostics returns the diagnostics of act from their
+// persistent form. Positions in files not already in the package's
+// FileSet, such as assembly files, cause the file to be read and
+// added to it.
+func (act *Action) decodeDiagnostics(diags []cacheDiagnostic) ([]analysis.Diagnostic, error) {
+	fset := act.Package.Fset
+	files := make(map[string]*token.File)
+	for _, f := range act.Package.Syntax {
+		if tf := fset.File(f.FileStart); tf != nil {
+			files[tf.Name()] = tf
+		}
+	}
+	var err error
+	decodePos := func(pos cachePos) token.Pos {
+		if pos.File == "" {
+			return token.NoPos
+		}
+		tf, ok := files[pos.File]
+		if !ok {
+			fset.Iterate(func(f *token.File) bool {
+				if f.Name() == pos.File {
+					tf = f
+					return false
+				}
+				return true
+			})
+			if tf == nil {
+				content, rerr := act.cache.readFile(pos.File)
+				if rerr != nil {
+					err = rerr
+					return token.NoPos
+				}
+				tf = fset.AddFile(pos.File, -1, len(content))
+				tf.SetLinesForContent(content)
+			}
+			files[pos.File] = tf
+		}
+		if pos.Offset > tf.Size() {
+			err = fmt.Errorf("offset %d out of range for file %s", pos.Offset, pos.File)
+			return token.NoPos
+		}
+		return tf.Pos(pos.Offset)
+	}
+	var result []analysis.Diagnostic
+	for _, cd := range diags {
+		d := analysis.Diagnostic{
+			Pos:      decodePos(cd.Pos),
+			End:      decodePos(cd.End),
+			Category: cd.Category,
+			Message:  cd.Message,
+			URL:      cd.URL,
+		}
+		for _, cfix := range cd.SuggestedFixes {
+			fix := analysis.SuggestedFix{Message: cfix.Message}
+			for _, edit := range cfix.TextEdits {
+				fix.TextEdits = append(fix.TextEdits, analysis.TextEdit{
+					Pos:     decodePos(edit.Pos),
+					End:     decodePos(edit.End),
+					NewText: edit.NewText,
+				})
+			}
+			d.SuggestedFixes = append(d.SuggestedFixes, fix)
+		}
+		for _, rel := range cd.Related {
+			d.Related = append(d.Related, analysis.RelatedInformation{
+				Pos:     decodePos(rel.Pos),
+				End:     decodePos(rel.End),
+				Message: rel.Message,
+			})
+		}
+		result = append(result, d)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package checker_test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/internal/testenv"
+	"golang.org/x/tools/internal/testfiles"
+	"golang.org/x/tools/txtar"
+)
+
+// TestCache checks that Options.CacheDir causes actions to be
+// reused across calls to Analyze, and that the cache is invalidated
+// by changes to the package or to the facts of its dependencies.
+func TestCache(t *testing.T) {
+	testenv.NeedsGoPackages(t)
+
+	const src = `
+-- go.mod --
+module example.com
+go 1.21
+
+-- a/a.go --
+package a
+
+func Bad() {}
+
+func good() {}
+
+-- b/b.go --
+package b
+
+import "example.com/a"
+
+func f() { a.Bad() }
+`
+	fs, err := txtar.FS(txtar.Parse([]byte(src)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := testfiles.CopyToTmp(t, fs)
+	cacheDir := t.TempDir()
+
+	// The "bad" analyzer marks functions named Bad with a fact,
+	// and reports calls to marked functions.
+	var (
+		mu  sync.Mutex
+		ran []string // packages analyzed by "bad"
+	)
+	bad := &analysis.Analyzer{
+		Name:      "bad",
+		Doc:       "report calls to bad functions",
+		Requires:  []*analysis.Analyzer{inspect.Analyzer},
+		FactTypes: []analysis.Fact{new(isBad)},
+		Run: func(pass *analysis.Pass) (any, error) {
+			mu.Lock()
+			ran = append(ran, pass.Pkg.Path())
+			mu.Unlock()
+
+			for _, name := range pass.Pkg.Scope().Names() {
+				if name == "Bad" {
+					pass.ExportObjectFact(pass.Pkg.Scope().Lookup(name), new(isBad))
+				}
+			}
+			inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+			inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+				call := n.(*ast.CallExpr)
+				if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+					if fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func); ok && pass.ImportObjectFact(fn, new(isBad)) {
+						pass.ReportRangef(call, "call of bad function %s", fn.Name())
+					}
+				}
+			})
+			return nil, nil
+		},
+	}
+
+	// analyze analyzes package b, and returns the list of
+	// analyzed packages and the diagnostics.
+	analyze := func() ([]string, []string) {
+		t.Helper()
+		cfg := &packages.Config{Mode: packages.LoadAllSyntax, Dir: dir}
+		pkgs, err := packages.Load(cfg, "example.com/b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ran = nil
+		graph, err := checker.Analyze([]*analysis.Analyzer{bad}, pkgs, &checker.Options{CacheDir: cacheDir})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var diags []string
+		for act := range graph.All() {
+			if act.Err != nil {
+				t.Fatalf("%s: %v", act, act.Err)
+			}
+			for _, d := range act.Diagnostics {
+				posn := act.Package.Fset.Position(d.Pos)
+				diags = append(diags, fmt.Sprintf("%s:%d:%d: %s", filepath.Base(posn.Filename), posn.Line, posn.Column, d.Message))
+			}
+		}
+		sort.Strings(ran)
+		return ran, diags
+	}
+	check := func(name string, wantRan []string) {
+		t.Helper()
+		gotRan, gotDiags := analyze()
+		if !reflect.DeepEqual(gotRan, wantRan) {
+			t.Errorf("%s: analyzed packages %q, want %q", name, gotRan, wantRan)
+		}
+		if want := []string{"b.go:5:12: call of bad function Bad"}; !reflect.DeepEqual(gotDiags, want) {
+			t.Errorf("%s: got diagnostics %q, want %q", name, gotDiags, want)
+		}
+	}
+	edit := func(filename, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, filename), []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	check("cold", []string{"example.com/a", "example.com/b"})
+	check("warm", nil)
+
+	// A change to b invalidates only b.
+	edit("b/b.go", "package b\n\nimport \"example.com/a\"\n\nfunc f() { a.Bad() }\n\n// comment\n")
+	check("edit b", []string{"example.com/b"})
+
+	// A change to a that affects neither its API nor its facts
+	// invalidates only a.
+	edit("a/a.go", "package a\n\nfunc Bad() {}\n\nfunc good() { println() }\n")
+	check("edit a", []string{"example.com/a"})
+
+	check("warm again", nil)
+}
+
+type isBad struct{}
+
+func (*isBad) AFact()         {}
+func (*isBad) String() string { return "isBad" }
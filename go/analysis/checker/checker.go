@@ -30,6 +30,8 @@ package checker
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
 	"encoding/gob"
 	"fmt"
 	"go/types"
@@ -37,9 +39,11 @@ import (
 	"log"
 	"os"
 	"reflect"
+	"runtime/metrics"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/tools/go/analysis"
@@ -56,6 +60,37 @@ type Options struct {
 	SanityCheck bool      // check fact encoding is ok and deterministic
 	FactLog     io.Writer // if non-nil, log each exported fact to it
 
+	// Concurrency, if positive, is the maximum number of actions
+	// executed in parallel, and bounds the number of goroutines
+	// used to execute them. Otherwise parallelism is unbounded
+	// (unless Sequential is set).
+	Concurrency int
+
+	// OnAction, if non-nil, is called once for each action in the
+	// graph as soon as it completes, successfully or not, allowing
+	// the caller to process results as they become available.
+	// Calls are serialized, and complete before Analyze returns.
+	// The action's Result is valid during the call even if the
+	// action is not a root.
+	OnAction func(*Action)
+
+	// CacheDir, if non-empty, is the name of a directory in which
+	// the facts and diagnostics of each action are saved, so that
+	// subsequent runs, perhaps by another process, need not
+	// recompute them if the package, its dependencies, and the
+	// analyzer are unchanged. The directory is created if needed.
+	//
+	// An action whose outputs are obtained from the cache has a
+	// nil Result, and its prerequisite analyzers may not be run
+	// at all. However, an action whose Result is required by
+	// another action is always executed.
+	//
+	// Entries are never removed from the directory, and its size
+	// is not bounded, so long-lived clients should periodically
+	// delete stale entries, for example by removing files that
+	// have not been modified in some time.
+	CacheDir string
+
 	// TODO(adonovan): expose ReadFile so that an Overlay specified
 	// in the [packages.Config] can be communicated via
 	// Pass.ReadFile to each Analyzer.
@@ -127,14 +162,32 @@ type Action struct {
 	Result      any   // computed result of Analyzer.run, if any (and if IsRoot)
 	Err         error // error result of Analyzer.run
 	Diagnostics []analysis.Diagnostic
-	Duration    time.Duration // execution time of this step
+
+	// Duration is the execution time of this step, excluding time
+	// spent waiting for dependencies or for a free slot (see
+	// Options.Concurrency). AllocBytes is the number of bytes
+	// allocated during this step. As allocation is measured across
+	// the whole process, AllocBytes is recorded only if actions
+	// are executed one at a time (Options.Sequential, or
+	// Options.Concurrency of 1); otherwise it is zero.
+	Duration   time.Duration
+	AllocBytes uint64
 
 	opts         *Options
+	sched        *scheduler
 	once         sync.Once
 	pass         *analysis.Pass
 	objectFacts  map[objectFactKey]analysis.Fact
 	packageFacts map[packageFactKey]analysis.Fact
 	inputs       map[*analysis.Analyzer]any
+
+	// Persistent cache state; see cache.go.
+	cache     *cache            // nil unless Options.CacheDir is set
+	needed    bool              // Result is required by another action
+	keyOnce   sync.Once         // for cacheKey
+	key       [sha256.Size]byte // cache key
+	keyErr    error             // error computing cache key
+	factsHash [sha256.Size]byte // hash of facts visible to dependents
 }
 
 func (act *Action) String() string {
@@ -142,6 +195,12 @@ func (act *Action) String() string {
 }
 
 // Analyze runs the specified analyzers on the initial packages.
+// It is equivalent to [AnalyzeContext] with a background context.
+func Analyze(analyzers []*analysis.Analyzer, pkgs []*packages.Package, opts *Options) (*Graph, error) {
+	return AnalyzeContext(context.Background(), analyzers, pkgs, opts)
+}
+
+// AnalyzeContext runs the specified analyzers on the initial packages.
 //
 // The initial packages and all dependencies must have been loaded
 // using the [packages.LoadAllSyntax] flag, Analyze may need to run
@@ -152,7 +211,14 @@ func (act *Action) String() string {
 // item per (a, p) in the cross-product of analyzers and pkgs.
 //
 // If opts is nil, it is equivalent to new(Options).
-func Analyze(analyzers []*analysis.Analyzer, pkgs []*packages.Package, opts *Options) (*Graph, error) {
+//
+// If the context is cancelled, actions that have not yet started
+// fail with the context's error, and AnalyzeContext returns the
+// graph, including the results of the actions that completed,
+// along with that error once all actions in progress have completed.
+// If the context is cancelled only after every action has started,
+// no action fails and AnalyzeContext reports no error.
+func AnalyzeContext(ctx context.Context, analyzers []*analysis.Analyzer, pkgs []*packages.Package, opts *Options) (*Graph, error) {
 	if opts == nil {
 		opts = new(Options)
 	}
@@ -172,16 +238,37 @@ func Analyze(analyzers []*analysis.Analyzer, pkgs []*packages.Package, opts *Opt
 	}
 	actions := make(map[key]*Action)
 
+	sched := &scheduler{
+		ctx:         ctx,
+		onAction:    opts.OnAction,
+		measureHeap: opts.Sequential || opts.Concurrency == 1,
+	}
+	if opts.Concurrency > 0 {
+		sched.sem = make(chan struct{}, opts.Concurrency)
+		sched.workers = make(chan struct{}, opts.Concurrency)
+	}
+
+	var cache *cache
+	if opts.CacheDir != "" {
+		readFile := os.ReadFile
+		if opts.readFile != nil {
+			readFile = opts.readFile
+		}
+		cache = newCache(opts.CacheDir, readFile)
+	}
+
 	var mkAction func(a *analysis.Analyzer, pkg *packages.Package) *Action
 	mkAction = func(a *analysis.Analyzer, pkg *packages.Package) *Action {
 		k := key{a, pkg}
 		act, ok := actions[k]
 		if !ok {
-			act = &Action{Analyzer: a, Package: pkg, opts: opts}
+			act = &Action{Analyzer: a, Package: pkg, opts: opts, sched: sched, cache: cache}
 
 			// Add a dependency on each required analyzers.
 			for _, req := range a.Requires {
-				act.Deps = append(act.Deps, mkAction(req, pkg))
+				dep := mkAction(req, pkg)
+				dep.needed = true
+				act.Deps = append(act.Deps, dep)
 			}
 
 			// An analysis that consumes/produces facts
@@ -226,7 +313,11 @@ func Analyze(analyzers []*analysis.Analyzer, pkgs []*packages.Package, opts *Opt
 		}
 	}
 
-	return &Graph{Roots: roots}, nil
+	graph := &Graph{Roots: roots}
+	if err := sched.err.Load(); err != nil {
+		return graph, *err
+	}
+	return graph, nil
 }
 
 func init() {
@@ -256,25 +347,117 @@ func execAll(actions []*Action) {
 		if act.opts.Sequential {
 			work(act)
 		} else {
-			go work(act)
+			act.sched.start(func() { work(act) })
 		}
 	}
 	wg.Wait()
 }
 
+// A scheduler holds the state shared by the actions of one call to
+// AnalyzeContext.
+type scheduler struct {
+	ctx         context.Context
+	sem         chan struct{} // counting semaphore of executing actions; nil => unbounded
+	workers     chan struct{} // counting semaphore of goroutines; nil => unbounded
+	measureHeap bool          // record Action.AllocBytes
+	mu          sync.Mutex    // serializes calls to onAction
+	onAction    func(*Action) // optional
+
+	err atomic.Pointer[error] // context error of the first action skipped due to cancellation
+}
+
+// start calls f in a new goroutine, or, if the limit on the number
+// of goroutines has been reached, in the calling goroutine.
+//
+// The goroutines only wait for dependencies, which are executed
+// independently, so running f in the calling goroutine cannot
+// cause a deadlock.
+func (s *scheduler) start(f func()) {
+	if s.workers == nil {
+		go f()
+		return
+	}
+	select {
+	case s.workers <- struct{}{}:
+		go func() {
+			defer func() { <-s.workers }()
+			f()
+		}()
+	default:
+		f()
+	}
+}
+
+// acquire waits for a free slot in which to execute an action,
+// and returns a function to release it, or an error if the
+// context was cancelled.
+func (s *scheduler) acquire() (release func(), err error) {
+	if err := s.ctx.Err(); err != nil {
+		return nil, s.cancelled(err)
+	}
+	if s.sem == nil {
+		return func() {}, nil
+	}
+	select {
+	case s.sem <- struct{}{}:
+		return func() { <-s.sem }, nil
+	case <-s.ctx.Done():
+		return nil, s.cancelled(s.ctx.Err())
+	}
+}
+
+// cancelled records that an action was skipped because the context
+// was cancelled, and returns the context's error.
+func (s *scheduler) cancelled(err error) error {
+	s.err.CompareAndSwap(nil, &err)
+	return err
+}
+
+// done reports the completion of an action to the client.
+func (s *scheduler) done(act *Action) {
+	if s.onAction != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.onAction(act)
+	}
+}
+
 func (act *Action) exec() { act.once.Do(act.execOnce) }
 
 func (act *Action) execOnce() {
+	defer act.sched.done(act)
+
+	// If the cache is enabled, try to obtain the outputs from it.
+	// This requires analyzing only the vertical dependencies.
+	if act.cache != nil && !act.needed && act.execCached() {
+		return
+	}
+
 	// Analyze dependencies.
 	execAll(act.Deps)
 
-	// Record time spent in this node but not its dependencies.
+	release, err := act.sched.acquire()
+	if err != nil {
+		act.Err = err // cancelled
+		return
+	}
+	defer release()
+
+	// Record time and allocation in this node but not its dependencies.
 	// In parallel mode, due to GC/scheduler contention, the
 	// time is 5x higher than in sequential mode, even with a
 	// semaphore limiting the number of threads here.
 	// So use -debug=tp.
 	t0 := time.Now()
 	defer func() { act.Duration = time.Since(t0) }()
+	if act.sched.measureHeap {
+		alloc0 := allocBytes()
+		defer func() { act.AllocBytes = allocBytes() - alloc0 }()
+	}
+
+	if act.cache != nil {
+		defer act.cache.put(act)
+	}
 
 	// Report an error if any dependency failed.
 	var failed []string
@@ -392,6 +575,17 @@ func (act *Action) execOnce() {
 	pass.ExportPackageFact = nil
 }
 
+// allocBytes returns the cumulative number of bytes allocated
+// by the process.
+func allocBytes() uint64 {
+	sample := []metrics.Sample{{Name: "/gc/heap/allocs:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0 // unsupported
+	}
+	return sample[0].Value.Uint64()
+}
+
 // inheritFacts populates act.facts with
 // those it obtains from its dependency, dep.
 func inheritFacts(act, dep *Action) {
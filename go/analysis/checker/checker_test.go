@@ -0,0 +1,188 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package checker_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/internal/testenv"
+	"golang.org/x/tools/internal/testfiles"
+	"golang.org/x/tools/txtar"
+)
+
+// sink prevents allocations from being optimized away.
+var sink []byte
+
+// TestAnalyzeContext checks the streaming, bounded concurrency,
+// statistics, and cancellation features of AnalyzeContext,
+// with and without the cache.
+func TestAnalyzeContext(t *testing.T) {
+	testenv.NeedsGoPackages(t)
+
+	const src = `
+-- go.mod --
+module example.com
+go 1.21
+
+-- a/a.go --
+package a
+
+-- b/b.go --
+package b
+
+import _ "example.com/a"
+
+-- c/c.go --
+package c
+
+import _ "example.com/b"
+`
+	fs, err := txtar.FS(txtar.Parse([]byte(src)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := testfiles.CopyToTmp(t, fs)
+	cfg := &packages.Config{Mode: packages.LoadAllSyntax, Dir: dir}
+	pkgs, err := packages.Load(cfg, "example.com/c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The analyzer produces facts, so runs on every package,
+	// and allocates a megabyte each time.
+	const size = 1 << 20
+	var running, maxRunning, maxGoroutines atomic.Int32
+	a := &analysis.Analyzer{
+		Name:      "a",
+		Doc:       "doc",
+		FactTypes: []analysis.Fact{new(isBad)},
+		Run: func(pass *analysis.Pass) (any, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			if n > maxRunning.Load() {
+				maxRunning.Store(n)
+			}
+			if n := int32(runtime.NumGoroutine()); n > maxGoroutines.Load() {
+				maxGoroutines.Store(n)
+			}
+			sink = make([]byte, size)
+			return nil, nil
+		},
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		var streamed []*checker.Action
+		goroutines := int32(runtime.NumGoroutine())
+		graph, err := checker.AnalyzeContext(context.Background(), []*analysis.Analyzer{a}, pkgs, &checker.Options{
+			Concurrency: 1,
+			OnAction:    func(act *checker.Action) { streamed = append(streamed, act) },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var all []*checker.Action
+		for act := range graph.All() {
+			all = append(all, act)
+		}
+		if len(streamed) != 3 || len(all) != 3 {
+			t.Fatalf("streamed %d actions, graph has %d; want 3", len(streamed), len(all))
+		}
+		// Dependencies complete first.
+		for i, pkg := range []string{"example.com/a", "example.com/b", "example.com/c"} {
+			if got := streamed[i].Package.PkgPath; got != pkg {
+				t.Errorf("action %d is for package %s, want %s", i, got, pkg)
+			}
+		}
+		if n := maxRunning.Load(); n != 1 {
+			t.Errorf("%d actions ran concurrently, want 1", n)
+		}
+		if n := maxGoroutines.Load() - goroutines; n > 1 {
+			t.Errorf("AnalyzeContext started %d goroutines, want at most 1", n)
+		}
+		for _, act := range streamed {
+			if act.Err != nil {
+				t.Errorf("%s failed: %v", act, act.Err)
+			}
+			if act.AllocBytes < size {
+				t.Errorf("%s allocated %d bytes, want at least %d", act, act.AllocBytes, size)
+			}
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var errs []error
+		graph, err := checker.AnalyzeContext(ctx, []*analysis.Analyzer{a}, pkgs, &checker.Options{
+			Concurrency: 1,
+			OnAction: func(act *checker.Action) {
+				errs = append(errs, act.Err)
+				cancel() // after the first action
+			},
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("AnalyzeContext returned %v, want %v", err, context.Canceled)
+		}
+		if len(errs) != 3 || errs[0] != nil || !errors.Is(errs[1], context.Canceled) {
+			t.Errorf("got action errors %v, want [nil, context canceled, ...]", errs)
+		}
+		// The partial graph is returned along with the error.
+		if graph == nil || len(graph.Roots) != 1 || !errors.Is(graph.Roots[0].Err, context.Canceled) {
+			t.Errorf("got graph %v, want one whose root was canceled", graph)
+		}
+	})
+
+	t.Run("cancel after completion", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		n := 0
+		graph, err := checker.AnalyzeContext(ctx, []*analysis.Analyzer{a}, pkgs, &checker.Options{
+			Concurrency: 1,
+			OnAction: func(act *checker.Action) {
+				if n++; n == 3 {
+					cancel() // after the last action
+				}
+			},
+		})
+		if err != nil {
+			t.Errorf("AnalyzeContext returned %v, want nil since no action was skipped", err)
+		}
+		if graph == nil || graph.Roots[0].Err != nil {
+			t.Errorf("got graph %v, want one whose root succeeded", graph)
+		}
+	})
+
+	t.Run("cache", func(t *testing.T) {
+		// Populate the cache.
+		opts := &checker.Options{Concurrency: 1, CacheDir: t.TempDir()}
+		if _, err := checker.AnalyzeContext(context.Background(), []*analysis.Analyzer{a}, pkgs, opts); err != nil {
+			t.Fatal(err)
+		}
+
+		// Cache hits are subject to cancellation too.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var errs []error
+		opts.OnAction = func(act *checker.Action) { errs = append(errs, act.Err) }
+		if _, err := checker.AnalyzeContext(ctx, []*analysis.Analyzer{a}, pkgs, opts); !errors.Is(err, context.Canceled) {
+			t.Errorf("AnalyzeContext returned %v, want %v", err, context.Canceled)
+		}
+		for _, err := range errs {
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("got action error %v, want %v", err, context.Canceled)
+			}
+		}
+		if len(errs) != 3 {
+			t.Errorf("got %d actions, want 3", len(errs))
+		}
+	})
+}
@@ -86,3 +86,28 @@ func writeJSONDiagnostics(w io.Writer, roots []*Action) error {
 	})
 	return tree.Print(w)
 }
+
+// PrintSARIF emits diagnostics to w as a SARIF 2.1.0 log, the format
+// used by code-scanning tools. Each analyzer of a root action is
+// described by a rule. Diagnostics are shown only for the root nodes,
+// but errors (if any) are shown for all dependencies.
+func (g *Graph) PrintSARIF(w io.Writer) error {
+	return writeSARIFDiagnostics(w, g.Roots)
+}
+
+func writeSARIFDiagnostics(w io.Writer, roots []*Action) error {
+	var analyzers []*analysis.Analyzer
+	for _, act := range roots {
+		analyzers = append(analyzers, act.Analyzer)
+	}
+	sarif := analysisflags.NewSARIFLog(analyzers)
+	forEach(roots, func(act *Action) error {
+		var diags []analysis.Diagnostic
+		if act.IsRoot {
+			diags = act.Diagnostics
+		}
+		sarif.Add(act.Package.Fset, act.Package.ID, act.Analyzer, diags, act.Err)
+		return nil
+	})
+	return sarif.Print(w)
+}
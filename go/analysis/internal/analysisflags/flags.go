@@ -24,8 +24,9 @@ import (
 
 // flags common to all {single,multi,unit}checkers.
 var (
-	JSON    = false // -json
-	Context = -1    // -c=N: if N>0, display offending line plus N lines of context
+	JSON    = false  // -json
+	Format  = "text" // -format: "text", "json", or "sarif"; -json implies "json"
+	Context = -1     // -c=N: if N>0, display offending line plus N lines of context
 )
 
 // Parse creates a flag for each of the analyzer's flags,
@@ -73,7 +74,10 @@ func Parse(analyzers []*analysis.Analyzer, multi bool) []*analysis.Analyzer {
 
 	// flags common to all checkers
 	flag.BoolVar(&JSON, "json", JSON, "emit JSON output")
+	flag.StringVar(&Format, "format", Format, `output format: "text", "json", or "sarif"`)
 	flag.IntVar(&Context, "c", Context, `display offending line with this many lines of context`)
+	flag.StringVar(&Baseline, "baseline", Baseline, "report only findings not recorded in this baseline file")
+	flag.BoolVar(&UpdateBaseline, "update-baseline", UpdateBaseline, "with -baseline, record all current findings in the baseline file")
 
 	// Add shims for legacy vet flags to enable existing
 	// scripts that run vet to continue to work.
@@ -96,6 +100,26 @@ func Parse(analyzers []*analysis.Analyzer, multi bool) []*analysis.Analyzer {
 		os.Exit(0)
 	}
 
+	// Reconcile -json and -format.
+	switch Format {
+	case "text":
+		if JSON {
+			Format = "json"
+		}
+	case "json":
+		JSON = true
+	case "sarif":
+		if JSON {
+			log.Fatalf("-json and -format=sarif are mutually exclusive")
+		}
+	default:
+		log.Fatalf("invalid -format=%s (want text, json, or sarif)", Format)
+	}
+
+	if UpdateBaseline && Baseline == "" {
+		log.Fatalf("-update-baseline requires -baseline=file")
+	}
+
 	everything := expand(analyzers)
 
 	// If any -NAME flag is true,  run only those analyzers. Otherwise,
@@ -170,7 +194,9 @@ func printFlags() {
 		// flags or fix as these have no effect on unitchecker
 		// (as invoked by 'go vet').
 		switch f.Name {
-		case "debug", "cpuprofile", "memprofile", "trace", "fix":
+		// Nor -update-baseline, as concurrent vet processes
+		// cannot safely update a common file.
+		case "debug", "cpuprofile", "memprofile", "trace", "fix", "update-baseline":
 			return
 		}
 
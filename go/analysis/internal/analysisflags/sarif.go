@@ -0,0 +1,303 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysisflags
+
+// This file defines the SARIF output format, used by code-scanning
+// tools. See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// A SARIFLog accumulates the results of a run of analyzers
+// for printing as a SARIF 2.1.0 log.
+//
+// Each analyzer is described by a rule, and each diagnostic by a
+// result, whose suggested fixes become SARIF fixes. Errors are
+// reported as tool execution notifications.
+type SARIFLog struct {
+	run   sarifRun
+	rules map[string]int // index of each rule, by analyzer name
+	seen  map[string]bool
+	files map[string][]byte // cache of file contents, for columns
+}
+
+// NewSARIFLog returns a log whose tool is the current executable,
+// with a rule for each analyzer.
+func NewSARIFLog(analyzers []*analysis.Analyzer) *SARIFLog {
+	s := &SARIFLog{
+		run: sarifRun{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           filepath.Base(os.Args[0]),
+				InformationURI: "https://pkg.go.dev/golang.org/x/tools/go/analysis",
+				Rules:          []sarifRule{},
+			}},
+			Invocations: []sarifInvocation{{ExecutionSuccessful: true}},
+			ColumnKind:  "unicodeCodePoints",
+			Results:     []sarifResult{},
+		},
+		rules: make(map[string]int),
+		seen:  make(map[string]bool),
+		files: make(map[string][]byte),
+	}
+	for _, a := range analyzers {
+		if _, ok := s.rules[a.Name]; ok {
+			continue
+		}
+		s.rules[a.Name] = len(s.run.Tool.Driver.Rules)
+		short, _, _ := strings.Cut(a.Doc, "\n")
+		s.run.Tool.Driver.Rules = append(s.run.Tool.Driver.Rules, sarifRule{
+			ID:               a.Name,
+			ShortDescription: sarifMessage{Text: short},
+			FullDescription:  sarifMessage{Text: a.Doc},
+			HelpURI:          a.URL,
+		})
+	}
+	return s
+}
+
+// Add adds the result of analyzer a on package 'id'.
+// The result is either a list of diagnostics or an error.
+//
+// Diagnostics already added at the same position, such as those in
+// files that belong to both a package and its test variant, are
+// ignored.
+func (s *SARIFLog) Add(fset *token.FileSet, id string, a *analysis.Analyzer, diags []analysis.Diagnostic, err error) {
+	if err != nil {
+		s.run.Invocations[0].ExecutionSuccessful = false
+		s.run.Invocations[0].Notifications = append(s.run.Invocations[0].Notifications, sarifNotification{
+			Level:   "error",
+			Message: sarifMessage{Text: fmt.Sprintf("%s: %s: %v", id, a.Name, err)},
+		})
+		return
+	}
+	var index *int // nil if a is not a root analyzer
+	if i, ok := s.rules[a.Name]; ok {
+		index = &i
+	}
+	for _, diag := range diags {
+		result := sarifResult{
+			RuleID:    a.Name,
+			RuleIndex: index,
+			Level:     "warning",
+			Message:   sarifMessage{Text: diag.Message},
+			Locations: []sarifLocation{},
+		}
+		if loc := s.location(fset, diag.Pos, diag.End); loc != nil {
+			result.Locations = append(result.Locations, sarifLocation{PhysicalLocation: loc})
+		}
+		data, _ := json.Marshal(result)
+		if s.seen[string(data)] {
+			continue // duplicate
+		}
+		s.seen[string(data)] = true
+
+		for i, r := range diag.Related {
+			result.RelatedLocations = append(result.RelatedLocations, sarifLocation{
+				ID:               i + 1,
+				PhysicalLocation: s.location(fset, r.Pos, r.End),
+				Message:          &sarifMessage{Text: r.Message},
+			})
+		}
+		for _, fix := range diag.SuggestedFixes {
+			// Group the edits by file, in order of appearance.
+			var changes []sarifArtifactChange
+			files := make(map[string]int)
+			for _, edit := range fix.TextEdits {
+				loc := s.location(fset, edit.Pos, edit.End)
+				if loc == nil {
+					continue // invalid edit
+				}
+				i, ok := files[loc.ArtifactLocation.URI]
+				if !ok {
+					i = len(changes)
+					files[loc.ArtifactLocation.URI] = i
+					changes = append(changes, sarifArtifactChange{ArtifactLocation: loc.ArtifactLocation})
+				}
+				changes[i].Replacements = append(changes[i].Replacements, sarifReplacement{
+					DeletedRegion:   loc.Region,
+					InsertedContent: &sarifContent{Text: string(edit.NewText)},
+				})
+			}
+			result.Fixes = append(result.Fixes, sarifFix{
+				Description:     sarifMessage{Text: fix.Message},
+				ArtifactChanges: changes,
+			})
+		}
+		s.run.Results = append(s.run.Results, result)
+	}
+}
+
+// location returns the SARIF location of the range [pos, end),
+// or nil if pos is not valid, such as [token.NoPos].
+func (s *SARIFLog) location(fset *token.FileSet, pos, end token.Pos) *sarifPhysicalLocation {
+	start := fset.Position(pos)
+	if !start.IsValid() {
+		return nil
+	}
+	finish := fset.Position(end)
+	if !finish.IsValid() {
+		finish = start
+	}
+	return &sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: fileURI(start.Filename)},
+		Region: sarifRegion{
+			StartLine:   start.Line,
+			StartColumn: s.column(start),
+			EndLine:     finish.Line,
+			EndColumn:   s.column(finish),
+		},
+	}
+}
+
+// column returns the 1-based column of posn in Unicode code points,
+// or in bytes if the file cannot be read.
+func (s *SARIFLog) column(posn token.Position) int {
+	content, ok := s.files[posn.Filename]
+	if !ok {
+		content, _ = os.ReadFile(posn.Filename)
+		s.files[posn.Filename] = content
+	}
+	start := posn.Offset - (posn.Column - 1)
+	if content == nil || start < 0 || posn.Offset > len(content) {
+		return posn.Column
+	}
+	return utf8.RuneCount(content[start:posn.Offset]) + 1
+}
+
+// fileURI returns the file URI of the named file.
+func fileURI(filename string) string {
+	if abs, err := filepath.Abs(filename); err == nil {
+		filename = abs
+	}
+	path := filepath.ToSlash(filename)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // e.g. C:/foo on Windows
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// Print prints the log in JSON form.
+func (s *SARIFLog) Print(out io.Writer) error {
+	data, err := json.MarshalIndent(sarifDocument{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{s.run},
+	}, "", "\t")
+	if err != nil {
+		log.Panicf("internal error: JSON marshaling failed: %v", err)
+	}
+	_, err = fmt.Fprintf(out, "%s\n", data)
+	return err
+}
+
+// The types below define the subset of the SARIF 2.1.0 schema used by
+// SARIFLog.
+
+type sarifDocument struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	ColumnKind  string            `json:"columnKind"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	FullDescription  sarifMessage `json:"fullDescription"`
+	HelpURI          string       `json:"helpUri,omitempty"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful bool                `json:"executionSuccessful"`
+	Notifications       []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID           string          `json:"ruleId"`
+	RuleIndex        *int            `json:"ruleIndex,omitempty"`
+	Level            string          `json:"level"`
+	Message          sarifMessage    `json:"message"`
+	Locations        []sarifLocation `json:"locations"`
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+	Fixes            []sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifLocation struct {
+	ID               int                    `json:"id,omitempty"`
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	Message          *sarifMessage          `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion   `json:"deletedRegion"`
+	InsertedContent *sarifContent `json:"insertedContent,omitempty"`
+}
+
+type sarifContent struct {
+	Text string `json:"text"`
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysisflags_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/internal/analysisflags"
+)
+
+func TestSARIF(t *testing.T) {
+	const src = "package p\n\nvar s = \"héllo\" + x\n"
+	filename := filepath.Join(t.TempDir(), "p.go")
+	if err := os.WriteFile(filename, []byte(src), 0666); err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	file := fset.AddFile(filename, -1, len(src))
+	file.SetLinesForContent([]byte(src))
+	pos := func(substr string) token.Pos {
+		return file.Pos(strings.Index(src, substr))
+	}
+
+	a := &analysis.Analyzer{
+		Name: "concat",
+		Doc:  "check for concatenation\n\nThe concat analyzer reports concatenations.",
+		URL:  "https://example.com/concat",
+	}
+	b := &analysis.Analyzer{Name: "other", Doc: "other"}
+	diag := analysis.Diagnostic{
+		Pos:     pos("+ x"),
+		End:     pos("+ x") + 3,
+		Message: "concatenation of x",
+		Related: []analysis.RelatedInformation{{Pos: pos("s ="), Message: "s declared here"}},
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message:   "Remove x",
+			TextEdits: []analysis.TextEdit{{Pos: pos(" + x"), End: pos(" + x") + 4}},
+		}},
+	}
+
+	log := analysisflags.NewSARIFLog([]*analysis.Analyzer{a, b, a})
+	log.Add(fset, "p", a, []analysis.Diagnostic{diag}, nil)
+	log.Add(fset, "p.test", a, []analysis.Diagnostic{diag}, nil) // duplicate
+	log.Add(fset, "q", b, nil, errors.New("oops"))
+	log.Add(fset, "r", b, []analysis.Diagnostic{{Pos: token.NoPos, Message: "nowhere"}}, nil)
+	c := &analysis.Analyzer{Name: "dep", Doc: "not a root"}
+	log.Add(fset, "r", c, []analysis.Diagnostic{{Pos: pos("s ="), Message: "no rule"}}, nil)
+	var buf bytes.Buffer
+	if err := log.Print(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	type region struct{ StartLine, StartColumn, EndLine, EndColumn int }
+	type location struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct{ URI string }
+			Region           region
+		}
+		Message *struct{ Text string }
+	}
+	var got struct {
+		Version string
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID               string
+						ShortDescription struct{ Text string }
+						HelpURI          string
+					}
+				}
+			}
+			Invocations []struct {
+				ExecutionSuccessful        bool
+				ToolExecutionNotifications []struct{ Message struct{ Text string } }
+			}
+			Results []struct {
+				RuleID           string
+				RuleIndex        *int
+				Message          struct{ Text string }
+				Locations        []location
+				RelatedLocations []location
+				Fixes            []struct {
+					Description     struct{ Text string }
+					ArtifactChanges []struct {
+						Replacements []struct {
+							DeletedRegion   region
+							InsertedContent struct{ Text string }
+						}
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.Bytes())
+	}
+
+	if got.Version != "2.1.0" || len(got.Runs) != 1 {
+		t.Fatalf("got version %q with %d runs, want 2.1.0 with 1 run", got.Version, len(got.Runs))
+	}
+	run := got.Runs[0]
+	rules := run.Tool.Driver.Rules
+	if len(rules) != 2 || rules[0].ID != "concat" || rules[0].ShortDescription.Text != "check for concatenation" || rules[0].HelpURI != a.URL || rules[1].ID != "other" {
+		t.Errorf("got rules %+v", rules)
+	}
+	if inv := run.Invocations[0]; inv.ExecutionSuccessful || len(inv.ToolExecutionNotifications) != 1 || inv.ToolExecutionNotifications[0].Message.Text != "q: other: oops" {
+		t.Errorf("got invocation %+v", inv)
+	}
+	if len(run.Results) != 3 {
+		t.Fatalf("got %d results, want 3", len(run.Results))
+	}
+	// A diagnostic without a position has no location,
+	// rather than one at line 0.
+	if res := run.Results[1]; res.Message.Text != "nowhere" || len(res.Locations) != 0 {
+		t.Errorf("got result %+v, want one without locations", res)
+	}
+	// A result of an analyzer without a rule has no rule index.
+	if res := run.Results[2]; res.RuleID != "dep" || res.RuleIndex != nil {
+		t.Errorf("got result %+v, want one without a rule index", res)
+	}
+	res := run.Results[0]
+	if res.RuleID != "concat" || res.RuleIndex == nil || *res.RuleIndex != 0 || res.Message.Text != diag.Message {
+		t.Errorf("got result %+v", res)
+	}
+	loc := res.Locations[0].PhysicalLocation
+	if !strings.HasPrefix(loc.ArtifactLocation.URI, "file:///") || !strings.HasSuffix(loc.ArtifactLocation.URI, "/p.go") {
+		t.Errorf("got URI %q", loc.ArtifactLocation.URI)
+	}
+	// Columns count code points, not bytes: "é" is one column.
+	if want := (region{3, 17, 3, 20}); loc.Region != want {
+		t.Errorf("got region %+v, want %+v", loc.Region, want)
+	}
+	if len(res.RelatedLocations) != 1 || res.RelatedLocations[0].Message.Text != "s declared here" || res.RelatedLocations[0].PhysicalLocation.Region.StartColumn != 5 {
+		t.Errorf("got related locations %+v", res.RelatedLocations)
+	}
+	if len(res.Fixes) != 1 || res.Fixes[0].Description.Text != "Remove x" {
+		t.Fatalf("got fixes %+v", res.Fixes)
+	}
+	repl := res.Fixes[0].ArtifactChanges[0].Replacements
+	if want := (region{3, 16, 3, 20}); len(repl) != 1 || !reflect.DeepEqual(repl[0].DeletedRegion, want) || repl[0].InsertedContent.Text != "" {
+		t.Errorf("got replacements %+v, want deletion of %+v", repl, want)
+	}
+}
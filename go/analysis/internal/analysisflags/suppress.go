@@ -0,0 +1,338 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysisflags
+
+// This file defines the suppression of diagnostics by in-source
+// directives and by a baseline file, common to all drivers.
+//
+// A directive of the form
+//
+//	//lint:ignore analyzer[,analyzer...] reason
+//
+// suppresses diagnostics from the named analyzers that start on the
+// same line as the directive or on the following line. A directive
+// of the form
+//
+//	//lint:file-ignore analyzer[,analyzer...] reason
+//
+// suppresses them throughout the file. The reason is mandatory; a
+// directive without one is reported and has no effect.
+//
+// A baseline file records a set of existing findings, and causes
+// them to be suppressed, so that only new findings are reported. Each
+// finding is identified by a fingerprint derived from its analyzer,
+// package, file name, message, and the text of the source lines it
+// spans with spaces normalized, but not its line number, so that
+// unrelated edits do not invalidate the baseline.
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// flags controlling suppression of diagnostics
+var (
+	Baseline       = ""    // -baseline=file: report only findings not in file
+	UpdateBaseline = false // -update-baseline: write findings to baseline file
+)
+
+// A Suppressor filters diagnostics according to the suppression
+// directives in the source and the baseline file, if any. It records
+// which suppressions were used so that stale ones may be reported.
+type Suppressor struct {
+	baseline []BaselineEntry
+	allowed  map[string]int          // number of findings allowed by baseline, by fingerprint
+	found    map[string]int          // number of distinct findings, by fingerprint
+	seen     map[string]bool         // distinct findings, by fingerprint and position
+	findings []BaselineEntry         // current findings not suppressed by directives
+	files    map[string][]*directive // directives, by file name
+	ran      map[string]bool         // keys of (file, analyzer) and (package, base name, analyzer) analyzed
+	contents map[string][]byte       // cache of file contents
+}
+
+// A BaselineEntry records one finding in a baseline file.
+type BaselineEntry struct {
+	Analyzer    string `json:"analyzer"`
+	Package     string `json:"package"`
+	File        string `json:"file"` // base name
+	Message     string `json:"message"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// baselineFile is the JSON form of a baseline file.
+type baselineFile struct {
+	Findings []BaselineEntry `json:"findings"`
+}
+
+// A directive is a //lint:ignore or //lint:file-ignore comment.
+type directive struct {
+	posn      token.Position
+	analyzers []string
+	reason    string
+	fileWide  bool
+	used      map[string]bool // analyzers whose diagnostics it suppressed
+}
+
+// NewSuppressor returns a Suppressor that reads the baseline
+// file specified by the -baseline flag, if any.
+func NewSuppressor() (*Suppressor, error) {
+	s := &Suppressor{
+		allowed:  make(map[string]int),
+		found:    make(map[string]int),
+		seen:     make(map[string]bool),
+		files:    make(map[string][]*directive),
+		ran:      make(map[string]bool),
+		contents: make(map[string][]byte),
+	}
+	if Baseline != "" && !UpdateBaseline {
+		data, err := os.ReadFile(Baseline)
+		if err != nil {
+			return nil, fmt.Errorf("reading baseline: %v", err)
+		}
+		var file baselineFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("invalid baseline file %s: %v", Baseline, err)
+		}
+		s.baseline = file.Findings
+		for _, entry := range s.baseline {
+			s.allowed[entry.Fingerprint]++
+		}
+	}
+	return s, nil
+}
+
+// Filter returns the subset of the diagnostics reported by the
+// specified pass that are not suppressed by a directive or by the
+// baseline. Source files are read using pass.ReadFile, so that the
+// driver's view of the file (such as an overlay) is respected.
+//
+// Findings are keyed by the type checker's package path, not the
+// driver's package ID, so that all drivers agree on a baseline.
+//
+// Diagnostics in files belonging to several packages (such as a
+// package and its test variant) may be filtered more than once.
+func (s *Suppressor) Filter(pass *analysis.Pass, diags []analysis.Diagnostic) []analysis.Diagnostic {
+	fset, a, pkgPath := pass.Fset, pass.Analyzer, pass.Pkg.Path()
+	for _, f := range pass.Files {
+		filename := fset.File(f.FileStart).Name()
+		if _, ok := s.files[filename]; !ok {
+			s.files[filename] = parseDirectives(fset, f)
+		}
+		s.ran[filename+"\x00"+a.Name] = true
+		s.ran[pkgPath+"\x00"+filepath.Base(filename)+"\x00"+a.Name] = true
+	}
+
+	var keep []analysis.Diagnostic
+	for _, diag := range diags {
+		posn := fset.Position(diag.Pos)
+		if s.ignored(posn, a.Name) {
+			continue
+		}
+		entry := BaselineEntry{
+			Analyzer: a.Name,
+			Package:  pkgPath,
+			File:     filepath.Base(posn.Filename),
+			Message:  diag.Message,
+		}
+		entry.Fingerprint = s.fingerprint(pass, entry, diag)
+
+		// Count each finding once, even if reported in several packages.
+		key := fmt.Sprintf("%s %s:%d", entry.Fingerprint, posn.Filename, posn.Offset)
+		if !s.seen[key] {
+			s.seen[key] = true
+			s.found[entry.Fingerprint]++
+			s.findings = append(s.findings, entry)
+		}
+		if UpdateBaseline {
+			continue // record, but don't report, all findings
+		}
+		if s.found[entry.Fingerprint] <= s.allowed[entry.Fingerprint] {
+			continue // in baseline
+		}
+		keep = append(keep, diag)
+	}
+	return keep
+}
+
+// ignored reports whether a diagnostic at posn by the named analyzer
+// is suppressed by a directive.
+func (s *Suppressor) ignored(posn token.Position, analyzer string) bool {
+	for _, d := range s.files[posn.Filename] {
+		if d.reason == "" {
+			continue // invalid
+		}
+		if d.fileWide || posn.Line == d.posn.Line || posn.Line == d.posn.Line+1 {
+			for _, name := range d.analyzers {
+				if name == analyzer {
+					d.used[analyzer] = true
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// fingerprint returns the fingerprint of a finding.
+func (s *Suppressor) fingerprint(pass *analysis.Pass, entry BaselineEntry, diag analysis.Diagnostic) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00", entry.Analyzer, entry.Package, entry.File, entry.Message)
+	h.Write(s.sourceContext(pass, diag))
+	return fmt.Sprintf("%x", h.Sum(nil)[:8])
+}
+
+// sourceContext returns the text of the (at most three) lines spanned
+// by the diagnostic, with each run of spaces replaced by a single space.
+func (s *Suppressor) sourceContext(pass *analysis.Pass, diag analysis.Diagnostic) []byte {
+	if !diag.Pos.IsValid() {
+		return nil
+	}
+	tf := pass.Fset.File(diag.Pos)
+	if tf == nil {
+		return nil
+	}
+	content, ok := s.contents[tf.Name()]
+	if !ok {
+		content, _ = pass.ReadFile(tf.Name())
+		s.contents[tf.Name()] = content
+	}
+	startLine := tf.Line(diag.Pos)
+	endLine := startLine
+	if diag.End.IsValid() && diag.End <= token.Pos(tf.Base()+tf.Size()) {
+		endLine = min(tf.Line(diag.End), startLine+2)
+	}
+	start := tf.Offset(tf.LineStart(startLine))
+	end := len(content)
+	if endLine < tf.LineCount() {
+		end = tf.Offset(tf.LineStart(endLine + 1))
+	}
+	if start > end || end > len(content) {
+		return nil // file has changed
+	}
+	return bytes.Join(bytes.Fields(content[start:end]), []byte(" "))
+}
+
+// parseDirectives returns the suppression directives in file f.
+func parseDirectives(fset *token.FileSet, f *ast.File) []*directive {
+	var directives []*directive
+	for _, group := range f.Comments {
+		for _, c := range group.List {
+			var fileWide bool
+			rest, ok := strings.CutPrefix(c.Text, "//lint:ignore")
+			if !ok {
+				rest, ok = strings.CutPrefix(c.Text, "//lint:file-ignore")
+				fileWide = true
+			}
+			if !ok || rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+				continue
+			}
+			names, reason, _ := strings.Cut(strings.TrimSpace(rest), " ")
+			d := &directive{
+				posn:     fset.Position(c.Slash),
+				reason:   strings.TrimSpace(reason),
+				fileWide: fileWide,
+				used:     make(map[string]bool),
+			}
+			if names != "" {
+				d.analyzers = strings.Split(names, ",")
+			}
+			directives = append(directives, d)
+		}
+	}
+	return directives
+}
+
+// Finish completes the processing of findings. With -update-baseline,
+// it writes the findings to the baseline file. Otherwise, it prints
+// to w a report of invalid directives and stale suppressions: unused
+// directives and baseline entries that match no finding. Only
+// directives and entries for files analyzed by the relevant analyzer
+// are considered.
+func (s *Suppressor) Finish(w io.Writer) error {
+	var report []string
+	for _, directives := range s.files {
+		for _, d := range directives {
+			kind := "lint:ignore"
+			if d.fileWide {
+				kind = "lint:file-ignore"
+			}
+			if d.reason == "" {
+				report = append(report, fmt.Sprintf("%s: invalid //%s directive: must name analyzers and give a reason", d.posn, kind))
+				continue
+			}
+			for _, name := range d.analyzers {
+				if s.ran[d.posn.Filename+"\x00"+name] && !d.used[name] {
+					report = append(report, fmt.Sprintf("%s: unused //%s directive for %s", d.posn, kind, name))
+				}
+			}
+		}
+	}
+	sort.Strings(report)
+
+	if UpdateBaseline {
+		sort.Slice(s.findings, func(i, j int) bool {
+			x, y := s.findings[i], s.findings[j]
+			if x.Package != y.Package {
+				return x.Package < y.Package
+			}
+			if x.File != y.File {
+				return x.File < y.File
+			}
+			if x.Analyzer != y.Analyzer {
+				return x.Analyzer < y.Analyzer
+			}
+			if x.Message != y.Message {
+				return x.Message < y.Message
+			}
+			return x.Fingerprint < y.Fingerprint
+		})
+		data, err := json.MarshalIndent(baselineFile{Findings: s.findings}, "", "\t")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(Baseline, append(data, '\n'), 0666); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "wrote %d findings to baseline %s\n", len(s.findings), Baseline)
+	} else {
+		found := make(map[string]int)
+		for fp, n := range s.found {
+			found[fp] = n
+		}
+		var stale []string
+		for _, entry := range s.baseline {
+			if !s.ran[entry.Package+"\x00"+entry.File+"\x00"+entry.Analyzer] {
+				continue
+			}
+			if found[entry.Fingerprint] > 0 {
+				found[entry.Fingerprint]--
+				continue
+			}
+			stale = append(stale, fmt.Sprintf("\t%s/%s: %s: %s\n", entry.Package, entry.File, entry.Analyzer, entry.Message))
+		}
+		if len(stale) > 0 {
+			report = append(report, fmt.Sprintf("baseline %s has %d stale entries; regenerate it with -update-baseline:\n%s",
+				Baseline, len(stale), strings.Join(stale, "")))
+		}
+	}
+
+	for _, line := range report {
+		if _, err := fmt.Fprintln(w, strings.TrimSuffix(line, "\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysisflags_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/internal/analysisflags"
+)
+
+// TestBaselineFingerprint checks that a finding recorded in a
+// baseline continues to be suppressed after edits that move it to
+// another line or change its indentation, but that a finding with
+// different source context is not. The source is read only through
+// the pass's ReadFile, as a driver with overlays would provide it.
+func TestBaselineFingerprint(t *testing.T) {
+	defer func(baseline string, update bool) {
+		analysisflags.Baseline, analysisflags.UpdateBaseline = baseline, update
+	}(analysisflags.Baseline, analysisflags.UpdateBaseline)
+
+	dir := t.TempDir()
+	analysisflags.Baseline = filepath.Join(dir, "baseline.json")
+	filename := filepath.Join(dir, "p.go")
+	a := &analysis.Analyzer{Name: "a", Doc: "doc"}
+
+	// filter returns the messages of the diagnostics reported at
+	// each occurrence of "bad" in src that are not suppressed.
+	filter := func(src string) []string {
+		t.Helper()
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var diags []analysis.Diagnostic
+		ast.Inspect(f, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok && strings.HasPrefix(id.Name, "bad") {
+				diags = append(diags, analysis.Diagnostic{Pos: id.Pos(), End: id.End(), Message: "bad variable"})
+			}
+			return true
+		})
+		s, err := analysisflags.NewSuppressor()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		pass := &analysis.Pass{
+			Analyzer: a,
+			Fset:     fset,
+			Files:    []*ast.File{f},
+			Pkg:      types.NewPackage("p", "p"),
+			ReadFile: func(name string) ([]byte, error) {
+				if name != filename {
+					return nil, os.ErrNotExist
+				}
+				return []byte(src), nil
+			},
+		}
+		for _, d := range s.Filter(pass, diags) {
+			got = append(got, fset.Position(d.Pos).String())
+		}
+		if err := s.Finish(io.Discard); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	analysisflags.UpdateBaseline = true
+	filter("package p\n\nvar bad = 1\n")
+
+	analysisflags.UpdateBaseline = false
+	if got := filter("package p\n\n// comment\n\nvar   bad = 1\n"); got != nil {
+		t.Errorf("moved finding was reported: %v", got)
+	}
+	if got := filter("package p\n\nvar bad = 1\n\nvar bad2 = 2\n"); len(got) != 1 || !strings.HasSuffix(got[0], ":5:5") {
+		t.Errorf("got %v, want only the new finding at line 5", got)
+	}
+	if got := filter("package p\n\nvar bad = 2\n"); len(got) != 1 {
+		t.Errorf("got %v, want the finding with changed source", got)
+	}
+}
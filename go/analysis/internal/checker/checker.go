@@ -17,6 +17,7 @@ import (
 	"flag"
 	"fmt"
 	"go/format"
+	"go/token"
 	"io"
 	"maps"
 
@@ -25,7 +26,9 @@ import (
 	"runtime"
 	"runtime/pprof"
 	"runtime/trace"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -58,6 +61,10 @@ var (
 	// Fix determines whether to apply (!Diff) or display (Diff) all suggested fixes.
 	Fix bool
 
+	// FixAnalyzers, if non-empty, restricts Fix to the
+	// named analyzers, which are the only ones run.
+	FixAnalyzers []string
+
 	// Diff causes the file updates to be displayed, but not applied.
 	// This flag has no effect unless Fix is true.
 	Diff bool
@@ -75,10 +82,36 @@ func RegisterFlags() {
 	flag.StringVar(&Trace, "trace", "", "write trace log to this file")
 	flag.BoolVar(&IncludeTests, "test", IncludeTests, "indicates whether test files should be analyzed, too")
 
-	flag.BoolVar(&Fix, "fix", false, "apply all suggested fixes")
+	flag.Var(fixFlag{}, "fix", "apply all suggested fixes, or (with -fix=a,b) only those of the named analyzers")
 	flag.BoolVar(&Diff, "diff", false, "with -fix, don't update the files, but print a unified diff")
 }
 
+// fixFlag is the flag.Value for -fix, which is either a boolean
+// or a comma-separated list of analyzer names.
+type fixFlag struct{}
+
+func (fixFlag) IsBoolFlag() bool { return true }
+
+func (fixFlag) String() string {
+	if len(FixAnalyzers) > 0 {
+		return strings.Join(FixAnalyzers, ",")
+	}
+	return strconv.FormatBool(Fix)
+}
+
+func (fixFlag) Set(value string) error {
+	if b, err := strconv.ParseBool(value); err == nil {
+		Fix, FixAnalyzers = b, nil
+		return nil
+	}
+	Fix, FixAnalyzers = true, strings.Split(value, ",")
+	return nil
+}
+
+// maxFixRounds is the maximum number of rounds of analysis and
+// fixing that -fix runs in search of a fixed point.
+const maxFixRounds = 10
+
 // Run loads the packages specified by args using go/packages,
 // then applies the specified analyzers to them.
 // Analysis flags must already have been set.
@@ -158,6 +191,23 @@ func Run(args []string, analyzers []*analysis.Analyzer) (exitcode int) {
 		}()
 	}
 
+	// With -fix=a,b, run only the named analyzers.
+	if Fix && len(FixAnalyzers) > 0 {
+		var keep []*analysis.Analyzer
+		for _, name := range FixAnalyzers {
+			i := slices.IndexFunc(analyzers, func(a *analysis.Analyzer) bool { return a.Name == name })
+			if i < 0 {
+				log.Printf("-fix: unknown or disabled analyzer %q", name)
+				exitAtLeast(1)
+				return
+			}
+			if !slices.Contains(keep, analyzers[i]) {
+				keep = append(keep, analyzers[i])
+			}
+		}
+		analyzers = keep
+	}
+
 	// Load the packages.
 	if dbg('v') {
 		log.SetPrefix("")
@@ -192,24 +242,89 @@ func Run(args []string, analyzers []*analysis.Analyzer) (exitcode int) {
 		Sequential:  dbg('p'),
 		FactLog:     factLog,
 	}
-	if dbg('v') {
-		log.Printf("building graph of analysis passes")
-	}
-	graph, err := checker.Analyze(analyzers, initial, opts)
+	graph, suppressor, err := analyze(analyzers, initial, opts)
 	if err != nil {
 		log.Print(err)
 		exitAtLeast(1)
 		return
 	}
 
+	// Report stale suppressions once we're done.
+	defer func() {
+		if err := suppressor.Finish(os.Stderr); err != nil {
+			log.Print(err)
+			exitAtLeast(1)
+		}
+	}()
+
 	// Don't print the diagnostics,
-	// but apply all fixes from the root actions.
+	// but apply fixes from the root actions.
+	//
+	// Applying fixes may enable further fixes, and fixes that
+	// conflict with others are dropped, so re-run the analysis on
+	// the updated files until a round applies no fixes. The fixes
+	// dropped by the last round to apply any were not suggested
+	// again, so they are reported.
 	if Fix {
-		if err := applyFixes(graph.Roots, Diff); err != nil {
-			// Fail when applying fixes failed.
-			log.Print(err)
+		var (
+			applied int
+			rounds  int
+			dropped []string
+			updated = make(map[string]bool)
+		)
+		for round := 1; ; round++ {
+			rounds = round
+			res, err := applyFixes(graph.Roots, Diff)
+			if err != nil {
+				// Fail when applying fixes failed.
+				log.Print(err)
+				exitAtLeast(1)
+				return
+			}
+			applied += res.applied
+			for _, file := range res.updated {
+				updated[file] = true
+			}
+			if res.applied > 0 || len(res.dropped) > 0 {
+				dropped = res.dropped
+			}
+			if res.applied == 0 {
+				break // fixed point
+			}
+			if Diff {
+				break // the files were not updated
+			}
+			if round == maxFixRounds {
+				log.Printf("-fix: stopped after %d rounds without reaching a fixed point", round)
+				break
+			}
+
+			if dbg('v') {
+				log.Printf("fix round %d: applied %d fixes, dropped %d; re-running analysis", round, res.applied, len(res.dropped))
+			}
+			initial, err = load(args, allSyntax)
+			if err == nil {
+				graph, suppressor, err = analyze(analyzers, initial, opts)
+			}
+			if err != nil {
+				log.Print(err)
+				exitAtLeast(1)
+				return
+			}
+		}
+		if n := len(dropped); n > 0 {
+			for _, msg := range dropped {
+				log.Print(msg)
+			}
+			if Diff {
+				log.Printf("%d of %d fixes skipped (e.g. due to conflicts)", n, applied+n)
+			} else {
+				log.Printf("applied %d of %d fixes in %d rounds; %d files updated",
+					applied, applied+n, rounds, len(updated))
+			}
 			exitAtLeast(1)
-			return
+		} else if dbg('v') {
+			log.Printf("applied %d fixes, updated %d files", applied, len(updated))
 		}
 		// Don't proceed to print text/JSON,
 		// and don't report an error
@@ -226,16 +341,46 @@ func Run(args []string, analyzers []*analysis.Analyzer) (exitcode int) {
 	return
 }
 
+// analyze runs the analyzers on the initial packages, and filters
+// the diagnostics of the root actions through a new Suppressor,
+// which the caller must Finish.
+func analyze(analyzers []*analysis.Analyzer, initial []*packages.Package, opts *checker.Options) (*checker.Graph, *analysisflags.Suppressor, error) {
+	if dbg('v') {
+		log.Printf("building graph of analysis passes")
+	}
+	graph, err := checker.Analyze(analyzers, initial, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Suppress diagnostics by directives and baseline.
+	suppressor, err := analysisflags.NewSuppressor()
+	if err != nil {
+		return nil, nil, err
+	}
+	for act := range graph.All() {
+		if act.IsRoot && act.Err == nil {
+			act.Diagnostics = suppressor.Filter(internal.Pass(act), act.Diagnostics)
+		}
+	}
+	return graph, suppressor, nil
+}
+
 // printDiagnostics prints diagnostics in text or JSON form
 // and returns the appropriate exit code.
 func printDiagnostics(graph *checker.Graph) (exitcode int) {
 	// Print the results.
-	// With -json, the exit code is always zero.
-	if analysisflags.JSON {
+	// With -json or -format=sarif, the exit code is always zero.
+	switch analysisflags.Format {
+	case "json":
 		if err := graph.PrintJSON(os.Stdout); err != nil {
 			return 1
 		}
-	} else {
+	case "sarif":
+		if err := graph.PrintSARIF(os.Stdout); err != nil {
+			return 1
+		}
+	default:
 		if err := graph.PrintText(os.Stderr, analysisflags.Context); err != nil {
 			return 1
 		}
@@ -322,9 +467,9 @@ func load(patterns []string, allSyntax bool) ([]*packages.Package, error) {
 // Each fix is treated as an independent change; fixes are merged in
 // an arbitrary deterministic order as if by a three-way diff tool
 // such as the UNIX diff3 command or 'git merge'. Any fix that cannot be
-// cleanly merged is discarded, in which case the final summary tells
-// the user to re-run the tool.
-// TODO(adonovan): make the checker tool re-run the analysis itself.
+// cleanly merged is dropped, and the result describes it and the
+// applied fix with which it conflicts. The caller may then re-run the
+// analysis on the updated files to obtain fresh fixes.
 //
 // When the same file is analyzed as a member of both a primary
 // package "p" and a test-augmented package "p [p.test]", there may be
@@ -362,23 +507,26 @@ func load(patterns []string, allSyntax bool) ([]*packages.Package, error) {
 // import(...) portion of the file into semantic edits, compose those
 // edits algebraically, then convert the result back to edits.
 //
-// applyFixes returns success if all fixes are valid, could be cleanly
-// merged, and the corresponding files were successfully updated.
+// applyFixes returns an error if a file was modified concurrently
+// or could not be updated. Otherwise it returns a summary of the
+// fixes that were applied and dropped.
 //
 // If showDiff, instead of updating the files it display the final
 // patch composed of all the cleanly merged fixes.
 //
 // TODO(adonovan): handle file-system level aliases such as symbolic
 // links using robustio.FileID.
-func applyFixes(actions []*checker.Action, showDiff bool) error {
+func applyFixes(actions []*checker.Action, showDiff bool) (*fixResult, error) {
 
 	// Select fixes to apply.
 	//
 	// If there are several for a given Diagnostic, choose the first.
 	// Preserve the order of iteration, for determinism.
 	type fixact struct {
-		fix *analysis.SuggestedFix
-		act *checker.Action
+		fix   *analysis.SuggestedFix
+		act   *checker.Action
+		posn  token.Position         // of diagnostic
+		edits map[string][]diff.Edit // edits of fix, by file
 	}
 	var fixes []*fixact
 	for _, act := range actions {
@@ -386,7 +534,8 @@ func applyFixes(actions []*checker.Action, showDiff bool) error {
 			for i := range diag.SuggestedFixes {
 				fix := &diag.SuggestedFixes[i]
 				if i == 0 {
-					fixes = append(fixes, &fixact{fix, act})
+					posn := act.Package.Fset.Position(diag.Pos)
+					fixes = append(fixes, &fixact{fix: fix, act: act, posn: posn})
 				} else {
 					// TODO(adonovan): abstract the logger.
 					log.Printf("%s: ignoring alternative fix %q", act, fix.Message)
@@ -418,24 +567,30 @@ func applyFixes(actions []*checker.Action, showDiff bool) error {
 		return content, nil
 	}
 
+	// describe returns a description of a fix for the user.
+	describe := func(fa *fixact) string {
+		return fmt.Sprintf("fix %q (%s) at %s", fa.fix.Message, fa.act.Analyzer.Name, fa.posn)
+	}
+
 	// Apply each fix, updating the current state
 	// only if the entire fix can be cleanly merged.
+	result := new(fixResult)
 	accumulatedEdits := make(map[string][]diff.Edit)
-	goodFixes := 0
+	var applied []*fixact
 fixloop:
 	for _, fixact := range fixes {
 		readFile := internal.Pass(fixact.act).ReadFile
 
 		// Convert analysis.TextEdits to diff.Edits, grouped by file.
 		// Precondition: a prior call to validateFix succeeded.
-		fileEdits := make(map[string][]diff.Edit)
+		fixact.edits = make(map[string][]diff.Edit)
 		fset := fixact.act.Package.Fset
 		for _, edit := range fixact.fix.TextEdits {
 			file := fset.File(edit.Pos)
 
 			baseline, err := getBaseline(readFile, file.Name())
 			if err != nil {
-				log.Printf("skipping fix to file %s: %v", file.Name(), err)
+				result.dropped = append(result.dropped, fmt.Sprintf("dropped %s: %v", describe(fixact), err))
 				continue fixloop
 			}
 
@@ -443,11 +598,11 @@ fixloop:
 			// as it indicates a concurrent write to at least one file,
 			// and possibly others (consider a git checkout, for example).
 			if file.Size() != len(baseline) {
-				return fmt.Errorf("concurrent file modification detected in file %s (size changed from %d -> %d bytes); aborting fix",
+				return nil, fmt.Errorf("concurrent file modification detected in file %s (size changed from %d -> %d bytes); aborting fix",
 					file.Name(), file.Size(), len(baseline))
 			}
 
-			fileEdits[file.Name()] = append(fileEdits[file.Name()], diff.Edit{
+			fixact.edits[file.Name()] = append(fixact.edits[file.Name()], diff.Edit{
 				Start: file.Offset(edit.Pos),
 				End:   file.Offset(edit.End),
 				New:   string(edit.NewText),
@@ -457,15 +612,24 @@ fixloop:
 		// Apply each set of edits by merging atop
 		// the previous accumulated state.
 		after := make(map[string][]diff.Edit)
-		for file, edits := range fileEdits {
+		for file, edits := range fixact.edits {
 			if prev := accumulatedEdits[file]; len(prev) > 0 {
 				merged, ok := diff.Merge(prev, edits)
 				if !ok {
-					// debugging
-					if false {
-						log.Printf("%s: fix %s conflicts", fixact.act, fixact.fix.Message)
+					// Find the applied fix with which it conflicts.
+					// (There may be none if it conflicts only with
+					// the combination of several.)
+					reason := "conflicts with other fixes"
+					for _, prev := range applied {
+						if edits2 := prev.edits[file]; len(edits2) > 0 {
+							if _, ok := diff.Merge(edits2, edits); !ok {
+								reason = "conflicts with " + describe(prev)
+								break
+							}
+						}
 					}
-					continue fixloop // conflict
+					result.dropped = append(result.dropped, fmt.Sprintf("dropped %s: %s", describe(fixact), reason))
+					continue fixloop
 				}
 				edits = merged
 			}
@@ -473,14 +637,10 @@ fixloop:
 		}
 
 		// The entire fix applied cleanly; commit it.
-		goodFixes++
+		applied = append(applied, fixact)
 		maps.Copy(accumulatedEdits, after)
-		// debugging
-		if false {
-			log.Printf("%s: fix %s applied", fixact.act, fixact.fix.Message)
-		}
 	}
-	badFixes := len(fixes) - goodFixes
+	result.applied = len(applied)
 
 	// Show diff or update files to final state.
 	var files []string
@@ -488,7 +648,7 @@ fixloop:
 		files = append(files, file)
 	}
 	sort.Strings(files) // for deterministic -diff
-	var filesUpdated, totalFiles int
+	var failed int
 	for _, file := range files {
 		edits := accumulatedEdits[file]
 		if len(edits) == 0 {
@@ -515,13 +675,13 @@ fixloop:
 
 		} else {
 			// write
-			totalFiles++
 			// TODO(adonovan): abstract the I/O.
 			if err := os.WriteFile(file, final, 0644); err != nil {
 				log.Println(err)
+				failed++
 				continue
 			}
-			filesUpdated++
+			result.updated = append(result.updated, file)
 		}
 	}
 
@@ -537,39 +697,29 @@ fixloop:
 	// Then file writes and the UI can be applied by the caller
 	// in whatever form they like.
 
-	// If victory was incomplete, report an error that indicates partial progress.
-	//
-	// badFixes > 0 indicates that we decided not to attempt some
-	// fixes due to conflicts or failure to read the source; still
-	// it's a relatively benign situation since the user can
-	// re-run the tool, and we may still make progress.
-	//
-	// filesUpdated < totalFiles indicates that some file updates
-	// failed. This should be rare, but is a serious error as it
+	// A failure to update a file is a serious error as it
 	// may apply half a fix, or leave the files in a bad state.
-	//
-	// These numbers are potentially misleading:
-	// The denominator includes duplicate conflicting fixes due to
-	// common files in packages "p" and "p [p.test]", which may
-	// have been fixed fixed and won't appear in the re-run.
-	// TODO(adonovan): eliminate identical fixes as an initial
-	// filtering step.
-	//
-	// TODO(adonovan): should we log that n files were updated in case of total victory?
-	if badFixes > 0 || filesUpdated < totalFiles {
-		if showDiff {
-			return fmt.Errorf("%d of %d fixes skipped (e.g. due to conflicts)", badFixes, len(fixes))
-		} else {
-			return fmt.Errorf("applied %d of %d fixes; %d files updated. (Re-run the command to apply more.)",
-				goodFixes, len(fixes), filesUpdated)
-		}
+	if failed > 0 {
+		return nil, fmt.Errorf("applied %d of %d fixes, but failed to update %d of %d files",
+			result.applied, len(fixes), failed, len(result.updated)+failed)
 	}
 
-	if dbg('v') {
-		log.Printf("applied %d fixes, updated %d files", len(fixes), filesUpdated)
-	}
+	return result, nil
+}
 
-	return nil
+// A fixResult summarizes the outcome of applyFixes.
+//
+// Dropped fixes are those that were not applied, due to conflicts
+// or failure to read the source. This is a relatively benign
+// situation as re-running the analysis may yield fresh fixes.
+//
+// The counts are potentially misleading: they include duplicate
+// fixes due to common files in packages "p" and "p [p.test]", which
+// are coalesced if identical.
+type fixResult struct {
+	applied int      // number of fixes applied
+	dropped []string // description of each dropped fix, and why
+	updated []string // names of files updated
 }
 
 // needFacts reports whether any analysis required by the specified set
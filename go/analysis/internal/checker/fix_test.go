@@ -14,10 +14,12 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"runtime"
 	"slices"
 	"strings"
+	"sync"
 	"testing"
 
 	"golang.org/x/tools/go/analysis"
@@ -38,9 +40,14 @@ func TestMain(m *testing.M) {
 	// this process should behave like a multichecker.
 	// Analyzers are selected by flags.
 	if _, ok := os.LookupEnv("CHECKER_TEST_CHILD"); ok {
+		// The marker and noend analyzers suggest fixes that
+		// are valid only for the original files, so they must
+		// not run again after a round of -fix changes them.
+		round := newRoundAnalyzer()
 		multichecker.Main(
-			markerAnalyzer,
-			noendAnalyzer,
+			firstRound(round, markerAnalyzer),
+			firstRound(round, noendAnalyzer),
+			parensAnalyzer,
 			renameAnalyzer,
 		)
 		panic("unreachable")
@@ -587,6 +594,108 @@ var noendAnalyzer = &analysis.Analyzer{
 	},
 }
 
+var parensAnalyzer = &analysis.Analyzer{
+	Name:     "parens",
+	Doc:      "removes each pair of parentheses, one at a time",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run: func(pass *analysis.Pass) (any, error) {
+		inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+		nodeFilter := []ast.Node{(*ast.ParenExpr)(nil)}
+		var (
+			err   error
+			inner = make(map[ast.Expr]bool) // nested parens, with -parens.outer
+		)
+		inspect.Preorder(nodeFilter, func(n ast.Node) {
+			paren := n.(*ast.ParenExpr)
+			if parensOuter {
+				inner[paren.X] = true
+				if inner[paren] {
+					return
+				}
+			}
+			file := pass.Fset.File(paren.Pos())
+			content, err2 := pass.ReadFile(file.Name())
+			if err2 != nil {
+				err = err2
+				return
+			}
+			// Replace the whole expression, so that the fixes
+			// for nested parentheses conflict.
+			pass.Report(analysis.Diagnostic{
+				Pos:     paren.Pos(),
+				End:     paren.End(),
+				Message: "redundant parentheses",
+				SuggestedFixes: []analysis.SuggestedFix{{
+					Message: "remove parentheses",
+					TextEdits: []analysis.TextEdit{{
+						Pos:     paren.Pos(),
+						End:     paren.End(),
+						NewText: content[file.Offset(paren.X.Pos()):file.Offset(paren.X.End())],
+					}},
+				}},
+			})
+		})
+		return nil, err
+	},
+}
+
+// parensOuter causes the parens analyzer to report only the outermost
+// of nested parentheses, so that only one pair is removed per round.
+var parensOuter bool
+
+func init() {
+	parensAnalyzer.Flags.BoolVar(&parensOuter, "outer", false, "report only the outermost of nested parentheses")
+}
+
+// newRoundAnalyzer returns an analyzer whose result reports whether
+// the files of the package are unchanged since this analyzer first
+// saw them, that is, whether no earlier round of -fix has changed
+// them. Each call returns an analyzer with its own state.
+func newRoundAnalyzer() *analysis.Analyzer {
+	var (
+		mu       sync.Mutex
+		original = make(map[string]string) // file contents, by name
+	)
+	return &analysis.Analyzer{
+		Name:       "round",
+		Doc:        "reports whether the package is unchanged by -fix",
+		ResultType: reflect.TypeOf(false),
+		Run: func(pass *analysis.Pass) (any, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			unchanged := true
+			for _, file := range pass.Files {
+				filename := pass.Fset.File(file.FileStart).Name()
+				content, err := pass.ReadFile(filename)
+				if err != nil {
+					return nil, err
+				}
+				if orig, ok := original[filename]; !ok {
+					original[filename] = string(content)
+				} else if orig != string(content) {
+					unchanged = false
+				}
+			}
+			return unchanged, nil
+		},
+	}
+}
+
+// firstRound returns a copy of analyzer a that does nothing in
+// packages changed by an earlier round of -fix, according to the
+// round analyzer.
+func firstRound(round, a *analysis.Analyzer) *analysis.Analyzer {
+	copy := *a
+	copy.Requires = append(slices.Clip(a.Requires), round)
+	copy.Run = func(pass *analysis.Pass) (any, error) {
+		if !pass.ResultOf[round].(bool) {
+			return nil, nil
+		}
+		return a.Run(pass)
+	}
+	return &copy
+}
+
 // panics asserts that f() panics with with a value whose printed form matches the regexp want.
 func panics(t *testing.T, want string, f func()) {
 	defer func() {
@@ -69,6 +69,11 @@ func TestExitCode(t *testing.T) {
 		{[]string{"-findcall.name=panic", "-json", "io"}, 0},
 		{[]string{"-findcall.name=panic", "-json", "io"}, 0},
 		{[]string{"-findcall.name=panic", "-json", "sort", "io"}, 0},
+
+		// -format=sarif: likewise.
+		{[]string{"-findcall.name=panic", "-format=sarif", "io"}, 0},
+		{[]string{"-findcall.name=panic", "-format=sarif", "sort", "io"}, 0},
+		{[]string{"-format=xml", "io"}, 1}, // unknown format
 	} {
 		args := []string{"-test.run=TestExitCode", "--"}
 		args = append(args, test.args...)
@@ -14,6 +14,9 @@
 //	-flags          describe flags                    (to the build tool)
 //	foo.cfg         description of compilation unit (from the build tool)
 //
+// With -format=sarif, the tool prints a separate SARIF log for each
+// compilation unit, describing a run of the analyzers on that unit.
+//
 // This package does not depend on go/packages.
 // If you need a standalone tool, use multichecker,
 // which supports this mode but can also load packages
@@ -138,14 +141,41 @@ func Run(configFile string, analyzers []*analysis.Analyzer) {
 
 	// In VetxOnly mode, the analysis is run only for facts.
 	if !cfg.VetxOnly {
-		if analysisflags.JSON {
+		// Suppress diagnostics by directives and baseline.
+		if analysisflags.UpdateBaseline {
+			log.Fatalf("-update-baseline is not supported by go vet; run the tool directly")
+		}
+		suppressor, err := analysisflags.NewSuppressor()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for i, res := range results {
+			if res.err == nil {
+				results[i].diagnostics = suppressor.Filter(res.pass, res.diagnostics)
+			}
+		}
+		if err := suppressor.Finish(os.Stderr); err != nil {
+			log.Fatal(err)
+		}
+
+		switch analysisflags.Format {
+		case "json":
 			// JSON output
 			tree := make(analysisflags.JSONTree)
 			for _, res := range results {
 				tree.Add(fset, cfg.ID, res.a.Name, res.diagnostics, res.err)
 			}
 			tree.Print(os.Stdout)
-		} else {
+		case "sarif":
+			// SARIF output, one log per compilation unit
+			sarif := analysisflags.NewSARIFLog(analyzers)
+			for _, res := range results {
+				sarif.Add(fset, cfg.ID, res.a, res.diagnostics, res.err)
+			}
+			if err := sarif.Print(os.Stdout); err != nil {
+				log.Fatal(err)
+			}
+		default:
 			// plain text
 			exit := 0
 			for _, res := range results {
@@ -287,6 +317,7 @@ func run(fset *token.FileSet, cfg *Config, analyzers []*analysis.Analyzer) ([]re
 	// Also build a map to hold working state and result.
 	type action struct {
 		once        sync.Once
+		pass        *analysis.Pass
 		result      any
 		err         error
 		usesFacts   bool // (transitively uses)
@@ -396,6 +427,7 @@ func run(fset *token.FileSet, cfg *Config, analyzers []*analysis.Analyzer) ([]re
 				Module:            module,
 			}
 			pass.ReadFile = analysisinternal.CheckedReadFile(pass, os.ReadFile)
+			act.pass = pass
 
 			t0 := time.Now()
 			act.result, act.err = a.Run(pass)
@@ -434,6 +466,7 @@ func run(fset *token.FileSet, cfg *Config, analyzers []*analysis.Analyzer) ([]re
 	for i, a := range analyzers {
 		act := actions[a]
 		results[i].a = a
+		results[i].pass = act.pass
 		results[i].err = act.err
 		results[i].diagnostics = act.diagnostics
 	}
@@ -451,6 +484,7 @@ func run(fset *token.FileSet, cfg *Config, analyzers []*analysis.Analyzer) ([]re
 
 type result struct {
 	a           *analysis.Analyzer
+	pass        *analysis.Pass
 	diagnostics []analysis.Diagnostic
 	err         error
 }
@@ -8,11 +8,13 @@ import (
 	"flag"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"runtime"
 	"strings"
 	"testing"
 
+	"golang.org/x/tools/go/analysis/multichecker"
 	"golang.org/x/tools/go/analysis/passes/assign"
 	"golang.org/x/tools/go/analysis/passes/findcall"
 	"golang.org/x/tools/go/analysis/passes/printf"
@@ -29,6 +31,9 @@ func TestMain(m *testing.M) {
 	case "minivet":
 		minivet()
 		panic("unreachable")
+	case "minichecker":
+		minichecker()
+		panic("unreachable")
 	case "worker":
 		worker() // see ExampleSeparateAnalysis
 		panic("unreachable")
@@ -48,6 +53,15 @@ func minivet() {
 	)
 }
 
+// minichecker is a standalone tool with the same analyzers as minivet.
+func minichecker() {
+	multichecker.Main(
+		findcall.Analyzer,
+		printf.Analyzer,
+		assign.Analyzer,
+	)
+}
+
 // This is a very basic integration test of modular
 // analysis with facts using unitchecker under "go vet".
 // It fork/execs the main function above.
@@ -149,6 +163,31 @@ func _() {
 	\}
 \}
 `
+	// With -format=sarif, each package gets its own SARIF log.
+	const wantASARIF = `# golang.org/fake/a
+\{
+\s*"\$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+\s*"version": "2.1.0",
+\s*"runs": \[(.|\n)*"ruleId": "findcall",
+\s*"ruleIndex": 0,
+\s*"level": "warning",
+\s*"message": \{
+\s*"text": "call of MyFunc123\(...\)"
+\s*\},
+\s*"locations": \[
+\s*\{
+\s*"physicalLocation": \{
+\s*"artifactLocation": \{
+\s*"uri": "file:///[^"]*[\\/]fake[\\/][^"]*a/a.go"
+\s*\},
+\s*"region": \{
+\s*"startLine": 4,
+\s*"startColumn": 11,`
+	const wantCSARIF = `# golang.org/fake/c
+\{
+\s*"\$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+(.|\n)*"ruleId": "assign",
+\s*"ruleIndex": 2,`
 	for _, test := range []struct {
 		args          string
 		wantOut       string
@@ -160,6 +199,8 @@ func _() {
 		{args: "golang.org/fake/a golang.org/fake/b", wantOut: wantA + wantB, wantExitError: true},
 		{args: "-json golang.org/fake/a", wantOut: wantAJSON, wantExitError: false},
 		{args: "-json golang.org/fake/c", wantOut: wantCJSON, wantExitError: false},
+		{args: "-format=sarif golang.org/fake/a", wantOut: wantASARIF, wantExitError: false},
+		{args: "-format=sarif golang.org/fake/c", wantOut: wantCSARIF, wantExitError: false},
 		{args: "-c=0 golang.org/fake/a", wantOut: wantA + "4		MyFunc123\\(\\)\n", wantExitError: true},
 	} {
 		cmd := exec.Command("go", "vet", "-vettool="+os.Args[0], "-findcall.name=MyFunc123")
@@ -196,3 +237,66 @@ func _() {
 		}
 	}
 }
+
+// TestBaselineAcrossDrivers checks that a baseline written by the
+// standalone driver suppresses the same findings under "go vet",
+// including those in test variants and external test packages.
+func TestBaselineAcrossDrivers(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skipf("skipping fork/exec test on this platform")
+	}
+
+	exported := packagestest.Export(t, packagestest.Modules, []packagestest.Module{{
+		Name: "golang.org/fake",
+		Files: map[string]any{
+			"a/a.go": `package a
+
+func _() {
+	MyFunc123()
+}
+
+func MyFunc123() {}
+`,
+			"a/a_test.go": `package a
+
+func _() {
+	MyFunc123()
+}
+`,
+			"a/x_test.go": `package a_test
+
+import "golang.org/fake/a"
+
+func _() {
+	a.MyFunc123()
+}
+`,
+		}}})
+	defer exported.Cleanup()
+
+	baseline := filepath.Join(t.TempDir(), "baseline.json")
+	run := func(entrypoint string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command(os.Args[0], args...)
+		if entrypoint == "minivet" {
+			cmd = exec.Command("go", append([]string{"vet", "-vettool=" + os.Args[0]}, args...)...)
+		}
+		cmd.Env = append(exported.Config.Env, "ENTRYPOINT="+entrypoint)
+		cmd.Dir = exported.Config.Dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s %s failed: %v\n%s", entrypoint, strings.Join(args, " "), err, out)
+		}
+		return string(out)
+	}
+
+	out := run("minichecker", "-findcall.name=MyFunc123", "-baseline="+baseline, "-update-baseline", "./...")
+	if !strings.Contains(out, "wrote 3 findings") {
+		t.Fatalf("-update-baseline: got <<%s>>, want 3 findings", out)
+	}
+	for _, entrypoint := range []string{"minichecker", "minivet"} {
+		if out := run(entrypoint, "-findcall.name=MyFunc123", "-baseline="+baseline, "./..."); out != "" {
+			t.Errorf("%s -baseline: got <<%s>>, want no findings or stale entries", entrypoint, out)
+		}
+	}
+}
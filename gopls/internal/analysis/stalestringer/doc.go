@@ -0,0 +1,36 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package stalestringer defines an analyzer that checks for String
+// methods generated by stringer that no longer match their constants.
+//
+// # Analyzer stalestringer
+//
+// stalestringer: check for stale String methods generated by stringer
+//
+// The stalestringer analyzer reports files generated by the stringer
+// command whose String methods no longer match the constants of the
+// type, because constants were added, removed, renamed, or changed
+// value since the file was generated. Other differences from what
+// stringer would now generate, such as those due to the version of
+// stringer, are ignored. For example,
+// after a constant is added to
+//
+//	//go:generate stringer -type=Pill
+//	type Pill int
+//
+//	const (
+//		Placebo Pill = iota
+//		Aspirin
+//		Ibuprofen // added
+//	)
+//
+// the String method in pill_string.go prints "Pill(2)" for Ibuprofen.
+//
+// The analyzer reads the arguments of the stringer command from the
+// "Code generated" comment at the top of the file, and reports the
+// declaration of the type. Its suggested fix replaces the generated
+// file with the output of stringer for those arguments, without the
+// need to run go generate.
+package stalestringer
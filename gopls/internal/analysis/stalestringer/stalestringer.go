@@ -0,0 +1,172 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stalestringer
+
+import (
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/internal/analysisinternal"
+	"golang.org/x/tools/internal/stringer"
+)
+
+//go:embed doc.go
+var doc string
+
+var Analyzer = &analysis.Analyzer{
+	Name:             "stalestringer",
+	Doc:              analysisinternal.MustExtractDoc(doc, "stalestringer"),
+	Run:              run,
+	RunDespiteErrors: true, // a stale file may not compile
+	URL:              "https://pkg.go.dev/golang.org/x/tools/gopls/internal/analysis/stalestringer",
+}
+
+// headerRx matches the first line of a file generated by stringer.
+var headerRx = regexp.MustCompile(`^// Code generated by "stringer (.*)"; DO NOT EDIT\.$`)
+
+func run(pass *analysis.Pass) (any, error) {
+nextFile:
+	for _, file := range pass.Files {
+		if len(file.Comments) == 0 || file.Comments[0].Pos() != file.FileStart {
+			continue
+		}
+		header := file.Comments[0].List[0]
+		m := headerRx.FindStringSubmatch(header.Text)
+		if m == nil {
+			continue
+		}
+		args := strings.Fields(m[1])
+		flags, err := stringer.ParseFlags(args)
+		if err != nil {
+			continue // not generated by a known version of stringer
+		}
+
+		// Regenerate the file, with the same header.
+		var g stringer.Generator
+		g.Header(args, file.Name.Name)
+		for _, typeName := range flags.Types {
+			values, err := stringer.FindValues(pass.Files, pass.TypesInfo.Defs, typeName, flags.TrimPrefix, flags.LineComment)
+			if err != nil || len(values) == 0 {
+				// The type may be declared in another package,
+				// such as an external test package, or have
+				// been deleted. Either way, we can't help.
+				continue nextFile
+			}
+			g.Generate(typeName, values)
+		}
+		src, err := g.Format()
+		if err != nil {
+			return nil, err // can't happen
+		}
+
+		// Compare the meaning of the files, not their bytes,
+		// since the output of stringer varies across versions.
+		newFile, err := parser.ParseFile(token.NewFileSet(), "", src, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err // can't happen
+		}
+		oldChecks, oldNames := summarize(file, flags.Types)
+		newChecks, newNames := summarize(newFile, flags.Types)
+		if oldNames == newNames && (oldChecks == nil || slices.Equal(oldChecks, newChecks)) {
+			continue
+		}
+		filename := pass.Fset.File(file.FileStart).Name()
+
+		// Report the declaration of the type, rather than the
+		// generated file, in which gopls would not offer the fix.
+		pos, end := header.Pos(), header.End()
+		if obj := pass.Pkg.Scope().Lookup(flags.Types[0]); obj != nil {
+			pos, end = obj.Pos(), obj.Pos()+token.Pos(len(obj.Name()))
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:     pos,
+			End:     end,
+			Message: fmt.Sprintf("String method for %s in %s is stale: its constants have changed since it was generated", strings.Join(flags.Types, ", "), filepath.Base(filename)),
+			SuggestedFixes: []analysis.SuggestedFix{{
+				Message: "Regenerate String method",
+				TextEdits: []analysis.TextEdit{{
+					Pos:     file.FileStart,
+					End:     file.FileEnd,
+					NewText: src,
+				}},
+			}},
+		})
+	}
+	return nil, nil
+}
+
+// summarize returns the parts of a file generated by stringer for the
+// specified types that depend on their constants: the sorted
+// expressions of the index checks in func _, which relate each
+// constant name to its value, and the concatenation of the names
+// printed by the String methods. Files generated by old versions of
+// stringer have no index checks, so checks is nil for them.
+func summarize(file *ast.File, typeNames []string) (checks []string, names string) {
+	var sb strings.Builder
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			// _ = x[Name - value]
+			if decl.Name.Name != "_" || decl.Recv != nil || decl.Body == nil {
+				continue
+			}
+			for _, stmt := range decl.Body.List {
+				if assign, ok := stmt.(*ast.AssignStmt); ok && len(assign.Rhs) == 1 {
+					if index, ok := assign.Rhs[0].(*ast.IndexExpr); ok {
+						checks = append(checks, types.ExprString(index.Index))
+					}
+				}
+			}
+
+		case *ast.GenDecl:
+			// const _T_name = "..." or _T_name_N = "..."
+			if decl.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				spec := spec.(*ast.ValueSpec)
+				for i, id := range spec.Names {
+					if i < len(spec.Values) && isNameConst(id.Name, typeNames) {
+						if lit, ok := spec.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+							s, _ := strconv.Unquote(lit.Value)
+							sb.WriteString(s)
+						}
+					}
+				}
+			}
+		}
+	}
+	slices.Sort(checks)
+	return checks, sb.String()
+}
+
+// isNameConst reports whether name is that of a constant holding the
+// names of the constants of one of the types, such as _T_name or
+// _T_name_0.
+func isNameConst(name string, typeNames []string) bool {
+	for _, t := range typeNames {
+		if rest, ok := strings.CutPrefix(name, "_"+t+"_name"); ok {
+			if rest == "" {
+				return true
+			}
+			if n, ok := strings.CutPrefix(rest, "_"); ok {
+				if _, err := strconv.Atoi(n); err == nil {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
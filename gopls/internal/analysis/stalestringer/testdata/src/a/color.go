@@ -0,0 +1,10 @@
+package a
+
+//go:generate stringer -type=Color -trimprefix=Color -linecomment
+type Color int
+
+const (
+	ColorRed   Color = iota
+	ColorGreen       // verde
+	ColorBlue
+)
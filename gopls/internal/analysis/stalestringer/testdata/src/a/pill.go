@@ -0,0 +1,10 @@
+package a
+
+//go:generate stringer -type=Pill
+type Pill int // want `String method for Pill in pill_string.go is stale`
+
+const (
+	Placebo Pill = iota
+	Aspirin
+	Ibuprofen
+)
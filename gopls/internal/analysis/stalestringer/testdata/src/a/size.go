@@ -0,0 +1,9 @@
+package a
+
+//go:generate stringer -type=Size
+type Size int
+
+const (
+	Small Size = iota
+	Large
+)
@@ -166,6 +166,46 @@ func (index *Index) Search(key Key, method *types.Func) []Result {
 	return results
 }
 
+// A TypeResult reports a matching type in a Supertypes or Subtypes search.
+type TypeResult struct {
+	Location    Location // location of the type name
+	IsInterface bool     // the type is an interface
+}
+
+// Supertypes reports each interface type in the index that is
+// implemented by the type that produced the search key.
+//
+// Unlike Search, it reports interface/interface pairs too, since a
+// type hierarchy must relate an interface to its generalizations.
+func (index *Index) Supertypes(key Key) []TypeResult {
+	var results []TypeResult
+	for _, candidate := range index.pkg.MethodSets {
+		if implements(key.mset, candidate) {
+			results = append(results, TypeResult{
+				Location:    index.location(candidate.Posn),
+				IsInterface: true,
+			})
+		}
+	}
+	return results
+}
+
+// Subtypes reports each type in the index that implements the
+// interface type that produced the search key, including other
+// interface types.
+func (index *Index) Subtypes(key Key) []TypeResult {
+	var results []TypeResult
+	for _, candidate := range index.pkg.MethodSets {
+		if implements(candidate, key.mset) {
+			results = append(results, TypeResult{
+				Location:    index.location(candidate.Posn),
+				IsInterface: candidate.IsInterface,
+			})
+		}
+	}
+	return results
+}
+
 // implements reports whether x implements y.
 func implements(x, y *gobMethodSet) bool {
 	if !y.IsInterface {
@@ -0,0 +1,170 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/tools/internal/event"
+	"golang.org/x/tools/internal/memoize"
+)
+
+// ModuleProxyVersions returns the versions of the module with the
+// specified path that are listed by the module proxies of GOPROXY, in
+// the order in which they are listed.
+//
+// The proxies are consulted as the go command would: each entry of
+// GOPROXY is tried in turn, falling back to the next after a "not
+// found" response if the entries are separated by a comma, or after
+// any error if they are separated by a pipe. The list ends at "off"
+// or "direct", since gopls never contacts version control systems.
+// No proxy is consulted for modules that match GONOPROXY (which
+// defaults to GOPRIVATE).
+//
+// The result, even if an error, is memoized, and the memo is
+// inherited by subsequent snapshots since it does not depend on the
+// workspace, so the proxies are contacted at most once per module
+// path per view.
+func (s *Snapshot) ModuleProxyVersions(ctx context.Context, modulePath string) ([]string, error) {
+	s.mu.Lock()
+	entry, hit := s.moduleProxyVersions.Get(modulePath)
+	s.mu.Unlock()
+
+	type moduleProxyVersionsResult struct {
+		versions []string
+		err      error
+	}
+
+	// cache miss?
+	if !hit {
+		env := &s.view.folder.Env
+		handle := memoize.NewPromise("moduleProxyVersions", func(ctx context.Context, _ any) any {
+			versions, err := listProxyVersions(ctx, env, modulePath)
+			return moduleProxyVersionsResult{versions, err}
+		})
+
+		entry = handle
+		s.mu.Lock()
+		s.moduleProxyVersions.Set(modulePath, entry, nil)
+		s.mu.Unlock()
+	}
+
+	// Await result.
+	v, err := s.awaitPromise(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	res := v.(moduleProxyVersionsResult)
+	return res.versions, res.err
+}
+
+// listProxyVersions implements ModuleProxyVersions.
+func listProxyVersions(ctx context.Context, env *GoEnv, modulePath string) ([]string, error) {
+	noproxy := env.GONOPROXY
+	if noproxy == "" {
+		noproxy = env.GOPRIVATE
+	}
+	if module.MatchPrefixPatterns(noproxy, modulePath) {
+		return nil, nil
+	}
+	escPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	for list := env.GOPROXY; list != ""; {
+		proxy, fallBackOnError := list, false
+		if i := strings.IndexAny(list, ",|"); i >= 0 {
+			proxy, fallBackOnError, list = list[:i], list[i] == '|', list[i+1:]
+		} else {
+			list = ""
+		}
+		switch strings.TrimSpace(proxy) {
+		case "":
+			continue
+		case "off", "direct":
+			return nil, nil
+		}
+
+		versions, err := listVersions(ctx, strings.TrimSpace(proxy), escPath)
+		if err == nil {
+			return versions, nil
+		}
+		if !fallBackOnError && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		event.Error(ctx, "listing module versions", err)
+	}
+	return nil, nil
+}
+
+// listVersions returns the versions in the list of the module with
+// the specified escaped path in a single proxy. It returns an error
+// matching [fs.ErrNotExist] if the proxy has no such list.
+func listVersions(ctx context.Context, proxy, escPath string) ([]string, error) {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "file":
+		f, err := os.Open(filepath.Join(filepath.FromSlash(u.Path), filepath.FromSlash(escPath), "@v", "list"))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return readVersionList(f)
+
+	case "http", "https":
+		// Don't let an unresponsive proxy stall the client.
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, "GET", strings.TrimSuffix(proxy, "/")+"/"+escPath+"/@v/list", nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return readVersionList(resp.Body)
+		case http.StatusNotFound, http.StatusGone:
+			return nil, &fs.PathError{Op: "GET", Path: req.URL.String(), Err: fs.ErrNotExist}
+		default:
+			return nil, fmt.Errorf("GET %s: %s", req.URL, resp.Status)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported proxy URL %q", proxy)
+	}
+}
+
+// readVersionList reads a version list in the format of a module
+// proxy's @v/list endpoint.
+func readVersionList(r io.Reader) ([]string, error) {
+	var versions []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			versions = append(versions, fields[0])
+		}
+	}
+	return versions, scanner.Err()
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+func TestListProxyVersions(t *testing.T) {
+	var requests atomic.Int32
+	server := func(status int) string {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			if status != http.StatusOK {
+				w.WriteHeader(status)
+				return
+			}
+			fmt.Fprintln(w, "v1.0.0")
+			fmt.Fprintln(w, "v1.1.0 2025-01-01T00:00:00Z")
+		}))
+		t.Cleanup(srv.Close)
+		return srv.URL
+	}
+	var (
+		good     = server(http.StatusOK)
+		notFound = server(http.StatusNotFound)
+		broken   = server(http.StatusInternalServerError)
+	)
+
+	tests := []struct {
+		env          GoEnv
+		want         []string
+		wantErr      bool
+		wantRequests int32
+	}{
+		{GoEnv{GOPROXY: good}, []string{"v1.0.0", "v1.1.0"}, false, 1},
+		{GoEnv{GOPROXY: notFound + "," + good}, []string{"v1.0.0", "v1.1.0"}, false, 2},
+		{GoEnv{GOPROXY: broken + "," + good}, nil, true, 1},
+		{GoEnv{GOPROXY: broken + "|" + good}, []string{"v1.0.0", "v1.1.0"}, false, 2},
+		{GoEnv{GOPROXY: notFound + ",direct"}, nil, false, 1},
+		{GoEnv{GOPROXY: "off," + good}, nil, false, 0},
+		{GoEnv{GOPROXY: good, GOPRIVATE: "example.com"}, nil, false, 0},
+		{GoEnv{GOPROXY: good, GONOPROXY: "*.com", GOPRIVATE: "other.org"}, nil, false, 0},
+		{GoEnv{GOPROXY: good, GONOPROXY: "example.com/other"}, []string{"v1.0.0", "v1.1.0"}, false, 1},
+	}
+	for _, test := range tests {
+		requests.Store(0)
+		got, err := listProxyVersions(context.Background(), &test.env, "example.com/mod")
+		if (err != nil) != test.wantErr {
+			t.Errorf("%+v: got error %v, want error: %t", test.env, err, test.wantErr)
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%+v: got %v, want %v", test.env, got, test.want)
+		}
+		if n := requests.Load(); n != test.wantRequests {
+			t.Errorf("%+v: made %d requests, want %d", test.env, n, test.wantRequests)
+		}
+	}
+}
@@ -269,6 +269,8 @@ func (s *Session) createView(ctx context.Context, def *viewDefinition) (*View, *
 		modWhyHandles:     new(persistent.Map[protocol.DocumentURI, *memoize.Promise]),
 		moduleUpgrades:    new(persistent.Map[protocol.DocumentURI, map[string]string]),
 		vulns:             new(persistent.Map[protocol.DocumentURI, *vulncheck.Result]),
+
+		moduleProxyVersions: new(persistent.Map[string, *memoize.Promise]),
 	}
 
 	// Snapshots must observe all open files, as there are some caching
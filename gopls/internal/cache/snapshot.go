@@ -183,6 +183,15 @@ type Snapshot struct {
 	// vulns maps each go.mod file's URI to its known vulnerabilities.
 	vulns *persistent.Map[protocol.DocumentURI, *vulncheck.Result]
 
+	// moduleProxyVersions memoizes the versions of each module
+	// listed by the module proxies, by module path.
+	moduleProxyVersions *persistent.Map[string, *memoize.Promise] // *memoize.Promise[moduleProxyVersionsResult]
+
+	// templateDataTypes memoizes the types of the data of the
+	// templates executed by workspace packages. It is not
+	// inherited by clones.
+	templateDataTypes *memoize.Promise // *memoize.Promise[templateDataTypesResult]; nil until needed
+
 	// compilerOptDetails is the set of directories whose packages
 	// and tests need compiler optimization details in the diagnostics.
 	compilerOptDetails map[protocol.DocumentURI]unit
@@ -240,6 +249,7 @@ func (s *Snapshot) decref() {
 		s.unloadableFiles.Destroy()
 		s.moduleUpgrades.Destroy()
 		s.vulns.Destroy()
+		s.moduleProxyVersions.Destroy()
 		s.done()
 	}
 }
@@ -1543,6 +1553,8 @@ func (s *Snapshot) clone(ctx, bgCtx context.Context, changed StateChange, done f
 		modVulnHandles:    cloneWithout(s.modVulnHandles, changedFiles, &needsDiagnosis),
 		moduleUpgrades:    cloneWith(s.moduleUpgrades, changed.ModuleUpgrades),
 		vulns:             cloneWith(s.vulns, changed.Vulns),
+
+		moduleProxyVersions: s.moduleProxyVersions.Clone(), // not cloneWithout: independent of workspace files
 	}
 
 	// Compute the new set of packages for which we want compiler
@@ -0,0 +1,231 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"path/filepath"
+
+	"golang.org/x/tools/go/types/typeutil"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/internal/event"
+	"golang.org/x/tools/internal/memoize"
+)
+
+// A TemplateDataType is the Go type of the data with which a
+// template is executed.
+type TemplateDataType struct {
+	Type    types.Type     // nil if executed with data of different types
+	FileSet *token.FileSet // positions of the symbols of Type
+}
+
+// TemplateDataTypes returns the types of the data of the templates
+// executed by the workspace packages of the snapshot, by template
+// name. A template is executed by a call such as
+// t.ExecuteTemplate(w, "page.tmpl", data), or t.Execute(w, data)
+// where t was created by template.ParseFiles("page.tmpl").
+//
+// Finding the calls requires type checking every workspace package
+// that imports text/template or html/template, so the result is
+// memoized for the lifetime of the snapshot. It is not inherited by
+// subsequent snapshots, since any change to those packages may
+// affect it.
+func (s *Snapshot) TemplateDataTypes(ctx context.Context) (map[string]*TemplateDataType, error) {
+	type templateDataTypesResult struct {
+		byName map[string]*TemplateDataType
+		err    error
+	}
+
+	s.mu.Lock()
+	if s.templateDataTypes == nil {
+		s.templateDataTypes = memoize.NewPromise("templateDataTypes", func(ctx context.Context, arg any) any {
+			byName, err := templateDataTypesImpl(ctx, arg.(*Snapshot))
+			return templateDataTypesResult{byName, err}
+		})
+	}
+	entry := s.templateDataTypes
+	s.mu.Unlock()
+
+	// Await result.
+	v, err := s.awaitPromise(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	res := v.(templateDataTypesResult)
+	return res.byName, res.err
+}
+
+// templateDataTypesImpl implements TemplateDataTypes.
+func templateDataTypesImpl(ctx context.Context, snapshot *Snapshot) (map[string]*TemplateDataType, error) {
+	ctx, done := event.Start(ctx, "cache.TemplateDataTypes")
+	defer done()
+
+	mps, err := snapshot.WorkspaceMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var ids []metadata.PackageID
+	for _, mp := range mps {
+		if mp.DepsByPkgPath["text/template"] != "" || mp.DepsByPkgPath["html/template"] != "" {
+			ids = append(ids, mp.ID)
+		}
+	}
+	byName := make(map[string]*TemplateDataType)
+	if len(ids) == 0 {
+		return byName, nil
+	}
+	pkgs, err := snapshot.TypeCheck(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range pkgs {
+		addTemplateCalls(byName, pkg)
+	}
+	return byName, nil
+}
+
+// addTemplateCalls records in byName the types of the data of the
+// templates executed by the package.
+func addTemplateCalls(byName map[string]*TemplateDataType, pkg *Package) {
+	info := pkg.TypesInfo()
+
+	// First, find the names of the templates created by
+	// ParseFiles and assigned to variables.
+	names := make(map[types.Object]string)
+	bind := func(lhs []*ast.Ident, rhs []ast.Expr) {
+		if len(lhs) != len(rhs) {
+			return
+		}
+		for i, id := range lhs {
+			if name := templateName(info, rhs[i]); name != "" {
+				if obj := info.ObjectOf(id); obj != nil {
+					names[obj] = name
+				}
+			}
+		}
+	}
+	for _, pgf := range pkg.CompiledGoFiles() {
+		ast.Inspect(pgf.File, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				var lhs []*ast.Ident
+				for _, e := range n.Lhs {
+					id, _ := e.(*ast.Ident)
+					lhs = append(lhs, id)
+				}
+				bind(lhs, n.Rhs)
+			case *ast.ValueSpec:
+				bind(n.Names, n.Values)
+			}
+			return true
+		})
+	}
+
+	// Then find the calls that execute them.
+	for _, pgf := range pkg.CompiledGoFiles() {
+		ast.Inspect(pgf.File, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			switch fn := typeutil.Callee(info, call); {
+			case isTemplateFunc(fn, "Execute") && len(call.Args) == 2:
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok {
+					break
+				}
+				var name string
+				if id, ok := ast.Unparen(sel.X).(*ast.Ident); ok {
+					name = names[info.Uses[id]]
+				} else {
+					name = templateName(info, sel.X)
+				}
+				addTemplateDataType(byName, name, info.TypeOf(call.Args[1]), pkg.FileSet())
+
+			case isTemplateFunc(fn, "ExecuteTemplate") && len(call.Args) == 3:
+				addTemplateDataType(byName, stringConstant(info, call.Args[1]), info.TypeOf(call.Args[2]), pkg.FileSet())
+			}
+			return true
+		})
+	}
+}
+
+// addTemplateDataType records in byName that the template of the
+// specified name is executed with data of type t.
+func addTemplateDataType(byName map[string]*TemplateDataType, name string, t types.Type, fset *token.FileSet) {
+	if name == "" || t == nil || types.IsInterface(t) {
+		return // unknown template or type
+	}
+	if basic, ok := t.(*types.Basic); ok && basic.Kind() == types.UntypedNil {
+		return
+	}
+	if prev, ok := byName[name]; ok {
+		if prev.Type != nil && types.TypeString(prev.Type, nil) != types.TypeString(t, nil) {
+			prev.Type = nil // ambiguous
+		}
+		return
+	}
+	byName[name] = &TemplateDataType{Type: t, FileSet: fset}
+}
+
+// templateName returns the name of the template created by the
+// expression e, if it is a call to ParseFiles (possibly wrapped in a
+// call to Must), or "" if unknown.
+//
+// The name of the template returned by the ParseFiles function is
+// the base name of its first file; that of the ParseFiles method is
+// the name of its receiver, if it is created by a call to New.
+func templateName(info *types.Info, e ast.Expr) string {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+	fn := typeutil.Callee(info, call)
+	if isTemplateFunc(fn, "Must") && len(call.Args) == 1 {
+		return templateName(info, call.Args[0])
+	}
+	if !isTemplateFunc(fn, "ParseFiles") {
+		return ""
+	}
+	if fn.(*types.Func).Signature().Recv() != nil {
+		// t.ParseFiles(...): use the name of t from New(name).
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return ""
+		}
+		recv, ok := ast.Unparen(sel.X).(*ast.CallExpr)
+		if !ok || !isTemplateFunc(typeutil.Callee(info, recv), "New") || len(recv.Args) != 1 {
+			return ""
+		}
+		return stringConstant(info, recv.Args[0])
+	}
+	if len(call.Args) == 0 {
+		return ""
+	}
+	return filepath.Base(stringConstant(info, call.Args[0]))
+}
+
+// stringConstant returns the value of the string constant e, or "".
+func stringConstant(info *types.Info, e ast.Expr) string {
+	if tv, ok := info.Types[e]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+		return constant.StringVal(tv.Value)
+	}
+	return ""
+}
+
+// isTemplateFunc reports whether obj is the function or method of
+// the specified name in package text/template or html/template.
+func isTemplateFunc(obj types.Object, name string) bool {
+	fn, ok := obj.(*types.Func)
+	if !ok || fn.Name() != name || fn.Pkg() == nil {
+		return false
+	}
+	path := fn.Pkg().Path()
+	return path == "text/template" || path == "html/template"
+}
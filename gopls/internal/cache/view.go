@@ -65,6 +65,8 @@ type GoEnv struct {
 	GOMODCACHE  string
 	GOPATH      string
 	GOPRIVATE   string
+	GONOPROXY   string
+	GOPROXY     string
 	GOFLAGS     string
 	GO111MODULE string
 	GOTOOLCHAIN string
@@ -1014,6 +1016,8 @@ func FetchGoEnv(ctx context.Context, folder protocol.DocumentURI, opts *settings
 		"GOCACHE":     &env.GOCACHE,
 		"GOPATH":      &env.GOPATH,
 		"GOPRIVATE":   &env.GOPRIVATE,
+		"GONOPROXY":   &env.GONOPROXY,
+		"GOPROXY":     &env.GOPROXY,
 		"GOMODCACHE":  &env.GOMODCACHE,
 		"GOFLAGS":     &env.GOFLAGS,
 		"GO111MODULE": &env.GO111MODULE,
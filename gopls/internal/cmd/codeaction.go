@@ -58,19 +58,27 @@ Valid kinds include:
 	refactor.extract.variable
 	refactor.inline
 	refactor.inline.call
+	refactor.inline.constant
+	refactor.inline.variable
 	refactor.rewrite
+	refactor.rewrite.addTags
 	refactor.rewrite.changeQuote
 	refactor.rewrite.fillStruct
 	refactor.rewrite.fillSwitch
+	refactor.rewrite.ifToSwitch
 	refactor.rewrite.invertIf
 	refactor.rewrite.joinLines
+	refactor.rewrite.removeTags
 	refactor.rewrite.removeUnusedParam
+	refactor.rewrite.safeDelete
 	refactor.rewrite.splitLines
+	refactor.rewrite.switchToIf
 	source
 	source.assembly
 	source.doc
 	source.fixAll
 	source.freesymbols
+	source.generateStringer
 	source.organizeImports
 	source.test
 
@@ -4,12 +4,15 @@
 
 package golang
 
-// This file produces the "Browse GOARCH assembly of f" HTML report.
+// This file produces the "Browse GOARCH assembly of f" report, as HTML
+// or as plain text.
 //
 // See also:
 // - ./codeaction.go - computes the symbol and offers the CodeAction command.
 // - ../server/command.go - handles the command by opening a web page.
 // - ../server/server.go - handles the HTTP request and calls this function.
+// - ../server/text_document_content.go - handles the request for
+//   the plain-text document.
 //
 // For language-server behavior in Go assembly language files,
 // see [golang.org/x/tools/gopls/internal/goasm].
@@ -20,6 +23,7 @@ import (
 	"fmt"
 	"html"
 	"io"
+	"iter"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -107,25 +111,7 @@ document.getElementById('compiling').innerText = %q;
 	// Submatch groups are: (offset-hex-dec, file-line-column, instruction).
 	insnRx := regexp.MustCompile(`^(\s+0x[0-9a-f ]+)\(([^)]*)\)\s+(.*)$`)
 
-	// Parse the functions of interest out of the listing.
-	// Each function is of the form:
-	//
-	//     symbol STEXT k=v...
-	//         0x0000 00000 (/file.go:123) NOP...
-	//         ...
-	//
-	// Allow matches of symbol, symbol.func1, symbol.deferwrap, etc.
-	on := false
-	for line := range strings.SplitSeq(content, "\n") {
-		// start of function symbol?
-		if strings.Contains(line, " STEXT ") {
-			on = strings.HasPrefix(line, symbol) &&
-				(line[len(symbol)] == ' ' || line[len(symbol)] == '.')
-		}
-		if !on {
-			continue // within uninteresting symbol
-		}
-
+	for line := range symbolAssembly(content, symbol) {
 		// In lines of the form
 		//   "\t0x0000 00000 (/file.go:123) NOP..."
 		// replace the "(/file.go:123)" portion with an "L0123" source link.
@@ -145,3 +131,53 @@ document.getElementById('compiling').innerText = %q;
 		buf.WriteByte('\n')
 	}
 }
+
+// AssemblyText returns a plain-text assembly listing of the selected
+// function, for display in the client editor as a read-only document.
+func AssemblyText(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, symbol string) ([]byte, error) {
+	inv, cleanupInvocation, err := snapshot.GoCommandInvocation(cache.NoNetwork, pkg.Metadata().CompiledGoFiles[0].DirPath(), "build", []string{"-gcflags=-S", "."})
+	if err != nil {
+		return nil, err
+	}
+	defer cleanupInvocation()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// %s assembly for %s\n", snapshot.View().GOARCH(), symbol)
+	fmt.Fprintf(&buf, "// See https://go.dev/doc/asm for A Quick Guide to Go's Assembler.\n\n")
+
+	_, stderr, err, _ := snapshot.View().GoCommandRunner().RunRaw(ctx, *inv)
+	if err != nil {
+		return nil, fmt.Errorf("compilation failed: %v", err)
+	}
+	for line := range symbolAssembly(stderr.String(), symbol) {
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// symbolAssembly returns an iterator over the lines of the compiler's
+// assembly listing that belong to the specified function symbol.
+//
+// Each function is of the form:
+//
+//	symbol STEXT k=v...
+//	    0x0000 00000 (/file.go:123) NOP...
+//	    ...
+//
+// Matches include symbol, symbol.func1, symbol.deferwrap, etc.
+func symbolAssembly(listing, symbol string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		on := false
+		for line := range strings.SplitSeq(listing, "\n") {
+			// start of function symbol?
+			if strings.Contains(line, " STEXT ") {
+				on = strings.HasPrefix(line, symbol) &&
+					(line[len(symbol)] == ' ' || line[len(symbol)] == '.')
+			}
+			if on && !yield(line) {
+				return
+			}
+		}
+	}
+}
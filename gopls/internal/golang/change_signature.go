@@ -6,20 +6,26 @@ package golang
 
 import (
 	"bytes"
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/format"
 	"go/parser"
 	"go/token"
 	"go/types"
+	"maps"
 	"regexp"
+	"slices"
+	"strings"
 
 	"golang.org/x/tools/go/ast/astutil"
 	"golang.org/x/tools/gopls/internal/cache"
 	"golang.org/x/tools/gopls/internal/cache/parsego"
 	"golang.org/x/tools/gopls/internal/file"
 	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
 	goplsastutil "golang.org/x/tools/gopls/internal/util/astutil"
 	"golang.org/x/tools/gopls/internal/util/bug"
 	"golang.org/x/tools/gopls/internal/util/safetoken"
@@ -120,34 +126,149 @@ func removeParam(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle,
 		return nil, fmt.Errorf("no param found")
 	}
 	// Write a transformation to remove the param.
-	var newParams []int
+	var newParams []command.ChangeSignatureParam
 	for i := 0; i < info.decl.Type.Params.NumFields(); i++ {
 		if i != info.paramIndex {
-			newParams = append(newParams, i)
+			newParams = append(newParams, command.ChangeSignatureParam{OldIndex: i})
 		}
 	}
-	return ChangeSignature(ctx, snapshot, pkg, pgf, rng, newParams)
+	return ChangeSignature(ctx, snapshot, pkg, pgf, rng, newParams, nil, false)
 }
 
 // ChangeSignature computes a refactoring to update the signature according to
-// the provided parameter transformation, for the signature definition
-// surrounding rng.
+// the provided parameter and result transformations, for the signature
+// definition surrounding rng.
 //
 // newParams expresses the new parameters for the signature in terms of the old
-// parameters. Each entry in newParams is the index of the new parameter in the
-// original parameter list. For example, given func Foo(a, b, c int) and newParams
-// [2, 0, 1], the resulting changed signature is Foo(c, a, b int). If newParams
-// omits an index of the original signature, that parameter is removed.
+// parameters, as described at [command.ChangeSignatureArgs]. For example,
+// given func Foo(a, b, c int) and newParams [2, 0, 1], the resulting changed
+// signature is Foo(c, a, b int). If newParams omits an index of the original
+// signature, that parameter is removed. Elements of newParams that define a
+// new field add a parameter, whose default value is passed by each call.
+//
+// newResults likewise expresses the new results. If it is nil, the results
+// are unchanged.
+//
+// If the declaration is a method and updateInterfaces is set, the
+// corresponding methods of the interfaces it implements, and of all other
+// types that implement those interfaces, are changed too, and dynamic calls
+// through an interface, which cannot be rewritten, are conflicts. Otherwise
+// only the method itself and its static calls are changed, and dynamic calls
+// are left alone. References that are not calls are always conflicts. If
+// there are any conflicts, ChangeSignature returns an error describing them.
 //
 // This operation is a work in progress. Remaining TODO:
-//   - Handle adding parameters.
-//   - Handle adding/removing/reordering results.
+//   - Rewrite dynamic calls of interface methods.
 //   - Improve the extra newlines in output.
 //   - Stream type checking via ForEachPackage.
 //   - Avoid unnecessary additional type checking.
-func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, rng protocol.Range, newParams []int) ([]protocol.DocumentChange, error) {
-	// Changes to our heuristics for whether we can remove a parameter must also
-	// be reflected in the canRemoveParameter helper.
+func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, rng protocol.Range, newParams, newResults []command.ChangeSignatureParam, updateInterfaces bool) ([]protocol.DocumentChange, error) {
+	if err := checkPackageErrors(pkg); err != nil {
+		return nil, err
+	}
+
+	info := findParam(pgf, rng)
+	if info == nil || info.decl == nil {
+		return nil, fmt.Errorf("failed to find declaration")
+	}
+	if newResults == nil {
+		newResults = identityTransform(info.decl.Type.Results)
+	}
+
+	// A method may change along with its corresponding methods.
+	decls := []sigDecl{{pkg: pkg, pgf: pgf, decl: info.decl}}
+	if info.decl.Recv != nil && updateInterfaces {
+		related, err := relatedMethods(ctx, snapshot, pgf, info.decl)
+		if err != nil {
+			return nil, err
+		}
+		decls = append(decls, related...)
+	}
+
+	// Compute the edits to each file affected by changing each declaration,
+	// merging the edits to the same file.
+	var (
+		edits     = make(map[protocol.DocumentURI][]diff.Edit)
+		conflicts = make(map[token.Position]string) // references that cannot be rewritten
+	)
+	for _, d := range decls {
+		if err := checkPackageErrors(d.pkg); err != nil {
+			return nil, err
+		}
+		var newContent map[protocol.DocumentURI][]byte
+		if d.decl != nil {
+			content, err := changeFuncSignature(ctx, snapshot, d, newParams, newResults, conflicts)
+			if err != nil {
+				return nil, err
+			}
+			newContent = content
+		} else {
+			src, err := changeMethodSignature(d, newParams, newResults)
+			if err != nil {
+				return nil, err
+			}
+			newContent = map[protocol.DocumentURI][]byte{d.pgf.URI: src}
+		}
+		for uri, after := range newContent {
+			fh, err := snapshot.ReadFile(ctx, uri)
+			if err != nil {
+				return nil, err
+			}
+			before, err := fh.Content()
+			if err != nil {
+				return nil, err
+			}
+			merged, ok := diff.Merge(edits[uri], diff.Bytes(before, after))
+			if !ok {
+				return nil, fmt.Errorf("conflicting edits to %s", uri.Path())
+			}
+			edits[uri] = merged
+		}
+	}
+	if !updateInterfaces {
+		// Dynamic calls are unaffected by the change
+		// (though the type may no longer implement an interface).
+		maps.DeleteFunc(conflicts, func(_ token.Position, desc string) bool { return desc == dynamicCall })
+	}
+	if len(conflicts) > 0 {
+		posns := slices.SortedFunc(maps.Keys(conflicts), func(x, y token.Position) int {
+			return cmp.Or(strings.Compare(x.Filename, y.Filename), x.Offset-y.Offset)
+		})
+		var buf strings.Builder
+		buf.WriteString("cannot change signature: found references that cannot be updated:")
+		for _, posn := range posns {
+			fmt.Fprintf(&buf, "\n\t%s: %s", posn, conflicts[posn])
+		}
+		return nil, errors.New(buf.String())
+	}
+
+	// Translate the resulting state into document changes.
+	var changes []protocol.DocumentChange
+	for _, uri := range slices.Sorted(maps.Keys(edits)) {
+		fh, err := snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+		before, err := fh.Content()
+		if err != nil {
+			return nil, err
+		}
+		mapper := protocol.NewMapper(uri, before)
+		textedits, err := protocol.EditsFromDiffEdits(mapper, edits[uri])
+		if err != nil {
+			return nil, fmt.Errorf("computing edits for %s: %v", uri, err)
+		}
+		change := protocol.DocumentChangeEdit(fh, textedits)
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// checkPackageErrors returns an error if pkg has parse or type errors.
+//
+// Changes to our heuristics for whether we can remove a parameter must also
+// be reflected in the canRemoveParameter helper.
+func checkPackageErrors(pkg *cache.Package) error {
 	if perrors, terrors := pkg.ParseErrors(), pkg.TypeErrors(); len(perrors) > 0 || len(terrors) > 0 {
 		var sample string
 		if len(perrors) > 0 {
@@ -155,79 +276,320 @@ func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 		} else {
 			sample = terrors[0].Error()
 		}
-		return nil, fmt.Errorf("can't change signatures for packages with parse or type errors: (e.g. %s)", sample)
+		return fmt.Errorf("can't change signatures for packages with parse or type errors: (e.g. %s)", sample)
 	}
+	return nil
+}
 
-	info := findParam(pgf, rng)
-	if info == nil || info.decl == nil {
-		return nil, fmt.Errorf("failed to find declaration")
+// A sigDecl is a function or method whose signature is to be changed:
+// either a function declaration, or a method of an interface type.
+type sigDecl struct {
+	pkg   *cache.Package
+	pgf   *parsego.File
+	decl  *ast.FuncDecl // function declaration, or nil for an interface method
+	field *ast.Field    // interface method, if decl is nil
+}
+
+// funcType returns the function type of the declaration.
+func (d sigDecl) funcType() *ast.FuncType {
+	if d.decl != nil {
+		return d.decl.Type
 	}
+	return d.field.Type.(*ast.FuncType)
+}
 
-	// Step 1: create the new declaration, which is a copy of the original decl
-	// with the rewritten signature.
+// relatedMethods returns the methods whose signatures must change along with
+// that of the method declaration decl: the methods of interfaces that it
+// implements, and the methods of other types that implement those
+// interfaces, transitively.
+//
+// It is an error for any of these methods to be declared outside the
+// workspace, since they cannot be changed.
+func relatedMethods(ctx context.Context, snapshot *cache.Snapshot, pgf *parsego.File, decl *ast.FuncDecl) ([]sigDecl, error) {
+	loc, err := pgf.NodeLocation(decl.Name)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		related []sigDecl
+		seen    = map[protocol.Location]bool{loc: true}
+		queue   = []protocol.Location{loc}
+	)
+	for len(queue) > 0 {
+		loc := queue[0]
+		queue = queue[1:]
+		fh, err := snapshot.ReadFile(ctx, loc.URI)
+		if err != nil {
+			return nil, err
+		}
+		impls, err := Implementation(ctx, snapshot, fh, loc.Range.Start)
+		if err != nil {
+			return nil, fmt.Errorf("finding corresponding methods: %v", err)
+		}
+		for _, impl := range impls {
+			if seen[impl] {
+				continue
+			}
+			seen[impl] = true
+			d, err := methodAt(ctx, snapshot, impl)
+			if err != nil {
+				return nil, err
+			}
+			related = append(related, d)
+			queue = append(queue, impl)
+		}
+	}
+	return related, nil
+}
 
-	// Flatten, transform and regroup fields, using the flatField intermediate
-	// representation. A flatField is the result of flattening an *ast.FieldList
-	// along with type information.
-	type flatField struct {
-		name     string // empty if the field is unnamed
-		typeExpr ast.Expr
-		typ      types.Type
+// methodAt returns the method declared by the name at loc, which must be
+// either a method declaration or an interface method in a workspace
+// package.
+func methodAt(ctx context.Context, snapshot *cache.Snapshot, loc protocol.Location) (sigDecl, error) {
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, loc.URI)
+	if err != nil {
+		return sigDecl{}, fmt.Errorf("cannot change corresponding method in %s: %v", loc.URI.Path(), err)
 	}
+	if !snapshot.IsWorkspacePackage(pkg.Metadata().ID) {
+		return sigDecl{}, fmt.Errorf("cannot change corresponding method in %s: package %s is outside the workspace", loc.URI.Path(), pkg.Metadata().PkgPath)
+	}
+	start, end, err := pgf.RangePos(loc.Range)
+	if err != nil {
+		return sigDecl{}, err
+	}
+	path, _ := astutil.PathEnclosingInterval(pgf.File, start, end)
+	if len(path) >= 2 {
+		switch n := path[1].(type) {
+		case *ast.FuncDecl:
+			if n.Name == path[0] {
+				return sigDecl{pkg: pkg, pgf: pgf, decl: n}, nil
+			}
+		case *ast.Field:
+			if is[*ast.FuncType](n.Type) && len(path) >= 4 && is[*ast.InterfaceType](path[3]) {
+				return sigDecl{pkg: pkg, pgf: pgf, field: n}, nil
+			}
+		}
+	}
+	return sigDecl{}, bug.Errorf("no method declaration at %v", loc)
+}
 
-	var newParamFields []flatField
-	for id, field := range goplsastutil.FlatFields(info.decl.Type.Params) {
-		typ := pkg.TypesInfo().TypeOf(field.Type)
+// A sigField is a field of a changed signature: either a field of the old
+// signature, or a new field.
+type sigField struct {
+	name     string // empty if the field is unnamed
+	typeExpr ast.Expr
+	typ      types.Type
+	old      int      // index of the field in the old signature, or -1 if new
+	value    ast.Expr // for a new field, the argument or returned value
+}
+
+// newFields returns the fields of the signature of d resulting from applying
+// the transformation to the old field list (its parameters or results, as
+// described by kind).
+func newFields(d sigDecl, old *ast.FieldList, transform []command.ChangeSignatureParam, kind string) ([]sigField, error) {
+	var oldFields []sigField
+	for id, field := range goplsastutil.FlatFields(old) {
+		typ := d.pkg.TypesInfo().TypeOf(field.Type)
 		if typ == nil {
-			return nil, fmt.Errorf("missing field type for field #%d", len(newParamFields))
+			return nil, fmt.Errorf("missing field type for field #%d", len(oldFields))
 		}
-		field := flatField{
+		f := sigField{
 			typeExpr: field.Type,
 			typ:      typ,
+			old:      len(oldFields),
 		}
 		if id != nil {
-			field.name = id.Name
+			f.name = id.Name
 		}
-		newParamFields = append(newParamFields, field)
+		oldFields = append(oldFields, f)
 	}
 
-	// Select the new parameter fields.
-	newParamFields, ok := selectElements(newParamFields, newParams)
-	if !ok {
-		return nil, fmt.Errorf("failed to apply parameter transformation %v", newParams)
+	var (
+		fields []sigField
+		seen   = make(map[int]bool) // old fields seen
+		fset   = tokeninternal.FileSetFor(d.pgf.Tok)
+	)
+	for _, p := range transform {
+		var f sigField
+		if p.NewField == "" {
+			if p.OldIndex < 0 || p.OldIndex >= len(oldFields) {
+				return nil, fmt.Errorf("%s index %d out of range", kind, p.OldIndex)
+			}
+			if seen[p.OldIndex] {
+				return nil, fmt.Errorf("%s %d appears more than once", kind, p.OldIndex)
+			}
+			if p.Default != "" {
+				return nil, fmt.Errorf("%s %d is not new, so cannot have a default", kind, p.OldIndex)
+			}
+			seen[p.OldIndex] = true
+			f = oldFields[p.OldIndex]
+		} else {
+			var err error
+			f, err = newField(d, fset, p, kind)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if p.Name != "" {
+			if !token.IsIdentifier(p.Name) {
+				return nil, fmt.Errorf("invalid %s name %q", kind, p.Name)
+			}
+			f.name = p.Name
+		}
+		fields = append(fields, f)
 	}
 
-	// writeFields performs the regrouping of named fields.
-	writeFields := func(flatFields []flatField) *ast.FieldList {
-		list := new(ast.FieldList)
-		for i, f := range flatFields {
-			var field *ast.Field
-			if i > 0 && f.name != "" && flatFields[i-1].name != "" && types.Identical(f.typ, flatFields[i-1].typ) {
-				// Group named fields if they have the same type.
-				field = list.List[len(list.List)-1]
-			} else {
-				// Otherwise, create a new field.
-				field = &ast.Field{
-					Type: internalastutil.CloneNode(f.typeExpr),
-				}
-				list.List = append(list.List, field)
+	for i, f := range fields {
+		if is[*ast.Ellipsis](f.typeExpr) && (kind != "parameter" || i < len(fields)-1) {
+			return nil, fmt.Errorf("only the final parameter can be variadic")
+		}
+	}
+
+	// The fields of a list must be all named or all unnamed.
+	if slices.ContainsFunc(fields, func(f sigField) bool { return f.name != "" }) {
+		for i := range fields {
+			if fields[i].name == "" {
+				fields[i].name = "_"
 			}
-			if f.name != "" {
-				field.Names = append(field.Names, ast.NewIdent(f.name))
+		}
+	}
+	return fields, nil
+}
+
+// newField parses the definition of a new field of the signature of d,
+// and its default value. Syntax is parsed into fset, so that its
+// positions do not appear to be those of the declaring file.
+func newField(d sigDecl, fset *token.FileSet, p command.ChangeSignatureParam, kind string) (sigField, error) {
+	expr, err := parser.ParseExprFrom(fset, "", "func("+p.NewField+")", parser.SkipObjectResolution)
+	ftyp, _ := expr.(*ast.FuncType)
+	if err != nil || ftyp == nil || len(ftyp.Params.List) != 1 || len(ftyp.Params.List[0].Names) > 1 {
+		return sigField{}, fmt.Errorf("invalid new %s %q", kind, p.NewField)
+	}
+	field := ftyp.Params.List[0]
+	f := sigField{old: -1}
+	if len(field.Names) > 0 {
+		f.name = field.Names[0].Name
+	}
+
+	// Type-check the type in the scope of the declaration.
+	typeExpr := field.Type
+	if ellipsis, ok := typeExpr.(*ast.Ellipsis); ok {
+		typeExpr = ellipsis.Elt
+	}
+	info := &types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+	if err := types.CheckExpr(fset, d.pkg.Types(), d.funcType().Params.Opening, typeExpr, info); err != nil {
+		return sigField{}, fmt.Errorf("invalid type for new %s %q: %v", kind, p.NewField, err)
+	}
+	tv := info.Types[typeExpr]
+	if !tv.IsType() {
+		return sigField{}, fmt.Errorf("invalid type for new %s %q: %s is not a type", kind, p.NewField, types.ExprString(typeExpr))
+	}
+	// Use syntax for the type without positions, which would confuse the
+	// formatting of the signature.
+	qual := typesinternal.FileQualifier(d.pgf.File, d.pkg.Types())
+	if typeExpr != field.Type {
+		f.typ = types.NewSlice(tv.Type)
+		f.typeExpr = &ast.Ellipsis{Elt: typesinternal.TypeExpr(tv.Type, qual)}
+	} else {
+		f.typ = tv.Type
+		f.typeExpr = typesinternal.TypeExpr(tv.Type, qual)
+	}
+
+	// The default value is type-checked along with the rewritten package.
+	if p.Default != "" {
+		f.value, err = parser.ParseExprFrom(fset, "", p.Default, parser.SkipObjectResolution)
+		if err != nil {
+			return sigField{}, fmt.Errorf("invalid default for new %s %q: %v", kind, p.NewField, err)
+		}
+	} else if zero, ok := typesinternal.ZeroExpr(f.typ, qual); ok {
+		f.value = zero
+	} else if !is[*ast.Ellipsis](field.Type) {
+		return sigField{}, fmt.Errorf("new %s %q has no zero value: specify a default", kind, p.NewField)
+	}
+	return f, nil
+}
+
+// writeFields performs the regrouping of named fields.
+func writeFields(fields []sigField) *ast.FieldList {
+	list := new(ast.FieldList)
+	for i, f := range fields {
+		var field *ast.Field
+		if i > 0 && f.name != "" && fields[i-1].name != "" && types.Identical(f.typ, fields[i-1].typ) && !is[*ast.Ellipsis](f.typeExpr) {
+			// Group named fields if they have the same type.
+			field = list.List[len(list.List)-1]
+		} else {
+			// Otherwise, create a new field.
+			field = &ast.Field{
+				Type: internalastutil.CloneNode(f.typeExpr),
 			}
+			list.List = append(list.List, field)
+		}
+		if f.name != "" {
+			field.Names = append(field.Names, ast.NewIdent(f.name))
+		}
+	}
+	return list
+}
+
+// isIdentity reports whether the fields are those of the old field list,
+// unchanged.
+func isIdentity(fields []sigField, old *ast.FieldList) bool {
+	if len(fields) != old.NumFields() {
+		return false
+	}
+	i := 0
+	for id := range goplsastutil.FlatFields(old) {
+		if fields[i].old != i || id != nil && fields[i].name != id.Name {
+			return false
 		}
-		return list
+		i++
+	}
+	return true
+}
+
+// changeFuncSignature returns the new content of the files affected by
+// changing the signature of the function declaration d: its declaring file,
+// and the files of its calls. It records references that cannot be
+// rewritten in conflicts.
+func changeFuncSignature(ctx context.Context, snapshot *cache.Snapshot, d sigDecl, newParams, newResults []command.ChangeSignatureParam, conflicts map[token.Position]string) (map[protocol.DocumentURI][]byte, error) {
+	var (
+		pkg  = d.pkg
+		pgf  = d.pgf
+		decl = d.decl
+	)
+
+	// Step 1: create the new declaration, which is a copy of the original decl
+	// with the rewritten signature.
+
+	// Flatten, transform and regroup fields, using the sigField intermediate
+	// representation.
+	newParamFields, err := newFields(d, decl.Type.Params, newParams, "parameter")
+	if err != nil {
+		return nil, err
+	}
+	newResultFields, err := newFields(d, decl.Type.Results, newResults, "result")
+	if err != nil {
+		return nil, err
+	}
+	resultsChanged := !isIdentity(newResultFields, decl.Type.Results)
+	if err := checkFieldNames(d, newParamFields, newResultFields); err != nil {
+		return nil, err
 	}
 
-	newDecl := internalastutil.CloneNode(info.decl)
+	newDecl := internalastutil.CloneNode(decl)
 	newDecl.Type.Params = writeFields(newParamFields)
+	newDecl.Type.Results = writeFields(newResultFields)
+	bodyEdits, err := rewriteBody(d, newDecl, newParamFields, newResultFields)
+	if err != nil {
+		return nil, err
+	}
 
 	// Step 2: build a wrapper function calling the new declaration.
 
 	var (
-		params   = internalastutil.CloneNode(info.decl.Type.Params) // parameters of wrapper func: "_" names must be modified
-		args     = make([]ast.Expr, len(newParams))                 // arguments to the delegated call
-		variadic = false                                            // whether the signature is variadic
+		params   = internalastutil.CloneNode(decl.Type.Params) // parameters of wrapper func: "_" names must be modified
+		args     = make([]ast.Expr, 0, len(newParamFields))    // arguments to the delegated call
+		variadic = false                                       // whether the signature is variadic
 	)
 	{
 		// Record names used by non-blank parameters, just in case the user had a
@@ -247,23 +609,16 @@ func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 				fld.Names = append(fld.Names, ast.NewIdent("_")) // will be named below
 			}
 		}
-		// oldParams maps parameters to their argument in the delegated call.
-		// In other words, it is the inverse of newParams, but it is represented as
-		// a map rather than a slice, as not every old param need exist in
-		// newParams.
-		oldParams := make(map[int]int)
-		for new, old := range newParams {
-			oldParams[old] = new
+		kept := make(map[int]bool) // old parameters that are kept
+		for _, f := range newParamFields {
+			if f.old >= 0 {
+				kept[f.old] = true
+			}
 		}
+		var oldNames []string // names of the wrapper's parameters
 		blanks := 0
-		paramIndex := 0 // global param index.
-		for id, field := range goplsastutil.FlatFields(params) {
-			argIndex, ok := oldParams[paramIndex]
-			paramIndex++
-			if !ok {
-				continue // parameter is removed
-			}
-			if id.Name == "_" { // from above: every field has names
+		for id := range goplsastutil.FlatFields(params) {
+			if id.Name == "_" && kept[len(oldNames)] { // from above: every field has names
 				// Create names for blank (_) parameters so the delegating wrapper
 				// can refer to them.
 				for {
@@ -277,26 +632,55 @@ func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 					}
 				}
 			}
-			args[argIndex] = ast.NewIdent(id.Name)
+			oldNames = append(oldNames, id.Name)
+		}
+		for i, f := range newParamFields {
+			if f.old < 0 {
+				if f.value != nil { // nil for a new variadic parameter
+					args = append(args, internalastutil.CloneNode(f.value))
+				}
+				continue
+			}
+			args = append(args, ast.NewIdent(oldNames[f.old]))
 			// Record whether the call has an ellipsis.
-			// (Only the last loop iteration matters.)
-			_, variadic = field.Type.(*ast.Ellipsis)
+			variadic = i == len(newParamFields)-1 && is[*ast.Ellipsis](f.typeExpr)
 		}
 	}
 
-	// Step 3: Rewrite all referring calls, by swapping in the wrapper and
-	// inlining all.
-
-	newContent, err := rewriteCalls(ctx, signatureRewrite{
+	rw := signatureRewrite{
 		snapshot: snapshot,
 		pkg:      pkg,
 		pgf:      pgf,
-		origDecl: info.decl,
+		origDecl: decl,
 		newDecl:  newDecl,
 		params:   params,
 		callArgs: args,
 		variadic: variadic,
-	})
+	}
+	if resultsChanged {
+		qual := typesinternal.FileQualifier(pgf.File, pkg.Types())
+		i := 0
+		for _, field := range goplsastutil.FlatFields(decl.Type.Results) {
+			index := slices.IndexFunc(newResultFields, func(f sigField) bool { return f.old == i })
+			var zero ast.Expr
+			if index < 0 {
+				typ := pkg.TypesInfo().TypeOf(field.Type)
+				var ok bool
+				zero, ok = typesinternal.ZeroExpr(typ, qual)
+				if !ok {
+					return nil, fmt.Errorf("cannot remove result #%d: %s has no zero value", i, typ)
+				}
+			}
+			rw.resultIndices = append(rw.resultIndices, index)
+			rw.zeroResults = append(rw.zeroResults, zero)
+			i++
+		}
+	}
+
+	// Step 3: Rewrite all referring calls, by swapping in the wrapper and
+	// inlining all.
+
+	newContent, err := rewriteCalls(ctx, rw, conflicts)
 	if err != nil {
 		return nil, err
 	}
@@ -306,7 +690,7 @@ func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 	// of the inlining should have changed the location of the original
 	// declaration.
 	{
-		idx := findDecl(pgf.File, info.decl)
+		idx := findDecl(pgf.File, decl)
 		if idx < 0 {
 			return nil, bug.Errorf("didn't find original decl")
 		}
@@ -315,39 +699,358 @@ func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 		if !ok {
 			src = pgf.Src
 		}
+		if len(bodyEdits) > 0 {
+			edits, ok := diff.Merge(diff.Bytes(pgf.Src, src), bodyEdits)
+			if !ok {
+				return nil, fmt.Errorf("cannot change signature of %s: calls in its body conflict with the changes to it", decl.Name.Name)
+			}
+			src, err = diff.ApplyBytes(pgf.Src, edits)
+			if err != nil {
+				return nil, bug.Errorf("applying edits to body: %v", err)
+			}
+		}
 		fset := tokeninternal.FileSetFor(pgf.Tok)
-		src, err := rewriteSignature(fset, idx, src, newDecl)
+		src, err := rewriteSignature(fset, idx, src, newDecl, resultsChanged)
 		if err != nil {
 			return nil, err
 		}
 		newContent[pgf.URI] = src
 	}
+	return newContent, nil
+}
 
-	// Translate the resulting state into document changes.
-	var changes []protocol.DocumentChange
-	for uri, after := range newContent {
-		fh, err := snapshot.ReadFile(ctx, uri)
+// checkFieldNames checks that the fields of the changed signature of the
+// function declaration d are consistent with its body: that removed fields
+// are unused, and that new or renamed fields neither conflict with each
+// other nor capture references in the body to other objects.
+func checkFieldNames(d sigDecl, params, results []sigField) error {
+	info := d.pkg.TypesInfo()
+	vars := append(fieldVars(info, d.decl.Type.Params), fieldVars(info, d.decl.Type.Results)...)
+
+	// Removed fields must be unused.
+	kept := make(map[*types.Var]bool)
+	nparams := d.decl.Type.Params.NumFields()
+	for _, f := range params {
+		if f.old >= 0 {
+			kept[vars[f.old]] = true
+		}
+	}
+	for _, f := range results {
+		if f.old >= 0 {
+			kept[vars[nparams+f.old]] = true
+		}
+	}
+	if d.decl.Body != nil {
+		for _, v := range vars {
+			if v != nil && !kept[v] && v.Name() != "_" {
+				for id, obj := range info.Uses {
+					if obj == v && goplsastutil.NodeContains(d.decl.Body, id.Pos()) {
+						return fmt.Errorf("cannot remove %s from %s: it is used in the body", v.Name(), d.decl.Name.Name)
+					}
+				}
+			}
+		}
+	}
+
+	// Field names must be unique among all names of the signature.
+	names := make(map[string]bool)
+	for _, list := range []*ast.FieldList{d.decl.Recv, d.decl.Type.TypeParams} {
+		for id := range goplsastutil.FlatFields(list) {
+			if id != nil {
+				names[id.Name] = true
+			}
+		}
+	}
+	for i, f := range slices.Concat(params, results) {
+		if f.name == "" || f.name == "_" {
+			continue
+		}
+		if names[f.name] {
+			return fmt.Errorf("duplicate name %s in new signature of %s", f.name, d.decl.Name.Name)
+		}
+		names[f.name] = true
+
+		// A new or renamed field must not capture a reference in the body to
+		// an object other than the old fields, and must not be redeclared.
+		var oldVar *types.Var
+		if f.old >= 0 {
+			if i < len(params) {
+				oldVar = vars[f.old]
+			} else {
+				oldVar = vars[nparams+f.old]
+			}
+		}
+		if (oldVar == nil || f.name != oldVar.Name()) && d.decl.Body != nil {
+			var found bool
+			ast.Inspect(d.decl.Body, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok && id.Name == f.name {
+					if obj, ok := info.Uses[id].(*types.Var); !ok || !slices.Contains(vars, obj) {
+						found = true
+					}
+				}
+				return !found
+			})
+			if found {
+				return fmt.Errorf("cannot use name %s in new signature of %s: it is already used in the body", f.name, d.decl.Name.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// fieldVars returns the variables of the fields of a parameter or result
+// list, or nil for unnamed fields.
+func fieldVars(info *types.Info, list *ast.FieldList) []*types.Var {
+	var vars []*types.Var
+	for id := range goplsastutil.FlatFields(list) {
+		var v *types.Var
+		if id != nil {
+			v, _ = info.Defs[id].(*types.Var)
+		}
+		vars = append(vars, v)
+	}
+	return vars
+}
+
+// rewriteBody updates the body of newDecl, a copy of the declaration d, for
+// its changed signature: it renames references to renamed fields, and
+// rewrites return statements for the new results. It returns the
+// corresponding edits to the source of the original declaration.
+func rewriteBody(d sigDecl, newDecl *ast.FuncDecl, params, results []sigField) ([]diff.Edit, error) {
+	if d.decl.Body == nil {
+		return nil, nil
+	}
+	var (
+		info  = d.pkg.TypesInfo()
+		fset  = tokeninternal.FileSetFor(d.pgf.Tok)
+		edits []diff.Edit
+	)
+
+	// Rename references. The syntax of newDecl has the positions of the
+	// original syntax, so we can correlate them.
+	var (
+		vars    = append(fieldVars(info, d.decl.Type.Params), fieldVars(info, d.decl.Type.Results)...)
+		nparams = d.decl.Type.Params.NumFields()
+		renames = make(map[token.Pos]string)
+	)
+	for i, fields := range [][]sigField{params, results} {
+		for _, f := range fields {
+			if f.old < 0 {
+				continue
+			}
+			index := f.old
+			if i == 1 {
+				index += nparams
+			}
+			if v := vars[index]; v != nil && f.name != v.Name() {
+				for id, obj := range info.Uses {
+					if obj == v && goplsastutil.NodeContains(d.decl.Body, id.Pos()) {
+						renames[id.Pos()] = f.name
+					}
+				}
+			}
+		}
+	}
+	ast.Inspect(newDecl.Body, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			if name, ok := renames[id.Pos()]; ok {
+				start, end, err := safetoken.Offsets(d.pgf.Tok, id.Pos(), id.End())
+				if err == nil {
+					edits = append(edits, diff.Edit{Start: start, End: end, New: name})
+				}
+				id.Name = name
+			}
+		}
+		return true
+	})
+	diff.SortEdits(edits)
+
+	if isIdentity(results, d.decl.Type.Results) {
+		return edits, nil
+	}
+
+	// text returns the source of the original syntax of n, renamed.
+	text := func(n ast.Node) (string, error) {
+		start, end, err := safetoken.Offsets(d.pgf.Tok, n.Pos(), n.End())
 		if err != nil {
-			return nil, err
+			return "", err
+		}
+		var within []diff.Edit
+		for _, edit := range edits {
+			if start <= edit.Start && edit.End <= end {
+				within = append(within, diff.Edit{Start: edit.Start - start, End: edit.End - start, New: edit.New})
+			}
+		}
+		return diff.Apply(string(d.pgf.Src[start:end]), within)
+	}
+
+	// Rewrite the return statements of the function (not those of function
+	// literals), consulting the corresponding original statements.
+	var (
+		oldReturns = returnStmts(d.decl.Body)
+		newReturns = returnStmts(newDecl.Body)
+		nresults   = d.decl.Type.Results.NumFields()
+		retEdits   []diff.Edit
+	)
+	for i, ret := range newReturns {
+		old := oldReturns[i]
+		if len(ret.Results) == 0 && nresults > 0 {
+			continue // a bare return of named results
+		}
+		if len(ret.Results) != nresults {
+			posn := safetoken.StartPosition(d.pkg.FileSet(), ret.Pos())
+			return nil, fmt.Errorf("cannot change results of %s: return statement at %s has a multi-valued operand", d.decl.Name.Name, posn)
+		}
+		// Don't drop operands with effects.
+		for j, expr := range old.Results {
+			if !slices.ContainsFunc(results, func(f sigField) bool { return f.old == j }) && mayHaveEffects(info, expr) {
+				posn := safetoken.StartPosition(d.pkg.FileSet(), expr.Pos())
+				return nil, fmt.Errorf("cannot remove result of %s: operand at %s may have effects", d.decl.Name.Name, posn)
+			}
+		}
+		var (
+			exprs []ast.Expr
+			texts []string
+		)
+		for _, f := range results {
+			if f.old >= 0 {
+				exprs = append(exprs, ret.Results[f.old])
+				t, err := text(old.Results[f.old])
+				if err != nil {
+					return nil, err
+				}
+				texts = append(texts, t)
+			} else {
+				exprs = append(exprs, internalastutil.CloneNode(f.value))
+				texts = append(texts, FormatNode(fset, f.value))
+			}
+		}
+		ret.Results = exprs
+
+		edit := diff.Edit{New: strings.Join(texts, ", ")}
+		var err error
+		if len(old.Results) > 0 {
+			edit.Start, edit.End, err = safetoken.Offsets(d.pgf.Tok, old.Results[0].Pos(), old.Results[len(old.Results)-1].End())
+		} else {
+			edit.Start, err = safetoken.Offset(d.pgf.Tok, old.End())
+			edit.End = edit.Start
+			edit.New = " " + edit.New
 		}
-		before, err := fh.Content()
 		if err != nil {
 			return nil, err
 		}
-		edits := diff.Bytes(before, after)
-		mapper := protocol.NewMapper(uri, before)
-		textedits, err := protocol.EditsFromDiffEdits(mapper, edits)
+		retEdits = append(retEdits, edit)
+	}
+
+	// A function that had no results may need a final return statement.
+	if nresults == 0 && len(results) > 0 {
+		list := d.decl.Body.List
+		if len(list) == 0 || !is[*ast.ReturnStmt](list[len(list)-1]) {
+			var exprs []ast.Expr
+			for _, f := range results {
+				exprs = append(exprs, internalastutil.CloneNode(f.value))
+			}
+			ret := &ast.ReturnStmt{Results: exprs}
+			newDecl.Body.List = append(newDecl.Body.List, ret)
+			offset, err := safetoken.Offset(d.pgf.Tok, d.decl.Body.Rbrace)
+			if err != nil {
+				return nil, err
+			}
+			retEdits = append(retEdits, diff.Edit{Start: offset, End: offset, New: "\t" + FormatNode(fset, ret) + "\n"})
+		}
+	}
+
+	// Replace the renamings within rewritten return statements.
+	edits = slices.DeleteFunc(edits, func(edit diff.Edit) bool {
+		return slices.ContainsFunc(retEdits, func(ret diff.Edit) bool {
+			return ret.Start <= edit.Start && edit.End <= ret.End
+		})
+	})
+	edits = append(edits, retEdits...)
+	diff.SortEdits(edits)
+	return edits, nil
+}
+
+// returnStmts returns the return statements of a function body, excluding
+// those of function literals, in order.
+func returnStmts(body *ast.BlockStmt) []*ast.ReturnStmt {
+	var stmts []*ast.ReturnStmt
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			stmts = append(stmts, n)
+		}
+		return true
+	})
+	return stmts
+}
+
+// mayHaveEffects reports whether evaluating expr may have effects: whether
+// it contains a function call or channel receive.
+func mayHaveEffects(info *types.Info, expr ast.Expr) bool {
+	effects := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if tv, ok := info.Types[n.Fun]; !ok || !tv.IsType() {
+				effects = true // not a conversion
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.ARROW {
+				effects = true
+			}
+		}
+		return !effects
+	})
+	return effects
+}
+
+// changeMethodSignature returns the new content of the file declaring the
+// interface method d, with its signature changed.
+func changeMethodSignature(d sigDecl, newParams, newResults []command.ChangeSignatureParam) ([]byte, error) {
+	ftyp := d.funcType()
+	params, err := newFields(d, ftyp.Params, newParams, "parameter")
+	if err != nil {
+		return nil, err
+	}
+	results, err := newFields(d, ftyp.Results, newResults, "result")
+	if err != nil {
+		return nil, err
+	}
+	newType := &ast.FuncType{
+		Params:  writeFields(params),
+		Results: writeFields(results),
+	}
+	start, end, err := safetoken.Offsets(d.pgf.Tok, ftyp.Params.Opening, ftyp.End())
+	if err != nil {
+		return nil, bug.Errorf("can't find signature: %v", err)
+	}
+	fset := tokeninternal.FileSetFor(d.pgf.Tok)
+	sig := strings.TrimPrefix(FormatNode(fset, newType), "func")
+
+	// Splice.
+	var buf bytes.Buffer
+	buf.Write(d.pgf.Src[:start])
+	buf.WriteString(sig)
+	buf.Write(d.pgf.Src[end:])
+	newSrc := buf.Bytes()
+	if len(d.pgf.File.Imports) > 0 {
+		formatted, err := imports.Process("output", newSrc, nil)
 		if err != nil {
-			return nil, fmt.Errorf("computing edits for %s: %v", uri, err)
+			return nil, bug.Errorf("imports.Process failed: %v", err)
 		}
-		change := protocol.DocumentChangeEdit(fh, textedits)
-		changes = append(changes, change)
+		newSrc = formatted
 	}
-	return changes, nil
+	return newSrc, nil
 }
 
 // rewriteSignature rewrites the signature of the declIdx'th declaration in src
-// to use the signature of newDecl (described by fset).
+// to use the signature of newDecl (described by fset). The results are
+// rewritten only if resultsChanged is set.
 //
 // TODO(rfindley): I think this operation could be generalized, for example by
 // using a concept of a 'nodepath' to correlate nodes between two related
@@ -356,7 +1059,7 @@ func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 // Note that with its current application, rewriteSignature is expected to
 // succeed. Separate bug.Errorf calls are used below (rather than one call at
 // the callsite) in order to have greater precision.
-func rewriteSignature(fset *token.FileSet, declIdx int, src0 []byte, newDecl *ast.FuncDecl) ([]byte, error) {
+func rewriteSignature(fset *token.FileSet, declIdx int, src0 []byte, newDecl *ast.FuncDecl, resultsChanged bool) ([]byte, error) {
 	// Parse the new file0 content, to locate the original params.
 	file0, err := parser.ParseFile(fset, "", src0, parser.ParseComments|parser.SkipObjectResolution)
 	if err != nil {
@@ -372,6 +1075,13 @@ func rewriteSignature(fset *token.FileSet, declIdx int, src0 []byte, newDecl *as
 	if err != nil {
 		return nil, bug.Errorf("can't find params: %v", err)
 	}
+	end0 := closing0 + 1 // end of the replaced signature
+	if resultsChanged {
+		end0, err = safetoken.Offset(fset.File(decl0.Pos()), decl0.Type.End())
+		if err != nil {
+			return nil, bug.Errorf("can't find results: %v", err)
+		}
+	}
 
 	// Format the modified signature and apply a textual replacement. This
 	// minimizes comment disruption.
@@ -385,13 +1095,20 @@ func rewriteSignature(fset *token.FileSet, declIdx int, src0 []byte, newDecl *as
 	if err != nil {
 		return nil, bug.Errorf("param offsets: %v", err)
 	}
-	newParams := formattedType[opening1 : closing1+1]
+	end1 := closing1 + 1
+	if resultsChanged {
+		end1, err = safetoken.Offset(fset.File(newType.Pos()), newType.End())
+		if err != nil {
+			return nil, bug.Errorf("result offsets: %v", err)
+		}
+	}
+	newSig := formattedType[opening1:end1]
 
 	// Splice.
 	var buf bytes.Buffer
 	buf.Write(src0[:opening0])
-	buf.WriteString(newParams)
-	buf.Write(src0[closing0+1:])
+	buf.WriteString(newSig)
+	buf.Write(src0[end0:])
 	newSrc := buf.Bytes()
 	if len(file0.Imports) > 0 {
 		formatted, err := imports.Process("output", newSrc, nil)
@@ -485,6 +1202,12 @@ type signatureRewrite struct {
 	params            *ast.FieldList
 	callArgs          []ast.Expr
 	variadic          bool
+
+	// If the results are changed, resultIndices holds the index of the
+	// result of newDecl corresponding to each result of origDecl, or -1
+	// if it is removed, in which case zeroResults holds its zero value.
+	resultIndices []int
+	zeroResults   []ast.Expr
 }
 
 // rewriteCalls returns the document changes required to rewrite the
@@ -526,8 +1249,12 @@ type signatureRewrite struct {
 //
 // By passing an entirely new declaration, rewriteCalls may be used for
 // signature refactorings that may affect the function body, such as removing
-// or adding return values.
-func rewriteCalls(ctx context.Context, rw signatureRewrite) (map[protocol.DocumentURI][]byte, error) {
+// or adding return values. In that case, the wrapper returns the results of
+// the delegate in their original order (see resultIndices).
+//
+// References that cannot be rewritten, such as dynamic calls, are recorded in
+// conflicts.
+func rewriteCalls(ctx context.Context, rw signatureRewrite, conflicts map[token.Position]string) (map[protocol.DocumentURI][]byte, error) {
 	// tag is a unique prefix that is added to the delegated declaration.
 	//
 	// It must have a ~0% probability of causing collisions with existing names.
@@ -547,6 +1274,14 @@ func rewriteCalls(ctx context.Context, rw signatureRewrite) (map[protocol.Docume
 
 		wrapper := internalastutil.CloneNode(rw.origDecl)
 		wrapper.Type.Params = rw.params
+		if rw.resultIndices != nil {
+			// Unname the results, so they don't conflict with the names below.
+			results := new(ast.FieldList)
+			for _, field := range goplsastutil.FlatFields(rw.origDecl.Type.Results) {
+				results.List = append(results.List, &ast.Field{Type: internalastutil.CloneNode(field.Type)})
+			}
+			wrapper.Type.Results = results
+		}
 
 		// Get the receiver name, creating it if necessary.
 		var recv string // nonempty => call is a method call with receiver recv
@@ -597,18 +1332,25 @@ func rewriteCalls(ctx context.Context, rw signatureRewrite) (map[protocol.Docume
 			call.Ellipsis = 1 // must not be token.NoPos
 		}
 
-		var stmt ast.Stmt
-		if delegate.Type.Results.NumFields() > 0 {
-			stmt = &ast.ReturnStmt{
-				Results: []ast.Expr{call},
+		var stmts []ast.Stmt
+		switch {
+		case rw.resultIndices != nil:
+			usedNames := map[string]bool{recv: true}
+			for id := range goplsastutil.FlatFields(wrapper.Type.Params) {
+				usedNames[id.Name] = true
 			}
-		} else {
-			stmt = &ast.ExprStmt{
+			stmts = rw.delegateResults(call, delegate.Type.Results.NumFields(), usedNames)
+		case delegate.Type.Results.NumFields() > 0:
+			stmts = []ast.Stmt{&ast.ReturnStmt{
+				Results: []ast.Expr{call},
+			}}
+		default:
+			stmts = []ast.Stmt{&ast.ExprStmt{
 				X: call,
-			}
+			}}
 		}
 		wrapper.Body = &ast.BlockStmt{
-			List: []ast.Stmt{stmt},
+			List: stmts,
 		}
 
 		fset := tokeninternal.FileSetFor(rw.pgf.Tok)
@@ -645,7 +1387,63 @@ func rewriteCalls(ctx context.Context, rw signatureRewrite) (map[protocol.Docume
 		Logf:          logf,
 		IgnoreEffects: true,
 	}
-	return inlineAllCalls(ctx, rw.snapshot, rw.pkg, rw.pgf, rw.origDecl, calleeInfo, post, opts)
+	return inlineAllCalls(ctx, rw.snapshot, rw.pkg, rw.pgf, rw.origDecl, calleeInfo, post, opts, conflicts)
+}
+
+// delegateResults returns the statements of a wrapper whose results have
+// changed, which call the delegate (with nresults results) and return its
+// results in the original order, avoiding usedNames. For example:
+//
+//	r0, _ := G_o_p_l_s_foo(a, b)
+//	return 0, r0
+func (rw signatureRewrite) delegateResults(call *ast.CallExpr, nresults int, usedNames map[string]bool) []ast.Stmt {
+	var (
+		lhs     = make([]ast.Expr, nresults)
+		results = make([]ast.Expr, len(rw.resultIndices))
+		used    = 0 // number of results of the delegate that are returned
+		n       = 0
+	)
+	for i := range lhs {
+		lhs[i] = ast.NewIdent("_")
+	}
+	for old, index := range rw.resultIndices {
+		if index < 0 {
+			results[old] = internalastutil.CloneNode(rw.zeroResults[old])
+			continue
+		}
+		var name string
+		for {
+			name = fmt.Sprintf("r%d", n)
+			n++
+			if !usedNames[name] {
+				break
+			}
+		}
+		lhs[index] = ast.NewIdent(name)
+		results[old] = ast.NewIdent(name)
+		used++
+	}
+
+	// A single result may be returned directly.
+	if nresults == 1 && used == 1 {
+		results[slices.Index(rw.resultIndices, 0)] = call
+		return []ast.Stmt{&ast.ReturnStmt{Results: results}}
+	}
+
+	var stmts []ast.Stmt
+	if used > 0 {
+		stmts = append(stmts, &ast.AssignStmt{
+			Lhs: lhs,
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{call},
+		})
+	} else {
+		stmts = append(stmts, &ast.ExprStmt{X: call})
+	}
+	if len(results) > 0 {
+		stmts = append(stmts, &ast.ReturnStmt{Results: results})
+	}
+	return stmts
 }
 
 // reTypeCheck re-type checks orig with new file contents defined by fileMask.
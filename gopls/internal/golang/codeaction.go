@@ -30,6 +30,7 @@ import (
 	"golang.org/x/tools/gopls/internal/settings"
 	"golang.org/x/tools/internal/event"
 	"golang.org/x/tools/internal/imports"
+	"golang.org/x/tools/internal/stringer"
 	"golang.org/x/tools/internal/typesinternal"
 )
 
@@ -237,6 +238,7 @@ var codeActionProducers = [...]codeActionProducer{
 	{kind: protocol.QuickFix, fn: quickFix, needPkg: true},
 	{kind: protocol.SourceOrganizeImports, fn: sourceOrganizeImports},
 	{kind: settings.AddTest, fn: addTest, needPkg: true},
+	{kind: settings.GenerateStringer, fn: generateStringer, needPkg: true},
 	{kind: settings.GoAssembly, fn: goAssembly, needPkg: true},
 	{kind: settings.GoDoc, fn: goDoc, needPkg: true},
 	{kind: settings.GoFreeSymbols, fn: goFreeSymbols},
@@ -244,6 +246,7 @@ var codeActionProducers = [...]codeActionProducer{
 	{kind: settings.GoToggleCompilerOptDetails, fn: toggleCompilerOptDetails},
 	{kind: settings.GoplsDocFeatures, fn: goplsDocFeatures},
 	{kind: settings.RefactorExtractFunction, fn: refactorExtractFunction},
+	{kind: settings.RefactorExtractInterface, fn: refactorExtractInterface, needPkg: true},
 	{kind: settings.RefactorExtractMethod, fn: refactorExtractMethod},
 	{kind: settings.RefactorExtractToNewFile, fn: refactorExtractToNewFile},
 	{kind: settings.RefactorExtractConstant, fn: refactorExtractVariable, needPkg: true},
@@ -251,16 +254,23 @@ var codeActionProducers = [...]codeActionProducer{
 	{kind: settings.RefactorExtractConstantAll, fn: refactorExtractVariableAll, needPkg: true},
 	{kind: settings.RefactorExtractVariableAll, fn: refactorExtractVariableAll, needPkg: true},
 	{kind: settings.RefactorInlineCall, fn: refactorInlineCall, needPkg: true},
+	{kind: settings.RefactorInlineConstant, fn: refactorInlineConstant, needPkg: true},
+	{kind: settings.RefactorInlineVariable, fn: refactorInlineVariable, needPkg: true},
+	{kind: settings.RefactorRewriteAddTags, fn: refactorRewriteAddTags},
 	{kind: settings.RefactorRewriteChangeQuote, fn: refactorRewriteChangeQuote},
 	{kind: settings.RefactorRewriteFillStruct, fn: refactorRewriteFillStruct, needPkg: true},
 	{kind: settings.RefactorRewriteFillSwitch, fn: refactorRewriteFillSwitch, needPkg: true},
+	{kind: settings.RefactorRewriteIfToSwitch, fn: refactorRewriteIfToSwitch, needPkg: true},
 	{kind: settings.RefactorRewriteInvertIf, fn: refactorRewriteInvertIf},
 	{kind: settings.RefactorRewriteJoinLines, fn: refactorRewriteJoinLines, needPkg: true},
+	{kind: settings.RefactorRewriteRemoveTags, fn: refactorRewriteRemoveTags},
 	{kind: settings.RefactorRewriteRemoveUnusedParam, fn: refactorRewriteRemoveUnusedParam, needPkg: true},
 	{kind: settings.RefactorRewriteMoveParamLeft, fn: refactorRewriteMoveParamLeft, needPkg: true},
 	{kind: settings.RefactorRewriteMoveParamRight, fn: refactorRewriteMoveParamRight, needPkg: true},
 	{kind: settings.RefactorRewriteSplitLines, fn: refactorRewriteSplitLines, needPkg: true},
 	{kind: settings.RefactorRewriteEliminateDotImport, fn: refactorRewriteEliminateDotImport, needPkg: true},
+	{kind: settings.RefactorRewriteSafeDelete, fn: refactorRewriteSafeDelete, needPkg: true},
+	{kind: settings.RefactorRewriteSwitchToIf, fn: refactorRewriteSwitchToIf, needPkg: true},
 
 	// Note: don't forget to update the allow-list in Server.CodeAction
 	// when adding new query operations like GoTest and GoDoc that
@@ -539,6 +549,23 @@ func refactorExtractToNewFile(ctx context.Context, req *codeActionsRequest) erro
 	return nil
 }
 
+// refactorExtractInterface produces "Extract interface from TYPE" code actions.
+// See [ExtractInterface] for command implementation.
+func refactorExtractInterface(ctx context.Context, req *codeActionsRequest) error {
+	if named, methods, ok := interfaceSource(req.pkg, req.pgf, req.start, req.end); ok {
+		title := "Extract interface from " + named.Obj().Name()
+		if methods != nil {
+			title = "Extract interface from selected methods of " + named.Obj().Name()
+		}
+		cmd := command.NewExtractInterfaceCommand(title, command.ExtractInterfaceArgs{
+			Location:     req.loc,
+			ResolveEdits: req.resolveEdits(),
+		})
+		req.addCommandAction(cmd, true)
+	}
+	return nil
+}
+
 // addTest produces "Add test for FUNC" code actions.
 // See [server.commandHandler.AddTest] for command implementation.
 func addTest(ctx context.Context, req *codeActionsRequest) error {
@@ -574,6 +601,53 @@ func addTest(ctx context.Context, req *codeActionsRequest) error {
 	return nil
 }
 
+// generateStringer produces "Generate String method for T" code
+// actions, using the flags of a stringer //go:generate directive for
+// T, if any, or else with variants that use the line comments of the
+// constants or trim the type name from their names.
+// See [server.commandHandler.GenerateStringer] for command implementation.
+func generateStringer(ctx context.Context, req *codeActionsRequest) error {
+	obj, ok := stringerType(req.pkg, req.pgf, req.start, req.end)
+	if !ok {
+		return nil
+	}
+	title := "Generate String method for " + obj.Name()
+	add := func(title string, flags stringer.Flags) {
+		cmd := command.NewGenerateStringerCommand(title, command.GenerateStringerArgs{
+			Location:     req.loc,
+			TrimPrefix:   flags.TrimPrefix,
+			LineComment:  flags.LineComment,
+			ResolveEdits: req.resolveEdits(),
+		})
+		req.addCommandAction(cmd, true)
+	}
+	if flags, ok := stringerDirective(req.pkg, obj.Name()); ok {
+		add(title, flags)
+		return nil
+	}
+	add(title, stringer.Flags{})
+
+	values, err := stringerValues(req.pkg, obj.Name(), stringer.Flags{LineComment: true})
+	if err != nil {
+		return err
+	}
+
+	// Offer -linecomment if any constant has a line comment.
+	if slices.ContainsFunc(values, func(v stringer.Value) bool { return v.Text() != v.Name() }) {
+		add(title+" using line comments", stringer.Flags{LineComment: true})
+	}
+
+	// Offer -trimprefix if all the constants have the type name as a prefix.
+	trim := !slices.ContainsFunc(values, func(v stringer.Value) bool {
+		rest, ok := strings.CutPrefix(v.Name(), obj.Name())
+		return !ok || rest == ""
+	})
+	if trim {
+		add(title+" trimming prefix "+obj.Name(), stringer.Flags{TrimPrefix: obj.Name()})
+	}
+	return nil
+}
+
 // identityTransform returns a change signature transformation that leaves the
 // given fieldlist unmodified.
 func identityTransform(fields *ast.FieldList) []command.ChangeSignatureParam {
@@ -654,6 +728,133 @@ func refactorRewriteMoveParamRight(ctx context.Context, req *codeActionsRequest)
 	return nil
 }
 
+// refactorRewriteSafeDelete produces "Safely delete NAME" code actions.
+// See [server.commandHandler.SafeDelete] for command implementation.
+func refactorRewriteSafeDelete(ctx context.Context, req *codeActionsRequest) error {
+	// Same as [refactorInlineCall].
+	if req.trigger == protocol.CodeActionAutomatic && req.loc.Empty() {
+		return nil
+	}
+
+	obj, _, ok := safeDeleteTarget(req.pkg.TypesInfo(), req.pgf, req.start, req.end)
+	if !ok {
+		return nil
+	}
+	cmd := command.NewSafeDeleteCommand("Safely delete "+obj.Name(), command.SafeDeleteArgs{
+		Location:     req.loc,
+		ResolveEdits: req.resolveEdits(),
+	})
+	req.addCommandAction(cmd, true)
+
+	// If the package has tests, offer to delete those that use it too.
+	for _, mp := range req.snapshot.MetadataGraph().Packages {
+		if mp.ForTest == req.pkg.Metadata().PkgPath {
+			cmd := command.NewSafeDeleteCommand("Safely delete "+obj.Name()+" and the tests that use it", command.SafeDeleteArgs{
+				Location:     req.loc,
+				DeleteTests:  true,
+				ResolveEdits: req.resolveEdits(),
+			})
+			req.addCommandAction(cmd, true)
+			break
+		}
+	}
+	return nil
+}
+
+// refactorRewriteAddTags produces "Add KEYS struct tags" code actions,
+// which add the keys already used by the tags of the struct type (or
+// json, if none) to those selected fields that lack them, and an "Add
+// omitempty" code action. See [ModifyTags] for command implementation.
+func refactorRewriteAddTags(ctx context.Context, req *codeActionsRequest) error {
+	// Same as [refactorInlineCall].
+	if req.trigger == protocol.CodeActionAutomatic && req.loc.Empty() {
+		return nil
+	}
+
+	st, fields, ok := selectedFields(req.pgf, req.start, req.end)
+	if !ok {
+		return nil
+	}
+	keys, ok := structTagKeys(st.Fields.List)
+	if !ok {
+		return nil
+	}
+	add := keys
+	if len(add) == 0 {
+		add = []string{"json"}
+	}
+	args := command.ModifyTagsArgs{
+		Location:     req.loc,
+		Add:          add,
+		ResolveEdits: req.resolveEdits(),
+	}
+	if edits, err := tagEdits(req.pgf, fields, args, settings.StructTagKeep); err == nil && len(edits) > 0 {
+		title := fmt.Sprintf("Add %s struct tags", strings.Join(add, ", "))
+		req.addCommandAction(command.NewModifyTagsCommand(title, args), true)
+	}
+
+	var options []string
+	for _, key := range keys {
+		if omitemptyKeys[key] {
+			options = append(options, key+"=omitempty")
+		}
+	}
+	args = command.ModifyTagsArgs{
+		Location:     req.loc,
+		AddOptions:   options,
+		ResolveEdits: req.resolveEdits(),
+	}
+	if edits, err := tagEdits(req.pgf, fields, args, settings.StructTagKeep); err == nil && len(edits) > 0 {
+		req.addCommandAction(command.NewModifyTagsCommand("Add omitempty to struct tags", args), true)
+	}
+	return nil
+}
+
+// refactorRewriteRemoveTags produces "Remove struct tags" code
+// actions, for all keys of the tags of the selected fields and, if
+// there are several, for each one, and a "Remove omitempty" code
+// action. See [ModifyTags] for command implementation.
+func refactorRewriteRemoveTags(ctx context.Context, req *codeActionsRequest) error {
+	// Same as [refactorInlineCall].
+	if req.trigger == protocol.CodeActionAutomatic && req.loc.Empty() {
+		return nil
+	}
+
+	_, fields, ok := selectedFields(req.pgf, req.start, req.end)
+	if !ok {
+		return nil
+	}
+	keys, ok := structTagKeys(fields)
+	if !ok || len(keys) == 0 {
+		return nil
+	}
+	remove := func(title string, keys ...string) {
+		req.addCommandAction(command.NewModifyTagsCommand(title, command.ModifyTagsArgs{
+			Location:     req.loc,
+			Remove:       keys,
+			ResolveEdits: req.resolveEdits(),
+		}), true)
+	}
+	if len(keys) == 1 {
+		remove(fmt.Sprintf("Remove %s struct tags", keys[0]), keys...)
+	} else {
+		remove("Remove all struct tags", keys...)
+		for _, key := range keys {
+			remove(fmt.Sprintf("Remove %s struct tags", key), key)
+		}
+	}
+
+	args := command.ModifyTagsArgs{
+		Location:      req.loc,
+		RemoveOptions: []string{"omitempty"},
+		ResolveEdits:  req.resolveEdits(),
+	}
+	if edits, err := tagEdits(req.pgf, fields, args, settings.StructTagKeep); err == nil && len(edits) > 0 {
+		req.addCommandAction(command.NewModifyTagsCommand("Remove omitempty from struct tags", args), true)
+	}
+	return nil
+}
+
 // refactorRewriteChangeQuote produces "Convert to {raw,interpreted} string literal" code actions.
 func refactorRewriteChangeQuote(ctx context.Context, req *codeActionsRequest) error {
 	convertStringLiteral(req)
@@ -669,6 +870,28 @@ func refactorRewriteInvertIf(ctx context.Context, req *codeActionsRequest) error
 	return nil
 }
 
+// refactorRewriteIfToSwitch produces "Convert if/else-if chain to switch"
+// code actions. See [ifToSwitch] for command implementation.
+func refactorRewriteIfToSwitch(ctx context.Context, req *codeActionsRequest) error {
+	if _, _, typeSwitch, err := ifToSwitchText(req.pkg.TypesInfo(), req.pgf, req.start, req.end); err == nil {
+		title := "Convert if/else-if chain to switch"
+		if typeSwitch {
+			title = "Convert if/else-if chain to type switch"
+		}
+		req.addApplyFixAction(title, fixIfToSwitch, req.loc)
+	}
+	return nil
+}
+
+// refactorRewriteSwitchToIf produces "Convert switch to if/else-if chain"
+// code actions. See [switchToIf] for command implementation.
+func refactorRewriteSwitchToIf(ctx context.Context, req *codeActionsRequest) error {
+	if _, _, err := switchToIfText(req.pkg.TypesInfo(), req.pgf, req.start, req.end); err == nil {
+		req.addApplyFixAction("Convert switch to if/else-if chain", fixSwitchToIf, req.loc)
+	}
+	return nil
+}
+
 // refactorRewriteSplitLines produces "Split ITEMS into separate lines" code actions.
 // See [splitLines] for command implementation.
 func refactorRewriteSplitLines(ctx context.Context, req *codeActionsRequest) error {
@@ -887,6 +1110,34 @@ func refactorInlineCall(ctx context.Context, req *codeActionsRequest) error {
 	return nil
 }
 
+// refactorInlineVariable produces "Inline variable V" code actions.
+// See [inlineVariable] for command implementation.
+func refactorInlineVariable(ctx context.Context, req *codeActionsRequest) error {
+	// Same as [refactorInlineCall].
+	if req.trigger == protocol.CodeActionAutomatic && req.loc.Empty() {
+		return nil
+	}
+
+	if v, ok := canInlineVariable(req.pkg, req.pgf, req.start, req.end); ok {
+		req.addApplyFixAction("Inline variable "+v.Name(), fixInlineVariable, req.loc)
+	}
+	return nil
+}
+
+// refactorInlineConstant produces "Inline constant C" code actions.
+// See [inlineConstant] for command implementation.
+func refactorInlineConstant(ctx context.Context, req *codeActionsRequest) error {
+	// Same as [refactorInlineCall].
+	if req.trigger == protocol.CodeActionAutomatic && req.loc.Empty() {
+		return nil
+	}
+
+	if c, ok := selectedValue(req.pkg, req.pgf, req.start, req.end).(*types.Const); ok {
+		req.addApplyFixAction("Inline constant "+c.Name(), fixInlineConstant, req.loc)
+	}
+	return nil
+}
+
 // goTest produces "Run tests and benchmarks" code actions.
 // See [server.commandHandler.runTests] for command implementation.
 func goTest(ctx context.Context, req *codeActionsRequest) error {
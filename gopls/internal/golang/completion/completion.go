@@ -106,6 +106,11 @@ type CompletionItem struct {
 	// Documentation is the documentation for the completion item.
 	Documentation string
 
+	// Resolve, if non-nil, holds the information needed to compute
+	// the documentation and import edits of the item lazily, in
+	// response to a completionItem/resolve request. See [Resolve].
+	Resolve *ResolveData
+
 	// isSlice reports whether the underlying type of the object
 	// from which this candidate was derived is a slice.
 	// (Used to complete append() calls.)
@@ -116,13 +121,14 @@ type CompletionItem struct {
 type completionOptions struct {
 	unimported            bool
 	documentation         bool
-	fullDocumentation     bool
 	placeholders          bool
 	snippets              bool
 	postfix               bool
 	matcher               settings.Matcher
 	budget                time.Duration
 	completeFunctionCalls bool
+	lazyDocumentation     bool // documentation is deferred to Resolve
+	lazyImports           bool // import edits are deferred to Resolve
 }
 
 // Snippet is a convenience returns the snippet if available, otherwise
@@ -541,10 +547,15 @@ func Completion(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, p
 	switch n := path[0].(type) {
 	case *ast.BasicLit:
 		// Skip completion inside literals except for ImportSpec
+		// and struct tags.
 		if len(path) > 1 {
 			if _, ok := path[1].(*ast.ImportSpec); ok {
 				break
 			}
+			if field, ok := path[1].(*ast.Field); ok && field.Tag == n {
+				items, sel := structTag(n, pos, pgf)
+				return items, sel, nil
+			}
 		}
 		return nil, nil, nil
 	case *ast.CallExpr:
@@ -623,12 +634,13 @@ func Completion(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, p
 			matcher:               opts.Matcher,
 			unimported:            opts.CompleteUnimported,
 			documentation:         opts.CompletionDocumentation && opts.HoverKind != settings.NoDocumentation,
-			fullDocumentation:     opts.HoverKind == settings.FullDocumentation,
 			placeholders:          opts.UsePlaceholders,
 			budget:                opts.CompletionBudget,
 			snippets:              opts.InsertTextFormat == protocol.SnippetTextFormat,
 			postfix:               opts.ExperimentalPostfixCompletions,
 			completeFunctionCalls: opts.CompleteFunctionCalls,
+			lazyDocumentation:     slices.Contains(opts.CompletionResolveOptions, "documentation"),
+			lazyImports:           slices.Contains(opts.CompletionResolveOptions, "additionalTextEdits"),
 		},
 		// default to a matcher that always matches
 		matcher:            prefixMatcher(""),
@@ -1415,7 +1427,7 @@ func (c *completer) selector(ctx context.Context, sel *ast.SelectorExpr) error {
 				if imports.ImportPathToAssumedName(path) != string(mp.Name) {
 					imp.name = string(mp.Name)
 				}
-				item.AdditionalTextEdits, _ = c.importEdits(imp)
+				c.addImportEdits(&item, imp)
 			}
 
 			// For functions, add a parameter snippet.
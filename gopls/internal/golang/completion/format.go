@@ -16,6 +16,7 @@ import (
 	"golang.org/x/tools/gopls/internal/golang"
 	"golang.org/x/tools/gopls/internal/golang/completion/snippet"
 	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/settings"
 	"golang.org/x/tools/gopls/internal/util/safetoken"
 	"golang.org/x/tools/gopls/internal/util/typesutil"
 	internalastutil "golang.org/x/tools/internal/astutil"
@@ -181,15 +182,19 @@ Suffixes:
 	}
 
 	// If this candidate needs an additional import statement,
-	// add the additional text edits needed.
+	// add the additional text edits needed (unless deferred).
+	var resolve *ResolveData
 	if cand.imp != nil {
-		addlEdits, err := c.importEdits(cand.imp)
-
-		if err != nil {
-			return CompletionItem{}, err
+		if c.opts.lazyImports {
+			resolve = c.resolveData(nil)
+			resolve.ImportPath, resolve.ImportName = cand.imp.importPath, cand.imp.name
+		} else {
+			addlEdits, err := c.importEdits(cand.imp)
+			if err != nil {
+				return CompletionItem{}, err
+			}
+			protocolEdits = append(protocolEdits, addlEdits...)
 		}
-
-		protocolEdits = append(protocolEdits, addlEdits...)
 		if kind != protocol.ModuleCompletion {
 			if detail != "" {
 				detail += " "
@@ -239,6 +244,7 @@ Suffixes:
 		Depth:               len(cand.path),
 		snippet:             &snip,
 		isSlice:             isSlice(obj),
+		Resolve:             resolve,
 	}
 	// If the user doesn't want documentation for completion items.
 	if !c.opts.documentation {
@@ -257,20 +263,46 @@ Suffixes:
 		event.Error(ctx, fmt.Sprintf("failed to find Hover for %q", obj.Name()), err)
 		return item, nil
 	}
-	if c.opts.fullDocumentation {
+
+	// If the client can resolve it, record the location of the
+	// declaration so that the documentation can be computed later.
+	// The deprecation status is needed to display the item, so it
+	// is always reported.
+	if c.opts.lazyDocumentation {
+		setDeprecation(&item, comment, c.snapshot.Options())
+		if !(isTypeName(obj) && is[*types.TypeParam](obj.Type())) {
+			item.Resolve = c.resolveData(item.Resolve)
+			item.Resolve.DeclURI = protocol.URIFromPath(pos.Filename)
+			item.Resolve.DeclOffset = pos.Offset
+		}
+		return item, nil
+	}
+	setDocumentation(&item, comment, c.snapshot.Options())
+
+	return item, nil
+}
+
+// setDocumentation sets the documentation of item, and its
+// deprecation status, from the doc comment of its declaration.
+func setDocumentation(item *CompletionItem, comment *ast.CommentGroup, opts *settings.Options) {
+	if opts.HoverKind == settings.FullDocumentation {
 		item.Documentation = comment.Text()
 	} else {
 		item.Documentation = doc.Synopsis(comment.Text())
 	}
+	setDeprecation(item, comment, opts)
+}
+
+// setDeprecation sets the deprecation status of item from the doc
+// comment of its declaration.
+func setDeprecation(item *CompletionItem, comment *ast.CommentGroup, opts *settings.Options) {
 	if internalastutil.Deprecation(comment) != "" {
-		if c.snapshot.Options().CompletionTags {
+		if opts.CompletionTags {
 			item.Tags = []protocol.CompletionItemTag{protocol.ComplDeprecated}
-		} else if c.snapshot.Options().CompletionDeprecated {
+		} else if opts.CompletionDeprecated {
 			item.Deprecated = true
 		}
 	}
-
-	return item, nil
 }
 
 // conversionEdits represents the string edits needed to make a type conversion
@@ -305,6 +337,27 @@ func (c *completer) formatConversion(convertTo types.Type) conversionEdits {
 	return conversionEdits{prefix: typeName + "(", suffix: ")"}
 }
 
+// addImportEdits adds to item the text edits necessary to add the
+// given import to the current file, or, if the client can resolve
+// them, records the import so that the edits can be computed later.
+func (c *completer) addImportEdits(item *CompletionItem, imp *importInfo) {
+	if c.opts.lazyImports {
+		item.Resolve = c.resolveData(item.Resolve)
+		item.Resolve.ImportPath, item.Resolve.ImportName = imp.importPath, imp.name
+		return
+	}
+	item.AdditionalTextEdits, _ = c.importEdits(imp)
+}
+
+// resolveData returns data, or if it is nil, new ResolveData for
+// an item in the current file.
+func (c *completer) resolveData(data *ResolveData) *ResolveData {
+	if data == nil {
+		data = &ResolveData{URI: c.fh.URI()}
+	}
+	return data
+}
+
 // importEdits produces the text edits necessary to add the given import to the current file.
 func (c *completer) importEdits(imp *importInfo) ([]protocol.TextEdit, error) {
 	if imp == nil {
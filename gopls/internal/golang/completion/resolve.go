@@ -0,0 +1,74 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package completion
+
+import (
+	"context"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/event"
+	"golang.org/x/tools/internal/imports"
+)
+
+// ResolveData records the information needed to compute the parts of
+// a completion item that the client has asked to resolve lazily (see
+// the completionItem.resolveSupport client capability). It is sent to
+// the client as the data of the item, and returned by the client in a
+// completionItem/resolve request.
+type ResolveData struct {
+	// URI is the file in which completion was requested.
+	URI protocol.DocumentURI `json:"uri"`
+
+	// DeclURI and DeclOffset locate the declaration of the completed
+	// symbol, whose doc comment is the item's documentation.
+	DeclURI    protocol.DocumentURI `json:"declURI,omitempty"`
+	DeclOffset int                  `json:"declOffset,omitempty"`
+
+	// ImportPath and ImportName describe the import that accepting
+	// the item adds to the file.
+	ImportPath string `json:"importPath,omitempty"`
+	ImportName string `json:"importName,omitempty"`
+}
+
+// Resolve computes the documentation, deprecation status, and import
+// edits of a completion item that were deferred by [Completion], as
+// described by data. The file fh is the one in which completion was
+// requested.
+//
+// Only those fields of the resulting item are populated.
+func Resolve(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, data *ResolveData) (CompletionItem, error) {
+	ctx, done := event.Start(ctx, "completion.Resolve")
+	defer done()
+
+	var item CompletionItem
+	if data.ImportPath != "" {
+		content, err := fh.Content()
+		if err != nil {
+			return CompletionItem{}, err
+		}
+		edits, err := golang.ComputeImportFixEdits(snapshot.Options().Local, content, &imports.ImportFix{
+			StmtInfo: imports.ImportInfo{
+				ImportPath: data.ImportPath,
+				Name:       data.ImportName,
+			},
+			FixType: imports.AddImport,
+		})
+		if err != nil {
+			return CompletionItem{}, err
+		}
+		item.AdditionalTextEdits = edits
+	}
+	if data.DeclURI != "" {
+		comment, err := golang.HoverDocAt(ctx, snapshot, data.DeclURI, data.DeclOffset)
+		if err != nil {
+			return CompletionItem{}, err
+		}
+		setDocumentation(&item, comment, snapshot.Options())
+	}
+	return item, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package completion
+
+import (
+	"go/ast"
+	"go/token"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/golang/completion/snippet"
+	"golang.org/x/tools/gopls/internal/protocol"
+)
+
+// structTagOptions maps each well-known struct tag key to the options
+// that may follow the name in its value.
+var structTagOptions = map[string][]string{
+	"bson":         {"omitempty", "minsize", "truncate", "inline"},
+	"db":           nil,
+	"json":         {"omitempty", "omitzero", "string"},
+	"mapstructure": {"omitempty", "squash", "remain"},
+	"toml":         {"omitempty", "omitzero"},
+	"xml":          {"attr", "chardata", "cdata", "innerxml", "comment", "any", "omitempty"},
+	"yaml":         {"omitempty", "flow", "inline"},
+}
+
+// structTag returns completions of the keys of a struct tag, and of
+// the options within their values, for the position pos within the
+// raw string literal lit that is the tag of a struct field.
+func structTag(lit *ast.BasicLit, pos token.Pos, pgf *parsego.File) ([]CompletionItem, *Selection) {
+	if !strings.HasPrefix(lit.Value, "`") || pos <= lit.Pos() || pos >= lit.End() {
+		return nil, nil
+	}
+	text := lit.Value[1 : pos-lit.Pos()]
+
+	// Scan the key:"value" pairs before pos, following
+	// reflect.StructTag.Lookup, to find what is being typed.
+	var (
+		keys    []string // keys of complete pairs
+		key     string   // key of the incomplete value, if any
+		inValue bool     // pos is within a value
+	)
+	for {
+		text = strings.TrimLeft(text, " ")
+		i := 0
+		for i < len(text) && text[i] > ' ' && text[i] != ':' && text[i] != '"' && text[i] != 0x7f {
+			i++
+		}
+		if i == len(text) {
+			break // pos is within a key
+		}
+		if text[i] != ':' || i+1 >= len(text) || text[i+1] != '"' {
+			return nil, nil // malformed
+		}
+		key, text = text[:i], text[i+2:]
+		end := strings.IndexByte(text, '"')
+		if end < 0 {
+			inValue = true
+			break
+		}
+		keys = append(keys, key)
+		text = text[end+1:]
+		if text != "" && text[0] != ' ' {
+			return nil, nil // malformed
+		}
+	}
+
+	var (
+		items  []CompletionItem
+		prefix string
+	)
+	if inValue {
+		// Complete an option, after the name and a comma.
+		comma := strings.LastIndexByte(text, ',')
+		if comma < 0 {
+			return nil, nil
+		}
+		prefix = text[comma+1:]
+		used := strings.Split(text[:comma], ",")[1:]
+		for _, opt := range structTagOptions[key] {
+			if strings.HasPrefix(opt, prefix) && !slices.Contains(used, opt) {
+				items = append(items, CompletionItem{
+					Label:         opt,
+					InsertText:    opt,
+					Kind:          protocol.EnumMemberCompletion,
+					Score:         stdScore,
+					Documentation: "option of " + key + " struct tag",
+				})
+			}
+		}
+	} else {
+		// Complete a key.
+		prefix = text
+		for key := range structTagOptions {
+			if strings.HasPrefix(key, prefix) && !slices.Contains(keys, key) {
+				var sn snippet.Builder
+				sn.WriteText(key + `:"`)
+				sn.WriteFinalTabstop()
+				sn.WriteText(`"`)
+				items = append(items, CompletionItem{
+					Label:         key,
+					InsertText:    key + `:""`,
+					Kind:          protocol.PropertyCompletion,
+					Score:         stdScore,
+					snippet:       &sn,
+					Documentation: key + " struct tag",
+				})
+			}
+		}
+		sort.Slice(items, func(i, j int) bool { return items[i].Label < items[j].Label })
+	}
+	start := pos - token.Pos(len(prefix))
+	return items, &Selection{
+		content: prefix,
+		cursor:  pos,
+		tokFile: pgf.Tok,
+		start:   start,
+		end:     pos,
+		mapper:  pgf.Mapper,
+	}
+}
@@ -0,0 +1,514 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the code action "Extract interface".
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/astutil/edge"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// interfaceSource returns the concrete type whose methods are
+// indicated by the selection [start, end): either the name of its
+// declaration, in which case methods is nil, or one or more complete
+// declarations of its methods, in which case methods holds the names
+// of those that are exported. The result is ok if the type is a
+// non-generic defined type other than an interface, and there is at
+// least one exported method to extract.
+func interfaceSource(pkg *cache.Package, pgf *parsego.File, start, end token.Pos) (named *types.Named, methods []string, ok bool) {
+	info := pkg.TypesInfo()
+
+	path, _ := astutil.PathEnclosingInterval(pgf.File, start, end)
+	if len(path) >= 2 {
+		if id, ok := path[0].(*ast.Ident); ok {
+			if spec, ok := path[1].(*ast.TypeSpec); ok && spec.Name == id {
+				if tname, ok := info.Defs[id].(*types.TypeName); ok && !tname.IsAlias() {
+					named, _ = tname.Type().(*types.Named)
+				}
+				ok := named != nil && len(exportedMethods(named)) > 0
+				return named, nil, ok
+			}
+		}
+	}
+
+	for _, decl := range pgf.File.Decls {
+		if !posRangeIntersects(start, end, decl.Pos(), decl.End()) {
+			continue
+		}
+		// Each selected declaration must be a complete method of the same type.
+		decl, ok := decl.(*ast.FuncDecl)
+		if !ok || decl.Recv == nil || !posRangeContains(start, end, decl.Pos(), decl.End()) {
+			return nil, nil, false
+		}
+		fn, ok := info.Defs[decl.Name].(*types.Func)
+		if !ok {
+			return nil, nil, false
+		}
+		_, recv := typesinternal.ReceiverNamed(fn.Signature().Recv())
+		if recv == nil || named != nil && recv.Origin() != named {
+			return nil, nil, false
+		}
+		named = recv.Origin()
+		if fn.Exported() {
+			methods = append(methods, fn.Name())
+		}
+	}
+	if named == nil || len(methods) == 0 || len(exportedMethods(named)) == 0 {
+		return nil, nil, false
+	}
+	return named, methods, true
+}
+
+// exportedMethods returns the exported methods of the non-generic,
+// non-interface type named (including those of *named, and promoted
+// ones) in declaration order, or nil for an interface or generic type.
+func exportedMethods(named *types.Named) []*types.Func {
+	if types.IsInterface(named) || named.TypeParams().Len() > 0 {
+		return nil
+	}
+	var methods []*types.Func
+	mset := types.NewMethodSet(types.NewPointer(named))
+	for i := range mset.Len() {
+		if m := mset.At(i).Obj().(*types.Func); m.Exported() {
+			methods = append(methods, m)
+		}
+	}
+	slices.SortStableFunc(methods, func(x, y *types.Func) int { return cmp.Compare(x.Pos(), y.Pos()) })
+	return methods
+}
+
+// ExtractInterface declares an interface with methods of the concrete
+// type indicated by args.Location in pgf, and changes the types of the
+// parameters at args.UseSites to the new interface.
+// See [command.ExtractInterfaceArgs] for details.
+//
+// Changing the type of a parameter of a method may prevent its
+// receiver type from implementing an interface; this is not checked.
+func ExtractInterface(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, args command.ExtractInterfaceArgs) ([]protocol.DocumentChange, error) {
+	start, end, err := pgf.RangePos(args.Location.Range)
+	if err != nil {
+		return nil, err
+	}
+	named, selected, ok := interfaceSource(pkg, pgf, start, end)
+	if !ok {
+		return nil, fmt.Errorf("selection does not indicate a concrete type with exported methods")
+	}
+	typeName := named.Obj().Name()
+
+	// Choose the methods.
+	all := exportedMethods(named)
+	names := args.Methods
+	if len(names) == 0 {
+		names = selected
+	}
+	methods := all
+	if len(names) > 0 {
+		methods = nil
+		for _, name := range names {
+			i := slices.IndexFunc(all, func(m *types.Func) bool { return m.Name() == name })
+			if i < 0 {
+				return nil, fmt.Errorf("%s has no exported method %s", typeName, name)
+			}
+			if !slices.Contains(methods, all[i]) {
+				methods = append(methods, all[i])
+			}
+		}
+	}
+	names = nil
+	for _, m := range methods {
+		names = append(names, m.Name())
+	}
+
+	// Find the file in which to declare the interface.
+	dest := args.Dest
+	if dest == "" {
+		dest = protocol.URIFromPath(pkg.FileSet().File(named.Obj().Pos()).Name())
+	}
+	destPkg := pkg
+	destPGF, err := pkg.File(dest)
+	if err != nil {
+		destPkg, destPGF, err = NarrowestPackageForFile(ctx, snapshot, dest)
+		if err != nil {
+			return nil, err
+		}
+	}
+	destPath := destPkg.Types().Path()
+	destInfo := destPkg.TypesInfo()
+
+	// Choose or check the name of the interface.
+	declared := func(name string) bool {
+		return destPkg.Types().Scope().Lookup(name) != nil ||
+			destInfo.Scopes[destPGF.File].Lookup(name) != nil ||
+			types.Universe.Lookup(name) != nil
+	}
+	name := args.Name
+	if name == "" {
+		name = typeName + "Interface"
+		for i := 2; declared(name); i++ {
+			name = fmt.Sprintf("%sInterface%d", typeName, i)
+		}
+	} else if !token.IsIdentifier(name) || name == "_" {
+		return nil, fmt.Errorf("invalid interface name %q", name)
+	} else if declared(name) {
+		return nil, fmt.Errorf("%s is already declared in package %s", name, destPkg.Types().Name())
+	}
+
+	// The methods must be expressible in the destination package,
+	// and the packages they refer to must not import it.
+	refs := make(map[string]bool) // paths of referenced packages
+	for _, m := range methods {
+		if obj := inaccessible(m.Signature(), destPath, refs); obj != nil {
+			return nil, fmt.Errorf("cannot declare %s in package %s: method %s refers to unexported %s",
+				name, destPkg.Types().Name(), m.Name(), obj.Name())
+		}
+	}
+	g := snapshot.MetadataGraph()
+	for _, path := range slices.Sorted(maps.Keys(refs)) {
+		var ids []PackageID
+		for id, mp := range g.Packages {
+			if string(mp.PkgPath) == path && mp.ForTest == "" {
+				ids = append(ids, id)
+			}
+		}
+		for mp := range g.ForwardReflexiveTransitiveClosure(ids...) {
+			if string(mp.PkgPath) == destPath {
+				return nil, fmt.Errorf("cannot declare %s in package %s: importing %s would create an import cycle",
+					name, destPkg.Types().Name(), path)
+			}
+		}
+	}
+
+	// Declare the interface, after the concrete type if
+	// it is declared in the same file, or else at the end.
+	docs := make(map[types.Object]*ast.CommentGroup)
+	for _, pgf := range pkg.CompiledGoFiles() {
+		for _, decl := range pgf.File.Decls {
+			if decl, ok := decl.(*ast.FuncDecl); ok && decl.Recv != nil && decl.Doc != nil {
+				docs[pkg.TypesInfo().Defs[decl.Name]] = decl.Doc
+			}
+		}
+	}
+	insert := destPGF.File.End()
+	if destPkg == pkg {
+		for _, decl := range destPGF.File.Decls {
+			if decl.Pos() <= named.Obj().Pos() && named.Obj().Pos() < decl.End() {
+				insert = decl.End()
+			}
+		}
+	}
+	insertOffset, err := safetoken.Offset(destPGF.Tok, insert)
+	if err != nil {
+		return nil, err
+	}
+	// Skip past any comment on the same line.
+	if eol := bytes.IndexByte(destPGF.Src[insertOffset:], '\n'); eol >= 0 {
+		insertOffset += eol + 1
+	} else {
+		insertOffset = len(destPGF.Src)
+	}
+	declEdits, err := insertDecls(snapshot, destPkg.Metadata(), destPGF, insertOffset, func(out *bytes.Buffer, qual types.Qualifier) error {
+		fmt.Fprintf(out, "\ntype %s interface {\n", name)
+		for _, m := range methods {
+			if doc := docs[m]; doc != nil {
+				for _, comment := range doc.List {
+					fmt.Fprintf(out, "%s\n", comment.Text)
+				}
+			}
+			out.WriteString(m.Name())
+			types.WriteSignature(out, m.Signature(), qual)
+			out.WriteString("\n")
+		}
+		out.WriteString("}\n")
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	edits := map[protocol.DocumentURI][]diff.Edit{destPGF.URI: declEdits}
+	files := map[protocol.DocumentURI]*parsego.File{destPGF.URI: destPGF}
+	addEdits := func(pgf *parsego.File, more ...diff.Edit) error {
+		merged, ok := diff.Merge(edits[pgf.URI], more)
+		if !ok {
+			return fmt.Errorf("conflicting edits to %s", pgf.URI)
+		}
+		edits[pgf.URI], files[pgf.URI] = merged, pgf
+		return nil
+	}
+
+	// Change the types of the parameters at the use sites.
+	removed := make(map[*types.PkgName]int) // number of references within replaced types
+	for _, loc := range args.UseSites {
+		usePGF, err := destPkg.File(loc.URI)
+		if err != nil {
+			return nil, fmt.Errorf("use site %s is not in package %s", loc.URI, destPkg.Types().Name())
+		}
+		field, decl, err := paramAt(usePGF, loc.Range)
+		if err != nil {
+			return nil, err
+		}
+		posn := safetoken.StartPosition(destPkg.FileSet(), field.Pos())
+
+		// The type of the parameter must implement the interface.
+		t := destInfo.TypeOf(field.Type)
+		qual := typesinternal.FileQualifier(usePGF.File, destPkg.Types())
+		useNamed, _ := types.Unalias(typesinternal.Unpointer(t)).(*types.Named)
+		if useNamed == nil || useNamed.Obj().Name() != typeName || useNamed.Obj().Pkg().Path() != named.Obj().Pkg().Path() {
+			return nil, fmt.Errorf("%v: parameter type %s is not %s or *%s", posn, types.TypeString(t, qual), typeName, typeName)
+		}
+		iface := newInterface(useNamed, names)
+		if !types.Implements(t, iface) {
+			m, _ := types.MissingMethod(t, iface, true)
+			return nil, fmt.Errorf("%v: %s does not implement %s (method %s has pointer receiver)",
+				posn, types.TypeString(t, qual), name, m.Name())
+		}
+		if decl.Type.TypeParams != nil {
+			for _, tparam := range decl.Type.TypeParams.List {
+				for _, id := range tparam.Names {
+					if id.Name == name {
+						return nil, fmt.Errorf("%v: type parameter %s of %s would shadow the interface", posn, name, decl.Name.Name)
+					}
+				}
+			}
+		}
+
+		// The function must use the parameter only to call its methods.
+		if decl.Body != nil {
+			curBody, _ := usePGF.Cursor.FindNode(decl.Body)
+			for _, id := range field.Names {
+				obj := destInfo.Defs[id]
+				if obj == nil {
+					continue
+				}
+				for cur := range curBody.Preorder((*ast.Ident)(nil)) {
+					use := cur.Node().(*ast.Ident)
+					if destInfo.Uses[use] != obj {
+						continue
+					}
+					if ek, _ := cur.Edge(); ek == edge.SelectorExpr_X &&
+						slices.Contains(names, cur.Parent().Node().(*ast.SelectorExpr).Sel.Name) {
+						continue
+					}
+					return nil, fmt.Errorf("%v: parameter %s is used other than to call methods of %s",
+						safetoken.StartPosition(destPkg.FileSet(), use.Pos()), id.Name, name)
+				}
+			}
+		}
+
+		// The function must be referenced only by calls.
+		fn := destInfo.Defs[decl.Name]
+		for _, pgf := range destPkg.CompiledGoFiles() {
+			for cur := range pgf.Cursor.Preorder((*ast.Ident)(nil)) {
+				if destInfo.Uses[cur.Node().(*ast.Ident)] != fn {
+					continue
+				}
+				if ek, _ := cur.Edge(); ek == edge.SelectorExpr_Sel {
+					cur = cur.Parent()
+				}
+				if ek, _ := cur.Edge(); ek != edge.CallExpr_Fun {
+					return nil, fmt.Errorf("%v: cannot change the parameters of %s: it is referenced other than by a call",
+						safetoken.StartPosition(destPkg.FileSet(), cur.Node().Pos()), decl.Name.Name)
+				}
+			}
+		}
+
+		start, end, err := usePGF.NodeOffsets(field.Type)
+		if err != nil {
+			return nil, err
+		}
+		if err := addEdits(usePGF, diff.Edit{Start: start, End: end, New: name}); err != nil {
+			return nil, err
+		}
+		ast.Inspect(field.Type, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok {
+				if pkgname, ok := destInfo.Uses[id].(*types.PkgName); ok {
+					removed[pkgname]++
+				}
+			}
+			return true
+		})
+	}
+
+	// Delete imports that are no longer used, unless
+	// the new interface refers to them.
+	for _, pgf := range destPkg.CompiledGoFiles() {
+		if len(removed) == 0 {
+			break
+		}
+		uses := make(map[*types.PkgName]int)
+		for cur := range pgf.Cursor.Preorder((*ast.Ident)(nil)) {
+			if pkgname, ok := destInfo.Uses[cur.Node().(*ast.Ident)].(*types.PkgName); ok {
+				uses[pkgname]++
+			}
+		}
+		unparenthesized := unparenthesizedImports(pgf)
+		for _, spec := range pgf.File.Imports {
+			pkgname := destInfo.PkgNameOf(spec)
+			if pkgname == nil || removed[pkgname] == 0 || uses[pkgname] > removed[pkgname] ||
+				pgf == destPGF && refs[pkgname.Imported().Path()] {
+				continue
+			}
+			var node ast.Node = spec
+			decl := unparenthesized[spec]
+			if decl != nil {
+				node = decl
+			}
+			start, end, err := pgf.NodeOffsets(node)
+			if err != nil {
+				return nil, err
+			}
+			// Delete whole lines, and any blank lines after a declaration.
+			for start > 0 && (pgf.Src[start-1] == ' ' || pgf.Src[start-1] == '\t') {
+				start--
+			}
+			for end < len(pgf.Src) && pgf.Src[end] == '\n' {
+				end++
+				if decl == nil {
+					break
+				}
+			}
+			if err := addEdits(pgf, diff.Edit{Start: start, End: end}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var changes []protocol.DocumentChange
+	for _, uri := range slices.Sorted(maps.Keys(edits)) {
+		fh, err := snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+		textedits, err := protocol.EditsFromDiffEdits(files[uri].Mapper, edits[uri])
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, protocol.DocumentChangeEdit(fh, textedits))
+	}
+	return changes, nil
+}
+
+// paramAt returns the parameter declaration enclosing rng in pgf,
+// and the declaration of its function.
+func paramAt(pgf *parsego.File, rng protocol.Range) (*ast.Field, *ast.FuncDecl, error) {
+	start, end, err := pgf.RangePos(rng)
+	if err != nil {
+		return nil, nil, err
+	}
+	path, _ := astutil.PathEnclosingInterval(pgf.File, start, end)
+	for i, n := range path {
+		if field, ok := n.(*ast.Field); ok {
+			if len(path) > i+3 {
+				if decl, ok := path[i+3].(*ast.FuncDecl); ok && decl.Type.Params == path[i+1] {
+					return field, decl, nil
+				}
+			}
+			break
+		}
+	}
+	return nil, nil, fmt.Errorf("%s:%d: not a parameter of a function declaration", filepath.Base(pgf.URI.Path()), rng.Start.Line+1)
+}
+
+// newInterface returns an interface with the specified methods of
+// named, or of *named.
+func newInterface(named *types.Named, names []string) *types.Interface {
+	var methods []*types.Func
+	mset := types.NewMethodSet(types.NewPointer(named))
+	for _, name := range names {
+		if sel := mset.Lookup(named.Obj().Pkg(), name); sel != nil {
+			m := sel.Obj().(*types.Func)
+			sig := m.Signature()
+			methods = append(methods, types.NewFunc(m.Pos(), m.Pkg(), m.Name(),
+				types.NewSignatureType(nil, nil, nil, sig.Params(), sig.Results(), sig.Variadic())))
+		}
+	}
+	return types.NewInterfaceType(methods, nil).Complete()
+}
+
+// inaccessible returns an object referenced by type t that cannot be
+// referenced from the package with the specified path, or nil if there
+// is none. It records the paths of the packages of named types
+// referenced by t in refs.
+func inaccessible(t types.Type, path string, refs map[string]bool) types.Object {
+	accessible := func(obj types.Object) bool {
+		return obj.Pkg() == nil || obj.Pkg().Path() == path || obj.Exported()
+	}
+	switch t := t.(type) {
+	case interface {
+		Obj() *types.TypeName
+		TypeArgs() *types.TypeList
+	}: // *types.Named or *types.Alias
+		obj := t.Obj()
+		if !accessible(obj) {
+			return obj
+		}
+		if obj.Pkg() != nil && obj.Pkg().Path() != path {
+			refs[obj.Pkg().Path()] = true
+		}
+		for targ := range t.TypeArgs().Types() {
+			if obj := inaccessible(targ, path, refs); obj != nil {
+				return obj
+			}
+		}
+	case interface{ Elem() types.Type }: // pointer, slice, array, chan, map
+		if m, ok := t.(*types.Map); ok {
+			if obj := inaccessible(m.Key(), path, refs); obj != nil {
+				return obj
+			}
+		}
+		return inaccessible(t.Elem(), path, refs)
+	case *types.Signature:
+		for _, tuple := range []*types.Tuple{t.Params(), t.Results()} {
+			for v := range tuple.Variables() {
+				if obj := inaccessible(v.Type(), path, refs); obj != nil {
+					return obj
+				}
+			}
+		}
+	case *types.Struct:
+		for field := range t.Fields() {
+			if !accessible(field) {
+				return field
+			}
+			if obj := inaccessible(field.Type(), path, refs); obj != nil {
+				return obj
+			}
+		}
+	case *types.Interface:
+		for m := range t.ExplicitMethods() {
+			if !accessible(m) {
+				return m
+			}
+			if obj := inaccessible(m.Type(), path, refs); obj != nil {
+				return obj
+			}
+		}
+		for embedded := range t.EmbeddedTypes() {
+			if obj := inaccessible(embedded, path, refs); obj != nil {
+				return obj
+			}
+		}
+	}
+	return nil
+}
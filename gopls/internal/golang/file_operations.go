@@ -0,0 +1,270 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the operations that gopls performs in response
+// to the LSP workspace/willRenameFiles and workspace/willDeleteFiles
+// requests, which clients send before moving or deleting files and
+// directories in their file tree.
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/pathutil"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/event"
+)
+
+// RenameFiles returns the edits required to preserve the correctness
+// of the workspace when the specified files and directories are renamed.
+//
+// Renaming a directory changes the import path of each package
+// within it, so every import of such a package is updated. If the
+// name of a package matches the name of the renamed directory, its
+// package clause (and the local names of its imports) are updated too.
+//
+// Renaming a single file has no effect on import paths, so it
+// requires no edits. Nor does moving a whole module, since the import
+// paths of its packages are relative to its go.mod file.
+//
+// The edits apply to the files before they are renamed.
+func RenameFiles(ctx context.Context, snapshot *cache.Snapshot, renames []protocol.FileRename) (map[protocol.DocumentURI][]protocol.TextEdit, error) {
+	ctx, done := event.Start(ctx, "golang.RenameFiles")
+	defer done()
+
+	allMetadata, err := snapshot.AllMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	editMap := make(map[protocol.DocumentURI][]diff.Edit)
+	for _, rename := range renames {
+		oldURI, err := protocol.ParseDocumentURI(rename.OldURI)
+		if err != nil {
+			return nil, err
+		}
+		newURI, err := protocol.ParseDocumentURI(rename.NewURI)
+		if err != nil {
+			return nil, err
+		}
+		if err := renameDirectory(ctx, snapshot, allMetadata, oldURI.Path(), newURI.Path(), editMap); err != nil {
+			return nil, err
+		}
+	}
+	return toProtocolEdits(ctx, snapshot, editMap)
+}
+
+// renameDirectory computes the edits to package clauses and import
+// declarations resulting from renaming directory oldDir to newDir.
+// It has no effect if oldDir contains no packages, for example
+// because it is a file.
+//
+// Edits are written into the edits map.
+func renameDirectory(ctx context.Context, snapshot *cache.Snapshot, allMetadata []*metadata.Package, oldDir, newDir string, edits map[protocol.DocumentURI][]diff.Edit) error {
+	oldBase, newBase := filepath.Base(oldDir), filepath.Base(newDir)
+	for _, mp := range allMetadata {
+		if len(mp.CompiledGoFiles) == 0 || mp.IsIntermediateTestVariant() {
+			continue
+		}
+		pkgDir := mp.CompiledGoFiles[0].DirPath()
+		if !pathutil.InDir(oldDir, pkgDir) {
+			continue // not affected by the renaming
+		}
+		if mp.Module == nil {
+			return fmt.Errorf("cannot update imports: missing module information for package %q", mp.PkgPath)
+		}
+		if pathutil.InDir(oldDir, mp.Module.Dir) {
+			continue // the whole module moves, so import paths are unchanged
+		}
+
+		// Compute the new import path of the package from its new
+		// location relative to the module root.
+		rel, err := filepath.Rel(oldDir, pkgDir)
+		if err != nil {
+			return err
+		}
+		modRel, err := filepath.Rel(mp.Module.Dir, filepath.Join(newDir, rel))
+		if err != nil {
+			return err
+		}
+		modRel = filepath.ToSlash(modRel)
+		if modRel == ".." || strings.HasPrefix(modRel, "../") {
+			return fmt.Errorf("cannot move package %q out of its module %q", mp.PkgPath, mp.Module.Path)
+		}
+		newPath := path.Join(mp.Module.Path, modRel)
+
+		// If the package is named after the renamed directory,
+		// rename the package too, provided the new name is valid.
+		pkgName := mp.Name
+		if rel == "." && isValidIdentifier(newBase) && oldBase != newBase {
+			switch string(mp.Name) {
+			case oldBase:
+				pkgName = PackageName(newBase)
+			case oldBase + "_test":
+				pkgName = PackageName(newBase + "_test")
+			}
+			if pkgName != mp.Name {
+				if err := renamePackageClause(ctx, mp, snapshot, pkgName, edits); err != nil {
+					return err
+				}
+			}
+		}
+
+		if strings.HasSuffix(string(mp.PkgPath), "_test") && mp.ForTest != "" {
+			continue // an x_test package cannot be imported
+		}
+		if PackagePath(newPath) == mp.PkgPath && pkgName == mp.Name {
+			continue
+		}
+		if err := renameImports(ctx, snapshot, mp, ImportPath(newPath), pkgName, edits); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeletedFileReferences returns a description of each package-level
+// declaration in the specified files and directories that is still
+// referenced from a file that is not being deleted, in the form
+// "name (referenced from file.go)".
+//
+// Such references will be broken by the deletion.
+func DeletedFileReferences(ctx context.Context, snapshot *cache.Snapshot, deletes []protocol.FileDelete) ([]string, error) {
+	ctx, done := event.Start(ctx, "golang.DeletedFileReferences")
+	defer done()
+
+	var deleted []protocol.DocumentURI
+	for _, del := range deletes {
+		uri, err := protocol.ParseDocumentURI(del.URI)
+		if err != nil {
+			return nil, err
+		}
+		deleted = append(deleted, uri)
+	}
+	isDeleted := func(uri protocol.DocumentURI) bool {
+		for _, d := range deleted {
+			if d == uri || d.Encloses(uri) {
+				return true
+			}
+		}
+		return false
+	}
+
+	allMetadata, err := snapshot.AllMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the packages containing deleted files.
+	var ids []PackageID
+	for _, mp := range allMetadata {
+		if mp.IsIntermediateTestVariant() {
+			continue
+		}
+		for _, uri := range mp.CompiledGoFiles {
+			if isDeleted(uri) {
+				ids = append(ids, mp.ID)
+				break
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	pkgs, err := snapshot.TypeCheck(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	// A declKey identifies a package-level declaration independent
+	// of the type-checker instance that created its object.
+	type declKey struct {
+		pkgPath PackagePath
+		name    string
+	}
+	decls := make(map[declKey]bool)  // package-level declarations in deleted files
+	refs := make(map[declKey]string) // first non-deleted file referring to each
+	importers := make(map[PackageID]bool)
+
+	// recordRefs records references from the non-deleted files of pkg
+	// to the package-level declarations in deleted files.
+	recordRefs := func(pkg *cache.Package) {
+		for id, obj := range pkg.TypesInfo().Uses {
+			if obj.Pkg() == nil || obj.Parent() != obj.Pkg().Scope() {
+				continue // not package-level
+			}
+			key := declKey{PackagePath(obj.Pkg().Path()), obj.Name()}
+			if !decls[key] {
+				continue
+			}
+			pgf, ok := enclosingFile(pkg, id.Pos())
+			if !ok || isDeleted(pgf.URI) {
+				continue
+			}
+			if _, ok := refs[key]; !ok {
+				refs[key] = filepath.Base(pgf.URI.Path())
+			}
+		}
+	}
+
+	for _, pkg := range pkgs {
+		scope := pkg.Types().Scope()
+		exported := false
+		for _, name := range scope.Names() {
+			obj := scope.Lookup(name)
+			pgf, ok := enclosingFile(pkg, obj.Pos())
+			if !ok || !isDeleted(pgf.URI) {
+				continue
+			}
+			decls[declKey{PackagePath(pkg.Types().Path()), name}] = true
+			if obj.Exported() {
+				exported = true
+			}
+		}
+		recordRefs(pkg)
+
+		// Exported declarations may be referenced by importers.
+		if exported {
+			rdeps, err := snapshot.ReverseDependencies(ctx, pkg.Metadata().ID, false)
+			if err != nil {
+				return nil, err
+			}
+			for id, rdep := range rdeps {
+				if !rdep.IsIntermediateTestVariant() {
+					importers[id] = true
+				}
+			}
+		}
+	}
+
+	if len(importers) > 0 {
+		ids := make([]PackageID, 0, len(importers))
+		for id := range importers {
+			ids = append(ids, id)
+		}
+		pkgs, err := snapshot.TypeCheck(ctx, ids...)
+		if err != nil {
+			return nil, err
+		}
+		for _, pkg := range pkgs {
+			recordRefs(pkg)
+		}
+	}
+
+	var result []string
+	for key, file := range refs {
+		result = append(result, fmt.Sprintf("%s (referenced from %s)", key.name, file))
+	}
+	sort.Strings(result)
+	return result, nil
+}
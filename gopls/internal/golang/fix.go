@@ -56,10 +56,14 @@ const (
 	fixExtractVariableAll      = "extract_variable_all"
 	fixExtractFunction         = "extract_function"
 	fixExtractMethod           = "extract_method"
+	fixIfToSwitch              = "if_to_switch"
 	fixInlineCall              = "inline_call"
+	fixInlineConstant          = "inline_constant"
+	fixInlineVariable          = "inline_variable"
 	fixInvertIfCondition       = "invert_if_condition"
 	fixSplitLines              = "split_lines"
 	fixJoinLines               = "join_lines"
+	fixSwitchToIf              = "switch_to_if"
 	fixCreateUndeclared        = "create_undeclared"
 	fixMissingInterfaceMethods = "stub_missing_interface_method"
 	fixMissingCalledFunction   = "stub_missing_called_function"
@@ -102,10 +106,14 @@ func ApplyFix(ctx context.Context, fix string, snapshot *cache.Snapshot, fh file
 		fixExtractMethod:           singleFile(extractMethod),
 		fixExtractVariable:         singleFile(extractVariable),
 		fixExtractVariableAll:      singleFile(extractVariableAll),
+		fixIfToSwitch:              singleFile(ifToSwitch),
 		fixInlineCall:              inlineCall,
+		fixInlineConstant:          inlineConstant,
+		fixInlineVariable:          singleFile(inlineVariable),
 		fixInvertIfCondition:       singleFile(invertIfCondition),
 		fixSplitLines:              singleFile(splitLines),
 		fixJoinLines:               singleFile(joinLines),
+		fixSwitchToIf:              singleFile(switchToIf),
 		fixCreateUndeclared:        singleFile(createUndeclared),
 		fixMissingInterfaceMethods: stubMissingInterfaceMethodsFixer,
 		fixMissingCalledFunction:   stubMissingCalledFunctionFixer,
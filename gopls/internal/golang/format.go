@@ -65,36 +65,39 @@ func Format(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle) ([]pr
 	if err := format.Node(buf, fset, pgf.File); err != nil {
 		return nil, err
 	}
-	formatted := buf.String()
+	formatted, err := applyGofumpt(ctx, snapshot, fh, buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return computeTextEdits(ctx, pgf, string(formatted))
+}
 
-	// Apply additional formatting, if any is supported. Currently, the only
-	// supported additional formatter is gofumpt.
-	if snapshot.Options().Gofumpt {
-		// gofumpt can customize formatting based on language version and module
-		// path, if available.
-		//
-		// Try to derive this information, but fall-back on the default behavior.
-		//
-		// TODO: under which circumstances can we fail to find module information?
-		// Can this, for example, result in inconsistent formatting across saves,
-		// due to pending calls to packages.Load?
-		var opts gofumptFormat.Options
-		meta, err := NarrowestMetadataForFile(ctx, snapshot, fh.URI())
-		if err == nil {
-			if mi := meta.Module; mi != nil {
-				if v := mi.GoVersion; v != "" {
-					opts.LangVersion = "go" + v
-				}
-				opts.ModulePath = mi.Path
+// applyGofumpt applies additional formatting to the gofmt-formatted
+// content of fh, if any is supported. Currently, the only supported
+// additional formatter is gofumpt.
+func applyGofumpt(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, formatted []byte) ([]byte, error) {
+	if !snapshot.Options().Gofumpt {
+		return formatted, nil
+	}
+	// gofumpt can customize formatting based on language version and module
+	// path, if available.
+	//
+	// Try to derive this information, but fall-back on the default behavior.
+	//
+	// TODO: under which circumstances can we fail to find module information?
+	// Can this, for example, result in inconsistent formatting across saves,
+	// due to pending calls to packages.Load?
+	var opts gofumptFormat.Options
+	meta, err := NarrowestMetadataForFile(ctx, snapshot, fh.URI())
+	if err == nil {
+		if mi := meta.Module; mi != nil {
+			if v := mi.GoVersion; v != "" {
+				opts.LangVersion = "go" + v
 			}
+			opts.ModulePath = mi.Path
 		}
-		b, err := gofumptFormat.Source(buf.Bytes(), opts)
-		if err != nil {
-			return nil, err
-		}
-		formatted = string(b)
 	}
-	return computeTextEdits(ctx, pgf, formatted)
+	return gofumptFormat.Source(formatted, opts)
 }
 
 func formatSource(ctx context.Context, fh file.Handle) ([]byte, error) {
@@ -0,0 +1,239 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/event"
+)
+
+// FormatRanges formats the parts of a Go file that intersect the given
+// ranges, each of which is extended to whole lines.
+//
+// The whole file is formatted, but only the edits that lie entirely
+// within one of the (extended) ranges are returned, so lines outside
+// the ranges are never modified, even if they are not well formatted.
+// If a range intersects the import declarations, the imports are
+// organized too, again subject to the same restriction.
+func FormatRanges(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rngs []protocol.Range) ([]protocol.TextEdit, error) {
+	ctx, done := event.Start(ctx, "golang.FormatRanges")
+	defer done()
+
+	pgf, err := snapshot.ParseGo(ctx, fh, parsego.Full)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unlike Format, this operation is permitted on generated
+	// files, since it modifies only the requested lines.
+	if pgf.ParseErr != nil {
+		return nil, fmt.Errorf("can't format %q: %v", fh.URI().Path(), pgf.ParseErr)
+	}
+
+	var spans []lineSpan
+	for _, rng := range rngs {
+		start, end, err := pgf.Mapper.RangeOffsets(rng)
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, lineSpanOf(pgf.Src, start, end))
+	}
+
+	// Organize imports only if a range touches them.
+	src := pgf.Src
+	importStart, importEnd, err := importsSpan(pgf)
+	if err != nil {
+		return nil, err
+	}
+	touchesImports := false
+	for _, span := range spans {
+		if span.start < importEnd && importStart < span.end {
+			touchesImports = true
+			break
+		}
+	}
+	if touchesImports {
+		allFixEdits, _, err := allImportsFixes(ctx, snapshot, pgf)
+		if err != nil {
+			return nil, err
+		}
+		src, _, err = protocol.ApplyEdits(pgf.Mapper, allFixEdits)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, err
+	}
+	formatted, err = applyGofumpt(ctx, snapshot, fh, formatted)
+	if err != nil {
+		return nil, err
+	}
+	return rangeEdits(pgf, formatted, spans)
+}
+
+// FormatOnType returns the formatting edits for the construct that
+// was completed by typing the character ch at position pp:
+//
+//   - after "}", the block (or composite literal, struct type, etc)
+//     closed by the brace is formatted;
+//   - after "\n", the line that was just completed is formatted.
+//
+// Unlike FormatRanges, it reports no error if the file cannot be
+// formatted, since the file is typically incomplete while typing.
+func FormatOnType(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pp protocol.Position, ch string) ([]protocol.TextEdit, error) {
+	ctx, done := event.Start(ctx, "golang.FormatOnType")
+	defer done()
+
+	pgf, err := snapshot.ParseGo(ctx, fh, parsego.Full)
+	if err != nil {
+		return nil, err
+	}
+	if pgf.ParseErr != nil {
+		return nil, nil
+	}
+	offset, err := pgf.Mapper.PositionOffset(pp)
+	if err != nil {
+		return nil, err
+	}
+
+	var span lineSpan
+	switch ch {
+	case "}":
+		// Find the brace, which is usually just before the cursor.
+		rbrace := bytes.LastIndexByte(pgf.Src[:offset], '}')
+		if rbrace < 0 {
+			return nil, nil
+		}
+		start, ok := braceOpening(pgf, rbrace)
+		if !ok {
+			return nil, nil
+		}
+		span = lineSpanOf(pgf.Src, start, rbrace+1)
+
+	case "\n":
+		// Format the previous line, which was just completed.
+		lineStart := bytes.LastIndexByte(pgf.Src[:offset], '\n')
+		if lineStart < 0 {
+			return nil, nil
+		}
+		span = lineSpanOf(pgf.Src, lineStart, lineStart)
+
+	default:
+		return nil, nil
+	}
+
+	formatted, err := format.Source(pgf.Src)
+	if err != nil {
+		return nil, nil // e.g. incomplete code
+	}
+	formatted, err = applyGofumpt(ctx, snapshot, fh, formatted)
+	if err != nil {
+		return nil, nil
+	}
+	return rangeEdits(pgf, formatted, []lineSpan{span})
+}
+
+// A lineSpan is an interval [start, end) of byte offsets that
+// begins at the start of a line and ends at the start of a line (or
+// at EOF).
+type lineSpan struct{ start, end int }
+
+// lineSpanOf returns the lineSpan of the whole lines of src that
+// intersect the interval [start, end).
+func lineSpanOf(src []byte, start, end int) lineSpan {
+	start = bytes.LastIndexByte(src[:start], '\n') + 1 // -1 => 0
+	if end > start && src[end-1] == '\n' {
+		end-- // a range ending at the start of a line does not include that line
+	}
+	if nl := bytes.IndexByte(src[end:], '\n'); nl >= 0 {
+		end += nl + 1
+	} else {
+		end = len(src)
+	}
+	return lineSpan{start, end}
+}
+
+// rangeEdits returns the edits that transform the content of pgf into
+// formatted, restricted to those that lie entirely within one of the
+// given spans.
+func rangeEdits(pgf *parsego.File, formatted []byte, spans []lineSpan) ([]protocol.TextEdit, error) {
+	var kept []diff.Edit
+	for _, edit := range diff.Bytes(pgf.Src, formatted) {
+		for _, span := range spans {
+			// An insertion at the end of a span belongs
+			// to the following line, unless at EOF.
+			if span.start <= edit.Start && edit.End <= span.end &&
+				(edit.Start < span.end || edit.Start == len(pgf.Src)) {
+				kept = append(kept, edit)
+				break
+			}
+		}
+	}
+	return protocol.EditsFromDiffEdits(pgf.Mapper, kept)
+}
+
+// importsSpan returns the interval of byte offsets of the import
+// declarations of pgf, or of its package clause if it has no
+// imports, since that is where new imports would be added.
+func importsSpan(pgf *parsego.File) (start, end int, _ error) {
+	startPos, endPos := pgf.File.Package, pgf.File.Name.End()
+	for _, decl := range pgf.File.Decls {
+		if decl, ok := decl.(*ast.GenDecl); ok && decl.Tok == token.IMPORT {
+			endPos = decl.End()
+		}
+	}
+	return safetoken.Offsets(pgf.Tok, startPos, endPos)
+}
+
+// braceOpening returns the offset of the start of the syntax
+// construct whose closing brace is at offset rbrace.
+func braceOpening(pgf *parsego.File, rbrace int) (int, bool) {
+	pos, err := safetoken.Pos(pgf.Tok, rbrace)
+	if err != nil {
+		return 0, false
+	}
+	path, _ := astutil.PathEnclosingInterval(pgf.File, pos, pos+1)
+	for _, n := range path {
+		var lbrace, closing token.Pos
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			lbrace, closing = n.Lbrace, n.Rbrace
+		case *ast.CompositeLit:
+			lbrace, closing = n.Lbrace, n.Rbrace
+		case *ast.StructType:
+			lbrace, closing = n.Fields.Opening, n.Fields.Closing
+		case *ast.InterfaceType:
+			lbrace, closing = n.Methods.Opening, n.Methods.Closing
+		default:
+			continue
+		}
+		if closing == pos {
+			// Include the statement or declaration that
+			// begins on the same line as the opening brace.
+			offset, err := safetoken.Offset(pgf.Tok, lbrace)
+			if err != nil {
+				return 0, false
+			}
+			return offset, true
+		}
+	}
+	return 0, false
+}
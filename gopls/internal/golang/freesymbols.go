@@ -13,6 +13,7 @@ import (
 	"go/token"
 	"go/types"
 	"html"
+	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
@@ -33,111 +34,7 @@ func FreeSymbolsHTML(viewID string, pkg *cache.Package, pgf *parsego.File, start
 	// Compute free references.
 	refs := freeRefs(pkg.Types(), pkg.TypesInfo(), pgf.File, start, end)
 
-	// -- model --
-
-	type Import struct {
-		Path    metadata.PackagePath
-		Symbols []string
-	}
-	type Symbol struct {
-		Kind string
-		Type string
-		Refs []types.Object
-	}
-	var model struct {
-		Imported []Import
-		PkgLevel []Symbol
-		Local    []Symbol
-	}
-
-	qualifier := typesinternal.NameRelativeTo(pkg.Types())
-
-	// Populate model.
-	{
-		// List the refs in order of dotted paths.
-		sort.Slice(refs, func(i, j int) bool {
-			return refs[i].dotted < refs[j].dotted
-		})
-
-		// Inspect the references.
-		imported := make(map[string][]*freeRef) // refs to imported symbols, by package path
-		seen := make(map[string]bool)           // to de-dup dotted paths
-		for _, ref := range refs {
-			if seen[ref.dotted] {
-				continue // de-dup
-			}
-			seen[ref.dotted] = true
-
-			var symbols *[]Symbol
-			switch ref.scope {
-			case "file":
-				// imported symbol: group by package
-				if pkgname, ok := ref.objects[0].(*types.PkgName); ok {
-					path := pkgname.Imported().Path()
-					imported[path] = append(imported[path], ref)
-				}
-				continue
-			case "pkg":
-				symbols = &model.PkgLevel
-			case "local":
-				symbols = &model.Local
-			default:
-				panic(ref.scope)
-			}
-
-			// Package and local symbols are presented the same way.
-			// We treat each dotted path x.y.z as a separate entity.
-
-			// Compute kind and type of last object (y in obj.x.y).
-			typestr := " " + types.TypeString(ref.typ, qualifier)
-			var kind string
-			switch obj := ref.objects[len(ref.objects)-1].(type) {
-			case *types.Var:
-				kind = "var"
-			case *types.Func:
-				kind = "func"
-			case *types.TypeName:
-				if is[*types.TypeParam](obj.Type()) {
-					kind = "type parameter"
-				} else {
-					kind = "type"
-				}
-				typestr = "" // avoid "type T T"
-			case *types.Const:
-				kind = "const"
-			case *types.Label:
-				kind = "label"
-				typestr = "" // avoid "label L L"
-			}
-
-			*symbols = append(*symbols, Symbol{
-				Kind: kind,
-				Type: typestr,
-				Refs: ref.objects,
-			})
-		}
-
-		// Imported symbols.
-		// Produce one record per package, with a list of symbols.
-		for pkgPath, refs := range moremaps.Sorted(imported) {
-			var syms []string
-			for _, ref := range refs {
-				// strip package name (bytes.Buffer.Len -> Buffer.Len)
-				syms = append(syms, ref.dotted[len(ref.objects[0].Name())+len("."):])
-			}
-			sort.Strings(syms)
-			const max = 4
-			if len(syms) > max {
-				syms[max-1] = fmt.Sprintf("... (%d)", len(syms))
-				syms = syms[:max]
-			}
-
-			model.Imported = append(model.Imported, Import{
-				Path:    PackagePath(pkgPath),
-				Symbols: syms,
-			})
-		}
-	}
+	model := newFreeSymbolsModel(pkg, refs)
 
 	// -- presentation --
 
@@ -182,7 +79,7 @@ p { max-width: 6in; }
 
 	// -- package and local symbols --
 
-	showSymbols := func(scope, title string, symbols []Symbol) {
+	showSymbols := func(scope, title string, symbols []freeSymbol) {
 		fmt.Fprintf(&buf, "<h2><span class='col-%s'>⬤</span> %s</h2>\n", scope, title)
 		fmt.Fprintf(&buf, "<ul>\n")
 		pre := buf.Len()
@@ -259,6 +156,156 @@ p { max-width: 6in; }
 	return buf.Bytes()
 }
 
+// FreeSymbolsText returns a plain-text report of the free symbols
+// referenced by the selection, for display in the client editor as a
+// read-only document.
+func FreeSymbolsText(pkg *cache.Package, pgf *parsego.File, start, end token.Pos) []byte {
+	refs := freeRefs(pkg.Types(), pkg.TypesInfo(), pgf.File, start, end)
+	model := newFreeSymbolsModel(pkg, refs)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Free symbols referenced by the selection in %s.\n", filepath.Base(pgf.URI.Path()))
+	fmt.Fprintf(&buf, "// A symbol is free if it is referenced within the selection\n")
+	fmt.Fprintf(&buf, "// but declared outside of it.\n")
+
+	fmt.Fprintf(&buf, "\n// Imported symbols\n")
+	for _, imp := range model.Imported {
+		fmt.Fprintf(&buf, "import %q // for %s\n", imp.Path, strings.Join(imp.Symbols, ", "))
+	}
+	if len(model.Imported) == 0 {
+		fmt.Fprintf(&buf, "// (none)\n")
+	}
+
+	showSymbols := func(title string, symbols []freeSymbol) {
+		fmt.Fprintf(&buf, "\n// %s\n", title)
+		for _, sym := range symbols {
+			names := make([]string, len(sym.Refs))
+			for i, obj := range sym.Refs {
+				names[i] = obj.Name()
+			}
+			fmt.Fprintf(&buf, "%s %s%s\n", sym.Kind, strings.Join(names, "."), sym.Type)
+		}
+		if len(symbols) == 0 {
+			fmt.Fprintf(&buf, "// (none)\n")
+		}
+	}
+	showSymbols("Package-level symbols", model.PkgLevel)
+	showSymbols("Local symbols", model.Local)
+	return buf.Bytes()
+}
+
+// freeSymbolsModel is the model of the free symbols report,
+// common to its presentations.
+type freeSymbolsModel struct {
+	Imported []freeImport
+	PkgLevel []freeSymbol
+	Local    []freeSymbol
+}
+
+type freeImport struct {
+	Path    metadata.PackagePath
+	Symbols []string
+}
+
+type freeSymbol struct {
+	Kind string
+	Type string
+	Refs []types.Object
+}
+
+// newFreeSymbolsModel populates the model of the free symbols report
+// from the references to free symbols, which it sorts by dotted path.
+func newFreeSymbolsModel(pkg *cache.Package, refs []*freeRef) *freeSymbolsModel {
+	var model freeSymbolsModel
+
+	qualifier := typesinternal.NameRelativeTo(pkg.Types())
+
+	// List the refs in order of dotted paths.
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].dotted < refs[j].dotted
+	})
+
+	// Inspect the references.
+	imported := make(map[string][]*freeRef) // refs to imported symbols, by package path
+	seen := make(map[string]bool)           // to de-dup dotted paths
+	for _, ref := range refs {
+		if seen[ref.dotted] {
+			continue // de-dup
+		}
+		seen[ref.dotted] = true
+
+		var symbols *[]freeSymbol
+		switch ref.scope {
+		case "file":
+			// imported symbol: group by package
+			if pkgname, ok := ref.objects[0].(*types.PkgName); ok {
+				path := pkgname.Imported().Path()
+				imported[path] = append(imported[path], ref)
+			}
+			continue
+		case "pkg":
+			symbols = &model.PkgLevel
+		case "local":
+			symbols = &model.Local
+		default:
+			panic(ref.scope)
+		}
+
+		// Package and local symbols are presented the same way.
+		// We treat each dotted path x.y.z as a separate entity.
+
+		// Compute kind and type of last object (y in obj.x.y).
+		typestr := " " + types.TypeString(ref.typ, qualifier)
+		var kind string
+		switch obj := ref.objects[len(ref.objects)-1].(type) {
+		case *types.Var:
+			kind = "var"
+		case *types.Func:
+			kind = "func"
+		case *types.TypeName:
+			if is[*types.TypeParam](obj.Type()) {
+				kind = "type parameter"
+			} else {
+				kind = "type"
+			}
+			typestr = "" // avoid "type T T"
+		case *types.Const:
+			kind = "const"
+		case *types.Label:
+			kind = "label"
+			typestr = "" // avoid "label L L"
+		}
+
+		*symbols = append(*symbols, freeSymbol{
+			Kind: kind,
+			Type: typestr,
+			Refs: ref.objects,
+		})
+	}
+
+	// Imported symbols.
+	// Produce one record per package, with a list of symbols.
+	for pkgPath, refs := range moremaps.Sorted(imported) {
+		var syms []string
+		for _, ref := range refs {
+			// strip package name (bytes.Buffer.Len -> Buffer.Len)
+			syms = append(syms, ref.dotted[len(ref.objects[0].Name())+len("."):])
+		}
+		sort.Strings(syms)
+		const max = 4
+		if len(syms) > max {
+			syms[max-1] = fmt.Sprintf("... (%d)", len(syms))
+			syms = syms[:max]
+		}
+
+		model.Imported = append(model.Imported, freeImport{
+			Path:    PackagePath(pkgPath),
+			Symbols: syms,
+		})
+	}
+	return &model
+}
+
 // A freeRef records a reference to a dotted path obj.x.y,
 // where obj (=objects[0]) is a free symbol.
 type freeRef struct {
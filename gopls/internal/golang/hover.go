@@ -1191,6 +1191,28 @@ func HoverDocForObject(ctx context.Context, snapshot *cache.Snapshot, fset *toke
 	return chooseDocComment(decl, spec, field), nil
 }
 
+// HoverDocAt returns the doc comment of the declaration of the symbol
+// declared at the specified offset of a Go file.
+//
+// It is equivalent to [HoverDocForObject], for use when the object
+// is no longer available.
+func HoverDocAt(ctx context.Context, snapshot *cache.Snapshot, uri protocol.DocumentURI, offset int) (*ast.CommentGroup, error) {
+	fh, err := snapshot.ReadFile(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	pgf, err := snapshot.ParseGo(ctx, fh, parsego.Full)
+	if err != nil {
+		return nil, err
+	}
+	pos, err := safetoken.Pos(pgf.Tok, offset)
+	if err != nil {
+		return nil, err
+	}
+	decl, spec, field := findDeclInfo([]*ast.File{pgf.File}, pos)
+	return chooseDocComment(decl, spec, field), nil
+}
+
 func chooseDocComment(decl ast.Decl, spec ast.Spec, field *ast.Field) *ast.CommentGroup {
 	if field != nil {
 		if field.Doc != nil {
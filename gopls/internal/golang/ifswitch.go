@@ -0,0 +1,717 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the refactor.rewrite.{ifToSwitch,switchToIf} code
+// actions, which convert between if/else-if chains and switch
+// statements.
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	goplsastutil "golang.org/x/tools/gopls/internal/util/astutil"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/astutil/cursor"
+)
+
+// ifToSwitch is a singleFileFixer that converts the if/else-if chain
+// whose header encloses the selection to a switch statement.
+func ifToSwitch(pkg *cache.Package, pgf *parsego.File, start, end token.Pos) (*token.FileSet, *analysis.SuggestedFix, error) {
+	stmt, text, _, err := ifToSwitchText(pkg.TypesInfo(), pgf, start, end)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pkg.FileSet(), &analysis.SuggestedFix{
+		TextEdits: []analysis.TextEdit{{Pos: stmt.Pos(), End: stmt.End(), NewText: []byte(text)}},
+	}, nil
+}
+
+// switchToIf is a singleFileFixer that converts the switch or type
+// switch statement whose header encloses the selection to an
+// if/else-if chain.
+func switchToIf(pkg *cache.Package, pgf *parsego.File, start, end token.Pos) (*token.FileSet, *analysis.SuggestedFix, error) {
+	stmt, text, err := switchToIfText(pkg.TypesInfo(), pgf, start, end)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pkg.FileSet(), &analysis.SuggestedFix{
+		TextEdits: []analysis.TextEdit{{Pos: stmt.Pos(), End: stmt.End(), NewText: []byte(text)}},
+	}, nil
+}
+
+// selectedStmt returns the innermost if, switch, or type switch
+// statement enclosing the selection, provided that the selection
+// lies in its header, not within one of its blocks or clauses.
+// For an if statement, it returns the first if of its else-if chain.
+func selectedStmt(pgf *parsego.File, start, end token.Pos) (cursor.Cursor, bool) {
+	cur, ok := pgf.Cursor.FindPos(start, end)
+	if !ok {
+		return cursor.Cursor{}, false
+	}
+	if !is[*ast.IfStmt](cur.Node()) && !is[*ast.SwitchStmt](cur.Node()) && !is[*ast.TypeSwitchStmt](cur.Node()) {
+		for cur = range cur.Ancestors((*ast.IfStmt)(nil), (*ast.SwitchStmt)(nil), (*ast.TypeSwitchStmt)(nil)) {
+			break
+		}
+	}
+	within := func(n ast.Node) bool { return n.Pos() <= start && end <= n.End() }
+	switch n := cur.Node().(type) {
+	case *ast.IfStmt:
+		if within(n.Body) || n.Else != nil && is[*ast.BlockStmt](n.Else) && within(n.Else) {
+			return cursor.Cursor{}, false
+		}
+		for {
+			parent, ok := cur.Parent().Node().(*ast.IfStmt)
+			if !ok || parent.Else != cur.Node() {
+				break
+			}
+			cur = cur.Parent()
+		}
+		return cur, true
+	case *ast.SwitchStmt:
+		return cur, n.Pos() <= start && end <= n.Body.Lbrace || clauseHeadersEnclose(n.Body, start, end)
+	case *ast.TypeSwitchStmt:
+		return cur, n.Pos() <= start && end <= n.Body.Lbrace || clauseHeadersEnclose(n.Body, start, end)
+	}
+	return cursor.Cursor{}, false
+}
+
+// clauseHeadersEnclose reports whether the range [start, end) lies
+// within the "case ...:" header of one of the clauses of a switch body.
+func clauseHeadersEnclose(body *ast.BlockStmt, start, end token.Pos) bool {
+	for _, clause := range body.List {
+		clause := clause.(*ast.CaseClause)
+		if clause.Case <= start && end <= clause.Colon+1 {
+			return true
+		}
+	}
+	return false
+}
+
+// An ifClause is a clause of an if/else-if chain.
+type ifClause struct {
+	ifStmt *ast.IfStmt // nil for the final else block
+	body   *ast.BlockStmt
+}
+
+// ifToSwitchText returns the first if statement of the if/else-if
+// chain whose header encloses the selection, the text of the
+// equivalent switch statement, and whether it is a type switch.
+//
+// An expression switch is formed when the conditions are disjunctions
+// of comparisons x == v of the same pure expression x, and a type
+// switch when the if statements are of the form
+// "if v, ok := x.(T); ok" for the same pure expression x.
+func ifToSwitchText(info *types.Info, pgf *parsego.File, start, end token.Pos) (*ast.IfStmt, string, bool, error) {
+	cur, ok := selectedStmt(pgf, start, end)
+	if !ok {
+		return nil, "", false, errors.New("selection is not in the header of an if statement")
+	}
+	root, ok := cur.Node().(*ast.IfStmt)
+	if !ok {
+		return nil, "", false, errors.New("selection is not in the header of an if statement")
+	}
+	var clauses []ifClause
+	for stmt := root; ; {
+		clauses = append(clauses, ifClause{stmt, stmt.Body})
+		if stmt.Init != nil && stmt != root && !isTypeAssertionInit(stmt) {
+			return nil, "", false, errors.New("an else-if statement has an initialization statement")
+		}
+		if next, ok := stmt.Else.(*ast.IfStmt); ok {
+			stmt = next
+			continue
+		}
+		if block, ok := stmt.Else.(*ast.BlockStmt); ok {
+			clauses = append(clauses, ifClause{nil, block})
+		}
+		break
+	}
+	if len(clauses) < 2 || clauses[1].ifStmt == nil {
+		return nil, "", false, errors.New("if statement has no else-if clauses")
+	}
+	for _, clause := range clauses {
+		if hasBreak(clause.body) {
+			return nil, "", false, errors.New("a block contains a break statement")
+		}
+	}
+
+	var (
+		src        = pgf.Src
+		text       = func(n ast.Node) string { return nodeText(pgf, n) }
+		indent     = lineIndent(pgf, root.Pos())
+		buf        strings.Builder
+		kept       []ast.Node // nodes whose text (and comments) are preserved
+		typeSwitch = isTypeAssertionInit(root)
+	)
+	for _, clause := range clauses {
+		kept = append(kept, clause.body)
+	}
+
+	if typeSwitch {
+		// Type switch: each if is "if v, ok := x.(T); ok".
+		var (
+			x       ast.Expr
+			binding string // name of v, if used in any clause
+			types_  []types.Type
+		)
+		for _, clause := range clauses {
+			stmt := clause.ifStmt
+			if stmt == nil {
+				continue
+			}
+			if !isTypeAssertionInit(stmt) {
+				return nil, "", false, errors.New("not all conditions are type assertions")
+			}
+			assign := stmt.Init.(*ast.AssignStmt)
+			assert := assign.Rhs[0].(*ast.TypeAssertExpr)
+			if x == nil {
+				x = assert.X
+				if hasEffects(info, x) {
+					return nil, "", false, fmt.Errorf("%s may have side effects", text(x))
+				}
+			} else if !sameExpr(info, x, assert.X) {
+				return nil, "", false, errors.New("the type assertions are not of the same expression")
+			}
+			t := info.TypeOf(assert.Type)
+			for _, prev := range types_ {
+				if types.Identical(prev, t) {
+					return nil, "", false, fmt.Errorf("duplicate case %s", text(assert.Type))
+				}
+			}
+			types_ = append(types_, t)
+			kept = append(kept, assert.Type)
+
+			// v and ok must not be used outside the block
+			// that follows their declaration (ok not at all).
+			v, ok := assign.Lhs[0].(*ast.Ident), assign.Lhs[1].(*ast.Ident)
+			vobj, okobj := info.Defs[v], info.Defs[ok]
+			for cur := range cur.Preorder((*ast.Ident)(nil)) {
+				id := cur.Node().(*ast.Ident)
+				switch obj := info.Uses[id]; {
+				case obj == nil:
+				case obj == okobj && id != stmt.Cond:
+					return nil, "", false, fmt.Errorf("%s is used other than as the condition", ok.Name)
+				case obj == vobj && !goplsastutil.NodeContains(stmt.Body, id.Pos()):
+					return nil, "", false, fmt.Errorf("%s is used outside the block of its if statement", v.Name)
+				case obj == vobj:
+					if binding != "" && binding != v.Name {
+						return nil, "", false, errors.New("the asserted values have different names")
+					}
+					binding = v.Name
+				}
+			}
+		}
+		// The binding must not shadow a variable of the same name
+		// that is used in any block.
+		if binding != "" {
+			for _, clause := range clauses {
+				var vobj types.Object
+				if clause.ifStmt != nil {
+					vobj = info.Defs[clause.ifStmt.Init.(*ast.AssignStmt).Lhs[0].(*ast.Ident)]
+				}
+				for cur := range cur.Preorder((*ast.Ident)(nil)) {
+					id := cur.Node().(*ast.Ident)
+					if id.Name == binding && goplsastutil.NodeContains(clause.body, id.Pos()) {
+						if obj := info.Uses[id]; obj != nil && obj != vobj && !goplsastutil.NodeContains(clause.body, obj.Pos()) {
+							return nil, "", false, fmt.Errorf("the switch variable %s would shadow another variable", binding)
+						}
+					}
+				}
+			}
+			fmt.Fprintf(&buf, "switch %s := %s.(type) {\n", binding, text(x))
+		} else {
+			fmt.Fprintf(&buf, "switch %s.(type) {\n", text(x))
+		}
+		kept = append(kept, x)
+		for _, clause := range clauses {
+			if clause.ifStmt != nil {
+				fmt.Fprintf(&buf, "%scase %s:", indent, text(clause.ifStmt.Init.(*ast.AssignStmt).Rhs[0].(*ast.TypeAssertExpr).Type))
+			} else {
+				fmt.Fprintf(&buf, "%sdefault:", indent)
+			}
+			buf.WriteString(bodyText(src, pgf, clause.body.Lbrace+1, clause.body.Rbrace, indent))
+		}
+
+	} else {
+		// Expression switch: each condition is a disjunction of x == v.
+		var disjuncts [][]*ast.BinaryExpr // per clause
+		for _, clause := range clauses {
+			if clause.ifStmt == nil {
+				continue
+			}
+			var eqs []*ast.BinaryExpr
+			for _, e := range splitOr(clause.ifStmt.Cond) {
+				eq, ok := ast.Unparen(e).(*ast.BinaryExpr)
+				if !ok || eq.Op != token.EQL {
+					return nil, "", false, fmt.Errorf("condition %s is not a comparison", text(e))
+				}
+				eqs = append(eqs, eq)
+			}
+			disjuncts = append(disjuncts, eqs)
+		}
+
+		// Choose the tag: the operand of the first comparison
+		// that appears in every comparison.
+		var tag ast.Expr
+		first := disjuncts[0][0]
+		for _, x := range []ast.Expr{first.X, first.Y} {
+			if info.Types[x].Value != nil || hasEffects(info, x) {
+				continue
+			}
+			all := true
+			for _, eqs := range disjuncts {
+				for _, eq := range eqs {
+					if !sameExpr(info, x, eq.X) && !sameExpr(info, x, eq.Y) {
+						all = false
+					}
+				}
+			}
+			if all {
+				tag = x
+				break
+			}
+		}
+		if tag == nil {
+			return nil, "", false, errors.New("the conditions do not compare the same pure expression")
+		}
+		kept = append(kept, tag)
+		if root.Init != nil {
+			kept = append(kept, root.Init)
+		}
+
+		buf.WriteString("switch ")
+		if root.Init != nil {
+			buf.WriteString(text(root.Init) + "; ")
+		}
+		buf.WriteString(text(tag) + " {\n")
+		seen := make(map[string]bool) // constant values
+		i := 0
+		for _, clause := range clauses {
+			if clause.ifStmt == nil {
+				fmt.Fprintf(&buf, "%sdefault:", indent)
+			} else {
+				var values []string
+				for _, eq := range disjuncts[i] {
+					v := eq.Y
+					if !sameExpr(info, tag, eq.X) {
+						v = eq.X
+					}
+					if hasEffects(info, v) {
+						return nil, "", false, fmt.Errorf("%s may have side effects", text(v))
+					}
+					if cv := info.Types[v].Value; cv != nil {
+						if seen[cv.ExactString()] {
+							return nil, "", false, fmt.Errorf("duplicate case %s", text(v))
+						}
+						seen[cv.ExactString()] = true
+					}
+					values = append(values, text(v))
+					kept = append(kept, v)
+				}
+				i++
+				fmt.Fprintf(&buf, "%scase %s:", indent, strings.Join(values, ", "))
+			}
+			buf.WriteString(bodyText(src, pgf, clause.body.Lbrace+1, clause.body.Rbrace, indent))
+		}
+	}
+	buf.WriteString(indent + "}")
+
+	if err := checkComments(pgf, root, kept); err != nil {
+		return nil, "", false, err
+	}
+	return root, buf.String(), typeSwitch, nil
+}
+
+// switchToIfText returns the switch or type switch statement whose
+// header encloses the selection, and the text of the equivalent
+// if/else-if chain.
+//
+// The tag of an expression switch, or the operand of a type switch,
+// must be a pure expression, since it is evaluated by each condition;
+// and each clause of a type switch other than those for a single
+// type must not use the switch variable.
+func switchToIfText(info *types.Info, pgf *parsego.File, start, end token.Pos) (ast.Stmt, string, error) {
+	cur, ok := selectedStmt(pgf, start, end)
+	if !ok {
+		return nil, "", errors.New("selection is not in the header of a switch statement")
+	}
+	stmt, ok := cur.Node().(ast.Stmt)
+	if !ok || is[*ast.IfStmt](stmt) {
+		return nil, "", errors.New("selection is not in the header of a switch statement")
+	}
+	if is[*ast.LabeledStmt](cur.Parent().Node()) {
+		return nil, "", errors.New("switch statement is labeled")
+	}
+
+	var (
+		src     = pgf.Src
+		text    = func(n ast.Node) string { return nodeText(pgf, n) }
+		indent  = lineIndent(pgf, stmt.Pos())
+		body    *ast.BlockStmt
+		kept    []ast.Node
+		conds   []string // condition, or "" for default, per clause
+		init    ast.Stmt
+		clauses []*ast.CaseClause
+	)
+	switch stmt := stmt.(type) {
+	case *ast.SwitchStmt:
+		body, init = stmt.Body, stmt.Init
+		if stmt.Tag != nil {
+			if hasEffects(info, stmt.Tag) {
+				return nil, "", fmt.Errorf("%s may have side effects", text(stmt.Tag))
+			}
+			kept = append(kept, stmt.Tag)
+		}
+		if init != nil {
+			kept = append(kept, init)
+		}
+		for _, clause := range body.List {
+			clause := clause.(*ast.CaseClause)
+			var disjuncts []string
+			for _, e := range clause.List {
+				if hasEffects(info, e) {
+					return nil, "", fmt.Errorf("%s may have side effects", text(e))
+				}
+				kept = append(kept, e)
+				if stmt.Tag != nil {
+					disjuncts = append(disjuncts, operandText(pgf, stmt.Tag)+" == "+operandText(pgf, e))
+				} else {
+					disjuncts = append(disjuncts, text(e))
+				}
+			}
+			clauses = append(clauses, clause)
+			conds = append(conds, strings.Join(disjuncts, " || "))
+		}
+
+	case *ast.TypeSwitchStmt:
+		body = stmt.Body
+		if stmt.Init != nil {
+			return nil, "", errors.New("type switch has an initialization statement")
+		}
+		var (
+			binding string
+			x       ast.Expr
+		)
+		switch assign := stmt.Assign.(type) {
+		case *ast.AssignStmt:
+			binding = assign.Lhs[0].(*ast.Ident).Name
+			x = assign.Rhs[0].(*ast.TypeAssertExpr).X
+		case *ast.ExprStmt:
+			x = assign.X.(*ast.TypeAssertExpr).X
+		}
+		if hasEffects(info, x) {
+			return nil, "", fmt.Errorf("%s may have side effects", text(x))
+		}
+		kept = append(kept, x)
+
+		// Choose a name for the ok variable that
+		// is not used within the switch statement.
+		okName := "ok"
+		for i := 1; ; i++ {
+			used := false
+			for cur := range cur.Preorder((*ast.Ident)(nil)) {
+				if cur.Node().(*ast.Ident).Name == okName {
+					used = true
+					break
+				}
+			}
+			if !used {
+				break
+			}
+			okName = fmt.Sprintf("ok%d", i)
+		}
+
+		for _, clause := range body.List {
+			clause := clause.(*ast.CaseClause)
+			// Is the switch variable used in this clause?
+			used := false
+			if obj := info.Implicits[clause]; obj != nil {
+				for cur := range cur.Preorder((*ast.Ident)(nil)) {
+					if info.Uses[cur.Node().(*ast.Ident)] == obj {
+						used = true
+						break
+					}
+				}
+			}
+			switch {
+			case len(clause.List) == 0:
+				if used {
+					return nil, "", fmt.Errorf("%s is used in the default clause", binding)
+				}
+				conds = append(conds, "")
+			case len(clause.List) > 1:
+				return nil, "", fmt.Errorf("a case has more than one type")
+			case isNilIdent(info, clause.List[0]):
+				if used {
+					return nil, "", fmt.Errorf("%s is used in the nil case", binding)
+				}
+				conds = append(conds, operandText(pgf, x)+" == nil")
+			default:
+				v := "_"
+				if used {
+					v = binding
+				}
+				conds = append(conds, fmt.Sprintf("%s, %s := %s.(%s); %s", v, okName, text(x), text(clause.List[0]), okName))
+			}
+			for _, e := range clause.List {
+				kept = append(kept, e)
+			}
+			clauses = append(clauses, clause)
+		}
+	}
+
+	// Check for statements that would change meaning.
+	for _, clause := range clauses {
+		for _, s := range clause.Body {
+			if hasBreak(s) {
+				return nil, "", errors.New("a case contains a break statement")
+			}
+			if branch, ok := s.(*ast.BranchStmt); ok && branch.Tok == token.FALLTHROUGH {
+				return nil, "", errors.New("a case contains a fallthrough statement")
+			}
+		}
+	}
+	ncases := 0
+	for _, cond := range conds {
+		if cond != "" {
+			ncases++
+		}
+	}
+	if ncases == 0 {
+		return nil, "", errors.New("switch statement has no cases")
+	}
+
+	// A comment on the line of the opening brace of the switch body
+	// is kept on the line of the opening brace of the first block.
+	var headComment string
+	if len(body.List) > 0 {
+		start, end, err := safetoken.Offsets(pgf.Tok, body.Lbrace+1, body.List[0].Pos())
+		if err != nil {
+			return nil, "", err
+		}
+		head := strings.TrimSpace(string(src[start:end]))
+		if strings.HasPrefix(head, "//") && !strings.Contains(head, "\n") {
+			headComment = " " + head
+			kept = append(kept, span{body.Lbrace, body.List[0].Pos()})
+		}
+	}
+
+	// Format the chain, with the default clause (if any) last.
+	var buf strings.Builder
+	dflt := -1
+	for i, clause := range clauses {
+		if conds[i] == "" {
+			dflt = i
+			continue
+		}
+		if buf.Len() == 0 {
+			buf.WriteString("if ")
+			if init != nil {
+				buf.WriteString(text(init) + "; ")
+			}
+		} else {
+			buf.WriteString(" else if ")
+		}
+		buf.WriteString(conds[i] + " {")
+		if headComment != "" {
+			buf.WriteString(headComment)
+			headComment = ""
+		}
+		buf.WriteString(bodyText(src, pgf, clause.Colon+1, clauseEnd(body, i), indent))
+		buf.WriteString(indent + "}")
+	}
+	if dflt >= 0 {
+		buf.WriteString(" else {")
+		buf.WriteString(bodyText(src, pgf, clauses[dflt].Colon+1, clauseEnd(body, dflt), indent))
+		buf.WriteString(indent + "}")
+	}
+
+	for i, clause := range clauses {
+		kept = append(kept, span{clause.Colon, clauseEnd(body, i)})
+	}
+	if err := checkComments(pgf, stmt, kept); err != nil {
+		return nil, "", err
+	}
+	return stmt, buf.String(), nil
+}
+
+// A span is an ast.Node for an arbitrary range of the source.
+type span struct{ pos, end token.Pos }
+
+func (s span) Pos() token.Pos { return s.pos }
+func (s span) End() token.Pos { return s.end }
+
+// clauseEnd returns the end of the ith clause of a switch body,
+// including any comments that follow its last statement.
+func clauseEnd(body *ast.BlockStmt, i int) token.Pos {
+	if i+1 < len(body.List) {
+		return body.List[i+1].Pos()
+	}
+	return body.Rbrace
+}
+
+// isTypeAssertionInit reports whether the if statement is of the form
+// "if v, ok := x.(T); ok".
+func isTypeAssertionInit(stmt *ast.IfStmt) bool {
+	assign, ok := stmt.Init.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != 2 || len(assign.Rhs) != 1 {
+		return false
+	}
+	if assert, ok := assign.Rhs[0].(*ast.TypeAssertExpr); !ok || assert.Type == nil {
+		return false
+	}
+	okVar, ok1 := assign.Lhs[1].(*ast.Ident)
+	cond, ok2 := stmt.Cond.(*ast.Ident)
+	return ok1 && ok2 && okVar.Name != "_" && cond.Name == okVar.Name && is[*ast.Ident](assign.Lhs[0])
+}
+
+// isNilIdent reports whether e denotes the predeclared nil.
+func isNilIdent(info *types.Info, e ast.Expr) bool {
+	id, ok := ast.Unparen(e).(*ast.Ident)
+	return ok && is[*types.Nil](info.Uses[id])
+}
+
+// splitOr returns the operands of a chain of || operations.
+func splitOr(e ast.Expr) []ast.Expr {
+	if bin, ok := ast.Unparen(e).(*ast.BinaryExpr); ok && bin.Op == token.LOR {
+		return append(splitOr(bin.X), splitOr(bin.Y)...)
+	}
+	return []ast.Expr{e}
+}
+
+// sameExpr reports whether x and y are the same expression,
+// whose identifiers refer to the same objects.
+func sameExpr(info *types.Info, x, y ast.Expr) bool {
+	return goplsastutil.Equal(ast.Unparen(x), ast.Unparen(y), func(x, y *ast.Ident) bool {
+		return x.Name == y.Name && info.ObjectOf(x) == info.ObjectOf(y)
+	})
+}
+
+// hasEffects reports whether evaluation of e may have effects other
+// than a panic: a call to a function, other than a conversion or a
+// pure built-in, or a receive from a channel.
+func hasEffects(info *types.Info, e ast.Expr) bool {
+	effects := false
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false // prune descent: the body is not evaluated
+		case *ast.CallExpr:
+			if info.Types[n.Fun].IsType() {
+				break // conversion
+			}
+			if b, ok := typeutil.Callee(info, n).(*types.Builtin); ok {
+				switch b.Name() {
+				case "len", "cap", "complex", "real", "imag", "min", "max":
+					return true
+				}
+			}
+			effects = true
+		case *ast.UnaryExpr:
+			if n.Op == token.ARROW {
+				effects = true
+			}
+		}
+		return !effects
+	})
+	return effects
+}
+
+// hasBreak reports whether n contains an unlabeled break statement
+// whose target is not within n.
+func hasBreak(n ast.Node) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt, *ast.FuncLit:
+			return false
+		case *ast.BranchStmt:
+			if n.Tok == token.BREAK && n.Label == nil {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// checkComments returns an error if a comment within stmt is not
+// within one of the kept nodes, and so would be lost.
+func checkComments(pgf *parsego.File, stmt ast.Node, kept []ast.Node) error {
+	for _, cg := range pgf.File.Comments {
+		if stmt.Pos() <= cg.Pos() && cg.End() <= stmt.End() {
+			lost := true
+			for _, n := range kept {
+				if n.Pos() <= cg.Pos() && cg.End() <= n.End() {
+					lost = false
+					break
+				}
+			}
+			if lost {
+				return fmt.Errorf("the comment at line %d would be lost", safetoken.Line(pgf.Tok, cg.Pos()))
+			}
+		}
+	}
+	return nil
+}
+
+// nodeText returns the source text of node n.
+func nodeText(pgf *parsego.File, n ast.Node) string {
+	start, end, err := safetoken.Offsets(pgf.Tok, n.Pos(), n.End())
+	if err != nil {
+		return ""
+	}
+	return string(pgf.Src[start:end])
+}
+
+// operandText returns the source text of e as an operand of ==,
+// parenthesized if necessary.
+func operandText(pgf *parsego.File, e ast.Expr) string {
+	if bin, ok := e.(*ast.BinaryExpr); ok && bin.Op.Precedence() <= token.EQL.Precedence() {
+		return "(" + nodeText(pgf, e) + ")"
+	}
+	return nodeText(pgf, e)
+}
+
+// lineIndent returns the indentation of the line containing pos.
+func lineIndent(pgf *parsego.File, pos token.Pos) string {
+	offset, err := safetoken.Offset(pgf.Tok, pos)
+	if err != nil {
+		return ""
+	}
+	line := pgf.Src[bytes.LastIndexByte(pgf.Src[:offset], '\n')+1 : offset]
+	return string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+}
+
+// bodyText returns the text of a block or clause body, between start
+// and end, in a form that begins with a newline or a comment and ends
+// with a newline, so that it can be followed by a line with the given
+// indentation.
+func bodyText(src []byte, pgf *parsego.File, start, end token.Pos, indent string) string {
+	startOffset, endOffset, err := safetoken.Offsets(pgf.Tok, start, end)
+	if err != nil {
+		return "\n"
+	}
+	body := strings.TrimRight(string(src[startOffset:endOffset]), " \t")
+	switch {
+	case strings.TrimSpace(body) == "":
+		return "\n"
+	case !strings.Contains(body, "\n"):
+		// A block on a single line.
+		return "\n" + indent + "\t" + strings.TrimSpace(body) + "\n"
+	case !strings.HasSuffix(body, "\n"):
+		return body + "\n"
+	}
+	return body
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"go/ast"
 	"go/parser"
+	"go/token"
 	"go/types"
 
 	"golang.org/x/tools/go/ast/astutil"
@@ -17,11 +18,18 @@ import (
 	"golang.org/x/tools/gopls/internal/cache/parsego"
 	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/gopls/internal/util/bug"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
 	"golang.org/x/tools/internal/refactor/inline"
 )
 
+// dynamicCall is the description recorded by inlineAllCalls for a
+// conflicting reference that is a dynamic call.
+const dynamicCall = "dynamic call"
+
 // inlineAllCalls inlines all calls to the original function declaration
 // described by callee, returning the resulting modified file content.
+// References that are not static calls, and so cannot be inlined, are
+// recorded in conflicts.
 //
 // inlining everything is currently an expensive operation: it involves re-type
 // checking every package that contains a potential call, as reported by
@@ -44,7 +52,7 @@ import (
 //
 // The code below notes where are assumptions are made that only hold true in
 // the case of parameter removal (annotated with 'Assumption:')
-func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, origDecl *ast.FuncDecl, callee *inline.Callee, post func([]byte) []byte, opts *inline.Options) (map[protocol.DocumentURI][]byte, error) {
+func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, origDecl *ast.FuncDecl, callee *inline.Callee, post func([]byte) []byte, opts *inline.Options, conflicts map[token.Position]string) (map[protocol.DocumentURI][]byte, error) {
 	// Collect references.
 	var refs []protocol.Location
 	{
@@ -138,7 +146,8 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 			//    use(f)
 			// is replaced by
 			//    use(func(...) { f(...) })
-			return nil, fmt.Errorf("cannot inline: found non-call function reference %v", ref)
+			conflicts[safetoken.StartPosition(refpkg.FileSet(), start)] = "non-call function reference"
+			continue
 		}
 
 		// Heuristic: ignore references that overlap with type checker errors, as they may
@@ -155,7 +164,8 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 		}
 
 		if typeutil.StaticCallee(refpkg.TypesInfo(), call) == nil {
-			continue // dynamic call
+			conflicts[safetoken.StartPosition(refpkg.FileSet(), start)] = dynamicCall
+			continue
 		}
 
 		// Sanity check.
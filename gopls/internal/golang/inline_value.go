@@ -0,0 +1,172 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/astutil/cursor"
+	"golang.org/x/tools/internal/event"
+)
+
+// InlineValues returns the values that a debugger, stopped at the
+// specified location, should display inline within the range rng of
+// a Go file.
+//
+// Values are reported for each reference, within the function
+// enclosing the stopping point, to a local variable or parameter that
+// is in scope at that point, and for each selection of a field from
+// such a variable, such as x.f.g. References after the line on which
+// execution stopped are ignored, as they have not yet been reached.
+func InlineValues(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rng, stopped protocol.Range) ([]protocol.InlineValue, error) {
+	ctx, done := event.Start(ctx, "golang.InlineValues")
+	defer done()
+
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, fmt.Errorf("getting file for InlineValues: %w", err)
+	}
+	start, end, err := pgf.RangePos(rng)
+	if err != nil {
+		return nil, err
+	}
+	stopStart, stopEnd, err := pgf.RangePos(stopped)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ignore everything after the line on which execution stopped.
+	if line := safetoken.Line(pgf.Tok, stopEnd); line < pgf.Tok.LineCount() {
+		end = min(end, pgf.Tok.LineStart(line+1))
+	}
+
+	// Find the innermost function enclosing the stopping point.
+	curStop, ok := pgf.Cursor.FindPos(stopStart, stopEnd)
+	if !ok {
+		return nil, nil
+	}
+	var curFunc cursor.Cursor
+	switch curStop.Node().(type) {
+	case *ast.FuncDecl, *ast.FuncLit:
+		curFunc = curStop
+	default:
+		for cur := range curStop.Ancestors((*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)) {
+			curFunc = cur
+			break
+		}
+	}
+	if curFunc.Node() == nil {
+		return nil, nil // not within a function
+	}
+	start = max(start, curFunc.Node().Pos())
+	end = min(end, curFunc.Node().End())
+
+	info := pkg.TypesInfo()
+	scope := pkg.Types().Scope().Innermost(stopStart)
+	if scope == nil {
+		return nil, nil
+	}
+
+	// localVar returns the local variable or parameter referred to by
+	// id, if it is in scope at the stopping point.
+	localVar := func(id *ast.Ident) (*types.Var, bool) {
+		v, ok := info.ObjectOf(id).(*types.Var)
+		if !ok || v.IsField() || v.Name() == "_" || v.Parent() == nil || v.Parent() == v.Pkg().Scope() {
+			return nil, false
+		}
+		// Check that the variable is not shadowed at, or
+		// declared after, the stopping point.
+		if _, obj := scope.LookupParent(id.Name, stopStart); obj != v {
+			return nil, false
+		}
+		return v, true
+	}
+
+	var values []protocol.InlineValue
+	addLookup := func(id *ast.Ident) error {
+		rng, err := pgf.NodeRange(id)
+		if err != nil {
+			return err
+		}
+		values = append(values, protocol.InlineValue{Value: protocol.InlineValueVariableLookup{
+			Range:               rng,
+			VariableName:        id.Name,
+			CaseSensitiveLookup: true,
+		}})
+		return nil
+	}
+
+	var inspectErr error
+	filter := []ast.Node{(*ast.Ident)(nil), (*ast.SelectorExpr)(nil)}
+	curFunc.Inspect(filter, func(cur cursor.Cursor, push bool) bool {
+		n := cur.Node()
+		if !push || inspectErr != nil || n.End() <= start || n.Pos() >= end {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			root, ok := fieldSelectionRoot(info, n)
+			if !ok || n.End() > end {
+				return true
+			}
+			if _, ok := localVar(root); !ok {
+				return true
+			}
+			rng, err := pgf.NodeRange(n)
+			if err != nil {
+				inspectErr = err
+				return false
+			}
+			values = append(values, protocol.InlineValue{Value: protocol.InlineValueEvaluatableExpression{
+				Range:      rng,
+				Expression: types.ExprString(n),
+			}})
+			if root.Pos() >= start {
+				inspectErr = addLookup(root)
+			}
+			return false
+
+		case *ast.Ident:
+			if _, ok := localVar(n); ok {
+				inspectErr = addLookup(n)
+			}
+		}
+		return true
+	})
+	if inspectErr != nil {
+		return nil, inspectErr
+	}
+	return values, nil
+}
+
+// fieldSelectionRoot reports whether sel is a (possibly nested)
+// selection of a field from a variable, such as x.f.g, and if so
+// returns the identifier of the variable.
+func fieldSelectionRoot(info *types.Info, sel *ast.SelectorExpr) (*ast.Ident, bool) {
+	var e ast.Expr = sel
+	for {
+		switch x := e.(type) {
+		case *ast.SelectorExpr:
+			if s, ok := info.Selections[x]; !ok || s.Kind() != types.FieldVal {
+				return nil, false
+			}
+			e = x.X
+		case *ast.ParenExpr:
+			e = x.X
+		case *ast.Ident:
+			return x, x.Pos() != token.NoPos
+		default:
+			return nil, false
+		}
+	}
+}
@@ -0,0 +1,592 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the refactor.inline.{variable,constant} code actions.
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/astutil/cursor"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/refactor/inline"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// selectedValue returns the local variable or package-level constant
+// of pkg declared or referenced by the identifier selected by
+// [start, end), or nil if there is none.
+func selectedValue(pkg *cache.Package, pgf *parsego.File, start, end token.Pos) types.Object {
+	cur, ok := pgf.Cursor.FindPos(start, end)
+	if !ok {
+		return nil
+	}
+	id, ok := cur.Node().(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	switch obj := pkg.TypesInfo().ObjectOf(id).(type) {
+	case *types.Var:
+		if !typesinternal.IsPackageLevel(obj) && !obj.IsField() {
+			return obj
+		}
+	case *types.Const:
+		if obj.Pkg() == pkg.Types() && obj.Parent() == pkg.Types().Scope() {
+			return obj
+		}
+	}
+	return nil
+}
+
+// canInlineVariable reports whether the selection is an identifier
+// that declares or refers to a local variable with an initializer.
+func canInlineVariable(pkg *cache.Package, pgf *parsego.File, start, end token.Pos) (*types.Var, bool) {
+	v, ok := selectedValue(pkg, pgf, start, end).(*types.Var)
+	if !ok {
+		return nil, false
+	}
+	_, _, _, err := localVarDecl(pgf, pkg.TypesInfo(), v)
+	return v, err == nil
+}
+
+// localVarDecl returns the statement that declares the local variable
+// v, along with its initializer expression and its explicit type, if
+// any. The statement must be of the form v := init or var v [T] = init.
+func localVarDecl(pgf *parsego.File, info *types.Info, v *types.Var) (cursor.Cursor, ast.Expr, ast.Expr, error) {
+	curID, ok := pgf.Cursor.FindPos(v.Pos(), v.Pos())
+	if !ok || info.Defs[curID.Node().(*ast.Ident)] != v {
+		return cursor.Cursor{}, nil, nil, fmt.Errorf("cannot find declaration of %s", v.Name())
+	}
+	switch decl := curID.Parent().Node().(type) {
+	case *ast.AssignStmt:
+		if decl.Tok == token.DEFINE && len(decl.Lhs) == 1 && len(decl.Rhs) == 1 {
+			return curID.Parent(), decl.Rhs[0], nil, nil
+		}
+	case *ast.ValueSpec:
+		if len(decl.Names) == 1 && len(decl.Values) == 1 {
+			curDecl := curID.Parent().Parent().Parent() // ValueSpec -> GenDecl -> DeclStmt
+			if gen := curID.Parent().Parent().Node().(*ast.GenDecl); len(gen.Specs) == 1 {
+				if _, ok := curDecl.Node().(*ast.DeclStmt); ok {
+					return curDecl, decl.Values[0], decl.Type, nil
+				}
+			}
+		}
+	}
+	return cursor.Cursor{}, nil, nil, fmt.Errorf("%s is not declared with a single initializer", v.Name())
+}
+
+// inlineVariable computes a fix that replaces each use of the local
+// variable selected by [start, end) by its initializer expression,
+// and deletes its declaration.
+//
+// The fix preserves the behavior of the program: the variable must
+// not be updated after its declaration, and its initializer must be
+// pure (see [inline.Pure]), treating as pure only the local variables
+// that are not updated after the declaration, by function literals
+// that do not enclose it, or through their address. The initializer
+// must not allocate if the variable has several uses, and the
+// symbols to which it refers must not be shadowed at any use.
+func inlineVariable(pkg *cache.Package, pgf *parsego.File, start, end token.Pos) (*token.FileSet, *analysis.SuggestedFix, error) {
+	info := pkg.TypesInfo()
+	v, ok := selectedValue(pkg, pgf, start, end).(*types.Var)
+	if !ok {
+		return nil, nil, errors.New("selection is not a local variable")
+	}
+	curDecl, init, typ, err := localVarDecl(pgf, info, v)
+	if err != nil {
+		return nil, nil, err
+	}
+	line := func(pos token.Pos) int { return safetoken.Line(pgf.Tok, pos) }
+
+	// Find the outermost function enclosing the declaration, and the
+	// innermost one, which is the one that declares v.
+	funcs := slices.Collect(curDecl.Ancestors((*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)))
+	curFunc, curOuter := funcs[0], funcs[len(funcs)-1]
+
+	// Find the uses of v, and the local variables that may be
+	// updated after the declaration of v.
+	var (
+		uses    []*ast.Ident
+		updated = make(map[*types.Var]token.Pos) // position of the first relevant update
+	)
+	for cur := range curOuter.Preorder((*ast.Ident)(nil)) {
+		id := cur.Node().(*ast.Ident)
+		w, ok := info.Uses[id].(*types.Var)
+		if !ok || typesinternal.IsPackageLevel(w) || w.IsField() {
+			continue
+		}
+		if w == v {
+			uses = append(uses, id)
+		}
+		if kind := updateOf(info, cur); kind != 0 {
+			// An update is relevant if it follows the declaration
+			// of v, or it is in a function literal that does not
+			// enclose the declaration and so may be called at any
+			// time, or if it takes the variable's address.
+			var curLit cursor.Cursor
+			for c := range cur.Ancestors((*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)) {
+				curLit = c
+				break
+			}
+			if kind == token.AND || id.Pos() > curDecl.Node().End() ||
+				curLit != curFunc && !slices.Contains(funcs, curLit) {
+				if _, ok := updated[w]; !ok {
+					updated[w] = id.Pos()
+				}
+			}
+		}
+	}
+	if pos, ok := updated[v]; ok {
+		return nil, nil, fmt.Errorf("cannot inline %s: it is updated at line %d", v.Name(), line(pos))
+	}
+	assign1 := func(w *types.Var) bool {
+		_, ok := updated[w]
+		return !ok
+	}
+	if !inline.Pure(info, assign1, init) {
+		return nil, nil, fmt.Errorf("cannot inline %s: its initializer may have effects or depend on a variable that is updated", v.Name())
+	}
+	if len(uses) > 1 && inline.Allocates(info, init) {
+		return nil, nil, fmt.Errorf("cannot inline %s: its initializer allocates a variable, so it cannot be evaluated at each of its %d uses", v.Name(), len(uses))
+	}
+
+	// Compute the replacement for each use.
+	text, err := replacementText(pgf, init)
+	if err != nil {
+		return nil, nil, err
+	}
+	// An explicit type must be preserved by a conversion if it differs
+	// from that of the initializer, or if the initializer is a constant,
+	// whose recorded type is that of v even if it is untyped.
+	isConst := info.Types[init].Value != nil
+	if typ != nil && (isConst || !types.Identical(info.TypeOf(init), v.Type())) {
+		typText, err := replacementText(pgf, typ)
+		if err != nil {
+			return nil, nil, err
+		}
+		text = typText + "(" + text + ")"
+	}
+	repl, err := parser.ParseExpr(text)
+	if err != nil {
+		return nil, nil, err
+	}
+	var edits []analysis.TextEdit
+	for _, id := range uses {
+		if err := checkFreeSymbols(info, pgf, init, id.Pos()); err != nil {
+			return nil, nil, fmt.Errorf("cannot inline %s at line %d: %v", v.Name(), line(id.Pos()), err)
+		}
+		path, _ := astutil.PathEnclosingInterval(pgf.File, id.Pos(), id.End())
+		if isConst {
+			if _, ok := path[1].(*ast.CaseClause); ok {
+				return nil, nil, fmt.Errorf("cannot inline %s: its constant value would be a case value at line %d", v.Name(), line(id.Pos()))
+			}
+		}
+		newText := text
+		if inline.NeedsParens(path, id, repl) {
+			newText = "(" + text + ")"
+		}
+		edits = append(edits, analysis.TextEdit{Pos: id.Pos(), End: id.End(), NewText: []byte(newText)})
+	}
+
+	// A constant initializer makes constant the operations of which
+	// v was an operand, which may then fail to compile, for example
+	// due to overflow or division by zero. Check each expression
+	// enclosing a use by type-checking it after the replacement.
+	if isConst {
+		for _, id := range uses {
+			if err := checkReplacement(pkg, pgf, id, edits); err != nil {
+				return nil, nil, fmt.Errorf("cannot inline %s at line %d: %v", v.Name(), line(id.Pos()), err)
+			}
+		}
+	}
+
+	del, err := deleteDecl(pgf, curDecl)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pkg.FileSet(), &analysis.SuggestedFix{
+		Message:   fmt.Sprintf("inline variable %s", v.Name()),
+		TextEdits: append(edits, del),
+	}, nil
+}
+
+// updateOf reports how the variable referenced by the identifier at
+// cur is updated: by an assignment (token.ASSIGN), an increment or
+// decrement (token.INC), by taking its address (token.AND), either
+// explicitly or implicitly in a call of a pointer method; or 0 if
+// the reference does not update the variable.
+func updateOf(info *types.Info, cur cursor.Cursor) token.Token {
+	// Find the largest expression that denotes
+	// the same variable or a part of it.
+	for {
+		parent := cur.Parent().Node()
+		switch parent := parent.(type) {
+		case *ast.ParenExpr:
+			cur = cur.Parent()
+			continue
+		case *ast.SelectorExpr:
+			if seln, ok := info.Selections[parent]; ok && parent.X == cur.Node() {
+				if seln.Kind() == types.MethodVal {
+					if _, ok := seln.Obj().Type().(*types.Signature).Recv().Type().(*types.Pointer); ok && !seln.Indirect() {
+						if _, ok := info.TypeOf(parent.X).Underlying().(*types.Pointer); !ok {
+							return token.AND // implicit &x in x.f()
+						}
+					}
+				} else if !seln.Indirect() {
+					cur = cur.Parent() // x.f is part of x
+					continue
+				}
+			}
+		case *ast.IndexExpr:
+			if parent.X == cur.Node() {
+				if _, ok := info.TypeOf(parent.X).Underlying().(*types.Array); ok {
+					cur = cur.Parent() // x[i] is part of x
+					continue
+				}
+			}
+		}
+		break
+	}
+	switch parent := cur.Parent().Node().(type) {
+	case *ast.AssignStmt:
+		if slices.Contains(parent.Lhs, cur.Node().(ast.Expr)) {
+			return token.ASSIGN
+		}
+	case *ast.RangeStmt:
+		if parent.Tok == token.ASSIGN && (parent.Key == cur.Node() || parent.Value == cur.Node()) {
+			return token.ASSIGN
+		}
+	case *ast.IncDecStmt:
+		return token.INC
+	case *ast.UnaryExpr:
+		if parent.Op == token.AND {
+			return token.AND
+		}
+	}
+	return 0
+}
+
+// checkFreeSymbols returns an error if a symbol referenced by the
+// expression e would refer to a different symbol at position pos.
+func checkFreeSymbols(info *types.Info, pgf *parsego.File, e ast.Expr, pos token.Pos) error {
+	scope := info.Scopes[pgf.File].Innermost(pos)
+	var err error
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			ast.Inspect(sel.X, visit) // sel.Sel is a field, method, or qualified identifier
+			return false
+		}
+		id, ok := n.(*ast.Ident)
+		if !ok || err != nil {
+			return err == nil
+		}
+		obj := info.Uses[id]
+		if obj == nil || obj.Parent() == nil || e.Pos() <= obj.Pos() && obj.Pos() < e.End() {
+			return true // field, method, or symbol declared within e
+		}
+		_, obj2 := scope.LookupParent(id.Name, pos)
+		if pkgname, ok := obj.(*types.PkgName); ok {
+			if pkgname2, ok := obj2.(*types.PkgName); ok && pkgname2.Imported() == pkgname.Imported() {
+				return true
+			}
+			err = fmt.Errorf("package %s is not imported as %s", pkgname.Imported().Path(), id.Name)
+		} else if obj2 != obj {
+			err = fmt.Errorf("%s refers to a different symbol", id.Name)
+		}
+		return true
+	}
+	ast.Inspect(e, visit)
+	return err
+}
+
+// checkReplacement type-checks the largest expression enclosing the
+// use id after applying the edits within it.
+func checkReplacement(pkg *cache.Package, pgf *parsego.File, id *ast.Ident, edits []analysis.TextEdit) error {
+	cur, _ := pgf.Cursor.FindNode(id)
+	for {
+		if _, ok := cur.Parent().Node().(ast.Expr); !ok {
+			break
+		}
+		cur = cur.Parent()
+	}
+	outer := cur.Node().(ast.Expr)
+	if outer == id {
+		return nil
+	}
+	start, end, err := safetoken.Offsets(pgf.Tok, outer.Pos(), outer.End())
+	if err != nil {
+		return err
+	}
+	var diffs []diff.Edit
+	for _, e := range edits {
+		if outer.Pos() <= e.Pos && e.End <= outer.End() {
+			diffs = append(diffs, diff.Edit{
+				Start: int(e.Pos - outer.Pos()),
+				End:   int(e.End - outer.Pos()),
+				New:   string(e.NewText),
+			})
+		}
+	}
+	text, err := diff.Apply(string(pgf.Src[start:end]), diffs)
+	if err != nil {
+		return err
+	}
+	expr, err := parser.ParseExpr(text)
+	if err != nil {
+		return err
+	}
+	if err := types.CheckExpr(token.NewFileSet(), pkg.Types(), outer.Pos(), expr, nil); err != nil {
+		if terr, ok := err.(types.Error); ok {
+			return fmt.Errorf("%s would be invalid: %s", text, terr.Msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// replacementText returns the source text of the expression e in
+// pgf, less any comments.
+func replacementText(pgf *parsego.File, e ast.Expr) (string, error) {
+	start, end, err := safetoken.Offsets(pgf.Tok, e.Pos(), e.End())
+	if err != nil {
+		return "", err
+	}
+	text := string(pgf.Src[start:end])
+	for _, cg := range pgf.File.Comments {
+		if e.Pos() <= cg.Pos() && cg.End() <= e.End() {
+			return "", errors.New("cannot inline an expression containing comments")
+		}
+	}
+	switch e.(type) {
+	case *ast.StarExpr, *ast.FuncType, *ast.ChanType:
+		if strings.HasPrefix(text, "*") || strings.HasPrefix(text, "func") || strings.HasPrefix(text, "<-") {
+			text = "(" + text + ")" // a type that must be parenthesized in a conversion
+		}
+	}
+	return text, nil
+}
+
+// deleteDecl returns an edit that deletes the declaration at cur,
+// which is a statement in a block or the initialization statement of
+// an if or switch statement.
+func deleteDecl(pgf *parsego.File, cur cursor.Cursor) (analysis.TextEdit, error) {
+	stmt := cur.Node()
+	switch parent := cur.Parent().Node().(type) {
+	case *ast.IfStmt:
+		return analysis.TextEdit{Pos: stmt.Pos(), End: parent.Cond.Pos()}, nil
+	case *ast.SwitchStmt:
+		if parent.Tag != nil {
+			return analysis.TextEdit{Pos: stmt.Pos(), End: parent.Tag.Pos()}, nil
+		}
+		return analysis.TextEdit{Pos: stmt.Pos(), End: parent.Body.Lbrace}, nil
+	case *ast.TypeSwitchStmt:
+		return analysis.TextEdit{Pos: stmt.Pos(), End: parent.Assign.Pos()}, nil
+	case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+		return deleteLines(pgf, stmt.Pos(), stmt.End())
+	}
+	return analysis.TextEdit{}, fmt.Errorf("cannot delete the declaration at line %d", safetoken.Line(pgf.Tok, stmt.Pos()))
+}
+
+// deleteLines returns an edit that deletes [start, end), extended to
+// whole lines (and a blank line, if the deleted lines were surrounded
+// by them) if the range is preceded and followed on its lines only by
+// spaces, or otherwise by a following semicolon.
+func deleteLines(pgf *parsego.File, start, end token.Pos) (analysis.TextEdit, error) {
+	startOffset, endOffset, err := safetoken.Offsets(pgf.Tok, start, end)
+	if err != nil {
+		return analysis.TextEdit{}, err
+	}
+	lineStart := bytes.LastIndexByte(pgf.Src[:startOffset], '\n') + 1
+	rest := pgf.Src[endOffset:]
+	if eol := bytes.IndexByte(rest, '\n'); eol >= 0 &&
+		len(bytes.TrimSpace(pgf.Src[lineStart:startOffset])) == 0 &&
+		len(bytes.TrimSpace(rest[:eol])) == 0 {
+		startOffset, endOffset = lineStart, endOffset+eol+1
+		// Avoid leaving two consecutive blank lines.
+		if bytes.HasSuffix(pgf.Src[:startOffset], []byte("\n\n")) && bytes.HasPrefix(pgf.Src[endOffset:], []byte("\n")) {
+			endOffset++
+		}
+	} else if trimmed := bytes.TrimLeft(rest, " \t"); len(trimmed) > 0 && trimmed[0] == ';' {
+		endOffset += len(rest) - len(trimmed) + 1
+		endOffset += len(pgf.Src[endOffset:]) - len(bytes.TrimLeft(pgf.Src[endOffset:], " \t"))
+	}
+	return analysis.TextEdit{
+		Pos: pgf.Tok.Pos(startOffset),
+		End: pgf.Tok.Pos(endOffset),
+	}, nil
+}
+
+// inlineConstant computes a fix that replaces each use, within its
+// package, of the package-level constant selected by [start, end) by
+// its value, and deletes its declaration if it is unexported.
+//
+// The replacement is the constant's initializer expression, if it has
+// one that does not refer to iota, and otherwise a literal of its
+// value. Either is converted to the type of the constant, if
+// necessary. The symbols to which the initializer refers must not be
+// shadowed at any use.
+func inlineConstant(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, start, end token.Pos) (*token.FileSet, *analysis.SuggestedFix, error) {
+	c, ok := selectedValue(pkg, pgf, start, end).(*types.Const)
+	if !ok {
+		return nil, nil, errors.New("selection is not a package-level constant")
+	}
+
+	// Use the widest package, so that the uses in tests are inlined too.
+	pkg, _, err := WidestPackageForFile(ctx, snapshot, pgf.URI)
+	if err != nil {
+		return nil, nil, err
+	}
+	info := pkg.TypesInfo()
+	c, ok = pkg.Types().Scope().Lookup(c.Name()).(*types.Const)
+	if !ok {
+		return nil, nil, fmt.Errorf("cannot find constant %s", c.Name())
+	}
+	declPGF, ok := enclosingFile(pkg, c.Pos())
+	if !ok {
+		return nil, nil, fmt.Errorf("cannot find declaration of %s", c.Name())
+	}
+	curID, ok := declPGF.Cursor.FindPos(c.Pos(), c.Pos())
+	if !ok {
+		return nil, nil, fmt.Errorf("cannot find declaration of %s", c.Name())
+	}
+	spec := curID.Parent().Node().(*ast.ValueSpec)
+	gen := curID.Parent().Parent().Node().(*ast.GenDecl)
+	index := slices.Index(spec.Names, curID.Node().(*ast.Ident))
+
+	// Choose the replacement: the initializer, or a literal.
+	var (
+		init     ast.Expr // initializer, or nil for a literal
+		initText string
+		initType types.Type
+	)
+	if len(spec.Values) == len(spec.Names) && !usesIota(info, spec.Values[index]) {
+		init = spec.Values[index]
+		if initText, err = replacementText(declPGF, init); err != nil {
+			return nil, nil, err
+		}
+		initType = info.TypeOf(init)
+	} else {
+		initText = FormatNode(token.NewFileSet(), inline.ConstantLiteral(c.Val()))
+		initType = types.Typ[map[constant.Kind]types.BasicKind{
+			constant.Bool:    types.UntypedBool,
+			constant.String:  types.UntypedString,
+			constant.Int:     types.UntypedInt,
+			constant.Float:   types.UntypedFloat,
+			constant.Complex: types.UntypedComplex,
+		}[c.Val().Kind()]]
+		if basic, ok := c.Type().(*types.Basic); ok && basic.Info()&types.IsUntyped != 0 && basic != initType {
+			return nil, nil, fmt.Errorf("cannot inline %s: its value cannot be expressed as a literal of type %s", c.Name(), c.Type())
+		}
+	}
+
+	var edits []analysis.TextEdit
+	for _, upgf := range pkg.CompiledGoFiles() {
+		for cur := range upgf.Cursor.Preorder((*ast.Ident)(nil)) {
+			id := cur.Node().(*ast.Ident)
+			if info.Uses[id] != c {
+				continue
+			}
+			posn := safetoken.StartPosition(pkg.FileSet(), id.Pos())
+			if init != nil {
+				if err := checkFreeSymbols(info, upgf, init, id.Pos()); err != nil {
+					return nil, nil, fmt.Errorf("cannot inline %s at %s:%d: %v", c.Name(), filepath.Base(posn.Filename), posn.Line, err)
+				}
+			}
+			text := initText
+			if spec.Type != nil || !types.Identical(initType, c.Type()) {
+				// Convert to the constant's type.
+				var qualErr error
+				qual := func(p *types.Package) string {
+					if p == pkg.Types() {
+						return ""
+					}
+					for _, spec := range upgf.File.Imports {
+						if pkgname := info.PkgNameOf(spec); pkgname != nil && pkgname.Imported() == p {
+							return pkgname.Name()
+						}
+					}
+					qualErr = fmt.Errorf("package %s is not imported", p.Path())
+					return p.Name()
+				}
+				text = types.TypeString(c.Type(), qual) + "(" + text + ")"
+				if qualErr != nil {
+					return nil, nil, fmt.Errorf("cannot inline %s at %s:%d: %v", c.Name(), filepath.Base(posn.Filename), posn.Line, qualErr)
+				}
+			}
+			repl, err := parser.ParseExpr(text)
+			if err != nil {
+				return nil, nil, err
+			}
+			path, _ := astutil.PathEnclosingInterval(upgf.File, id.Pos(), id.End())
+			if inline.NeedsParens(path, id, repl) {
+				text = "(" + text + ")"
+			}
+			edits = append(edits, analysis.TextEdit{Pos: id.Pos(), End: id.End(), NewText: []byte(text)})
+		}
+	}
+
+	// Delete the declaration of an unexported constant if doing so
+	// does not change the values of the other constants in its group.
+	if !c.Exported() && len(spec.Names) == 1 && init != nil &&
+		!slices.ContainsFunc(gen.Specs, func(s ast.Spec) bool {
+			s1 := s.(*ast.ValueSpec)
+			return len(s1.Values) == 0 || slices.ContainsFunc(s1.Values, func(e ast.Expr) bool { return usesIota(info, e) })
+		}) {
+		var del analysis.TextEdit
+		if len(gen.Specs) == 1 {
+			start := gen.Pos()
+			if gen.Doc != nil {
+				start = gen.Doc.Pos()
+			}
+			del, err = deleteLines(declPGF, start, gen.End())
+		} else {
+			start := spec.Pos()
+			if spec.Doc != nil {
+				start = spec.Doc.Pos()
+			}
+			del, err = deleteLines(declPGF, start, spec.End())
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		edits = append(edits, del)
+	}
+	if len(edits) == 0 {
+		return nil, nil, fmt.Errorf("constant %s has no uses in package %s", c.Name(), pkg.Types().Name())
+	}
+	return pkg.FileSet(), &analysis.SuggestedFix{
+		Message:   fmt.Sprintf("inline constant %s", c.Name()),
+		TextEdits: edits,
+	}, nil
+}
+
+// usesIota reports whether the expression e refers to iota.
+func usesIota(info *types.Info, e ast.Expr) bool {
+	found := false
+	ast.Inspect(e, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && info.Uses[id] == types.Universe.Lookup("iota") {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,255 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/event"
+)
+
+// Word patterns for linked editing ranges, in the JavaScript regular
+// expression syntax used by LSP clients.
+const (
+	// identWordPattern matches a Go identifier. (Without the "u"
+	// flag, which clients do not use, \p{L} is not available, so any
+	// non-ASCII character is accepted.)
+	identWordPattern = `[A-Za-z_\u0080-\uFFFF][A-Za-z0-9_\u0080-\uFFFF]*`
+
+	// tagWordPattern matches the name within a struct tag value.
+	tagWordPattern = `[^\s",]+`
+)
+
+// LinkedEditingRanges returns the ranges of a Go file that should be
+// edited simultaneously with the text at the specified position.
+//
+// At an identifier, these are the occurrences of the same object,
+// provided that it is local to a function, so that all of its
+// references lie within that function: a local variable, parameter or
+// named result, a label, or a type parameter. Editing the identifier
+// is then equivalent to a renaming, though without its safety checks.
+//
+// Within a struct field tag, such as `json:"name" yaml:"name"`, these
+// are the occurrences of the same name in the values of different keys.
+//
+// It returns nil if the position has no linked editing ranges.
+func LinkedEditingRanges(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pp protocol.Position) (*protocol.LinkedEditingRanges, error) {
+	ctx, done := event.Start(ctx, "golang.LinkedEditingRanges")
+	defer done()
+
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, fmt.Errorf("getting package for LinkedEditingRanges: %w", err)
+	}
+	pos, err := pgf.PositionPos(pp)
+	if err != nil {
+		return nil, err
+	}
+	path, _ := astutil.PathEnclosingInterval(pgf.File, pos, pos)
+	if len(path) == 0 {
+		return nil, nil
+	}
+	// As in Highlight, prefer an identifier that ends at the cursor.
+	if _, ok := path[0].(*ast.Ident); !ok {
+		if p, _ := astutil.PathEnclosingInterval(pgf.File, pos-1, pos-1); len(p) > 0 {
+			if _, ok := p[0].(*ast.Ident); ok {
+				path = p
+			}
+		}
+	}
+
+	var (
+		spans   [][2]token.Pos
+		pattern string
+	)
+	switch n := path[0].(type) {
+	case *ast.Ident:
+		spans = linkedIdentSpans(pkg.TypesInfo(), path, n)
+		pattern = identWordPattern
+	case *ast.BasicLit:
+		if len(path) > 1 {
+			if field, ok := path[1].(*ast.Field); ok && field.Tag == n {
+				spans = linkedTagSpans(n, pos)
+				pattern = tagWordPattern
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return nil, nil
+	}
+
+	result := &protocol.LinkedEditingRanges{WordPattern: pattern}
+	for _, span := range spans {
+		rng, err := pgf.PosRange(span[0], span[1])
+		if err != nil {
+			return nil, err
+		}
+		result.Ranges = append(result.Ranges, rng)
+	}
+	return result, nil
+}
+
+// linkedIdentSpans returns the spans of all identifiers referring to
+// the same function-local object as id, whose enclosing path is
+// given. It returns nil if the object is not function-local.
+func linkedIdentSpans(info *types.Info, path []ast.Node, id *ast.Ident) [][2]token.Pos {
+	obj := info.ObjectOf(id)
+	if obj == nil || obj.Pkg() == nil || !isFunctionLocal(obj) {
+		return nil
+	}
+	// The implicit objects of a type switch (one per case clause)
+	// are declared by a single identifier, so they cannot be
+	// edited independently. Decline them.
+	if v, ok := obj.(*types.Var); ok {
+		for _, implicit := range info.Implicits {
+			if implicit == v {
+				return nil
+			}
+		}
+	}
+
+	// All references to the object lie within the outermost
+	// function enclosing its declaration.
+	var fn ast.Node
+	for _, n := range path {
+		switch n.(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			fn = n
+		}
+	}
+	if fn == nil || !(fn.Pos() <= obj.Pos() && obj.Pos() < fn.End()) {
+		return nil
+	}
+
+	var spans [][2]token.Pos
+	ast.Inspect(fn, func(n ast.Node) bool {
+		if n, ok := n.(*ast.Ident); ok && n.Name == id.Name && info.ObjectOf(n) == obj {
+			spans = append(spans, [2]token.Pos{n.Pos(), n.End()})
+		}
+		return true
+	})
+	return spans
+}
+
+// isFunctionLocal reports whether the object is declared within a
+// function (or, for a type parameter, within a declaration), so that
+// all references to it lie within the same declaration.
+func isFunctionLocal(obj types.Object) bool {
+	switch obj := obj.(type) {
+	case *types.Label:
+		return true
+	case *types.Var:
+		if obj.IsField() {
+			return false
+		}
+	case *types.TypeName:
+		// Of the local type names, only type parameters qualify:
+		// an embedded field implicitly shares the name of its type,
+		// so renaming a local type may also rename selections
+		// x.T of the field, which are not references to the type.
+		_, ok := obj.Type().(*types.TypeParam)
+		return ok
+	case *types.Const:
+		// local constants are checked below
+	default:
+		return false // e.g. functions, methods, package names
+	}
+	parent := obj.Parent()
+	return parent != nil && parent != obj.Pkg().Scope() && parent != types.Universe
+}
+
+// linkedTagSpans returns the spans, within the raw string literal of a
+// struct field tag, of each occurrence of the name (the part before
+// any comma) in the value at pos, provided that it occurs in the
+// values of at least two keys.
+func linkedTagSpans(lit *ast.BasicLit, pos token.Pos) [][2]token.Pos {
+	if !strings.HasPrefix(lit.Value, "`") {
+		return nil // interpreted string literals may contain escapes
+	}
+	names := tagValueNames(lit.Value[1 : len(lit.Value)-1])
+	start := lit.Pos() + 1
+
+	var name string
+	for _, n := range names {
+		if start+token.Pos(n[0]) <= pos && pos <= start+token.Pos(n[1]) {
+			name = lit.Value[1+n[0] : 1+n[1]]
+		}
+	}
+	if name == "" {
+		return nil
+	}
+	var spans [][2]token.Pos
+	for _, n := range names {
+		if lit.Value[1+n[0]:1+n[1]] == name {
+			spans = append(spans, [2]token.Pos{start + token.Pos(n[0]), start + token.Pos(n[1])})
+		}
+	}
+	if len(spans) < 2 {
+		return nil
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	return spans
+}
+
+// tagValueNames returns the offsets of the names within the values of
+// a conventional struct tag, such as `json:"name,omitempty" xml:"name"`.
+// Parsing follows reflect.StructTag.Lookup, but stops at the first
+// malformed key or escaped value.
+func tagValueNames(tag string) [][2]int {
+	var names [][2]int
+	offset := 0
+	for tag != "" {
+		// Skip leading space.
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag, offset = tag[i:], offset+i
+		if tag == "" {
+			break
+		}
+
+		// Scan to colon. A space, a quote or a control character is a syntax error.
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		tag, offset = tag[i+1:], offset+i+1
+
+		// Scan quoted string to find value.
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				return names // escapes would invalidate offsets
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		value := tag[1:i]
+		if comma := strings.IndexByte(value, ','); comma >= 0 {
+			value = value[:comma]
+		}
+		if value != "" && value != "-" {
+			names = append(names, [2]int{offset + 1, offset + 1 + len(value)})
+		}
+		tag, offset = tag[i+1:], offset+i+1
+	}
+	return names
+}
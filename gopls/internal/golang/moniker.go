@@ -0,0 +1,232 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"golang.org/x/tools/go/types/objectpath"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/bug"
+	"golang.org/x/tools/gopls/internal/util/pathutil"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/event"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// monikerScheme is the scheme of the monikers reported by gopls.
+const monikerScheme = "go"
+
+// Monikers returns the monikers of the symbols referenced or declared
+// at the specified position, for use in linking symbols across
+// repositories.
+//
+// The identifier of the moniker of a symbol that can be referenced
+// from outside its function has the form
+//
+//	module:package:objectpath
+//
+// where module is the path of the module containing the symbol's
+// package ("std" for the standard library), package is the package
+// path, and objectpath is the [objectpath] encoding of the symbol
+// relative to its package, such as "T.M0" for the first method of type T.
+// These monikers are globally unique, and have kind "export" if the
+// symbol is exported from the current package, "import" if it belongs
+// to another package, and "local" otherwise.
+//
+// An imported package name has a moniker of the form module:package.
+//
+// The path of a package that belongs to no module, such as one in
+// GOPATH mode, is not globally unique, so the monikers of its symbols
+// omit the module part, and are unique only within the document.
+//
+// Symbols local to a function, such as local variables, have monikers
+// of kind "local" that are unique only within the document.
+func Monikers(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pp protocol.Position) ([]protocol.Moniker, error) {
+	ctx, done := event.Start(ctx, "golang.Monikers")
+	defer done()
+
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, fmt.Errorf("getting package for Monikers: %w", err)
+	}
+	pos, err := pgf.PositionPos(pp)
+	if err != nil {
+		return nil, err
+	}
+	targets, _, err := objectsAt(pkg.TypesInfo(), pgf.File, pos)
+	if err != nil {
+		return nil, nil // no symbol at this position
+	}
+
+	// modulePath returns the path of the module containing the
+	// package with the specified path, "std" for the standard
+	// library, or "" if the package belongs to no module.
+	goroot := snapshot.View().Folder().Env.GOROOT
+	modulePath := func(path PackagePath) (string, error) {
+		mp := pkg.Metadata()
+		if path != mp.PkgPath {
+			mp = nil
+			if id, ok := pkg.Metadata().DepsByPkgPath[path]; ok {
+				mp = snapshot.Metadata(id)
+			} else {
+				// Not a direct dependency, e.g. the package of a
+				// method of a type from an indirect dependency.
+				allMetadata, err := snapshot.AllMetadata(ctx)
+				if err != nil {
+					return "", err
+				}
+				for _, m := range allMetadata {
+					if m.PkgPath == path {
+						mp = m
+						break
+					}
+				}
+			}
+		}
+		switch {
+		case mp == nil:
+			return "", nil
+		case mp.Module != nil:
+			return mp.Module.Path, nil
+		case isStandard(goroot, mp):
+			return "std", nil
+		default:
+			return "", nil
+		}
+	}
+
+	// global returns the moniker identifier and uniqueness of a
+	// symbol with the specified identifier within its module.
+	global := func(mod, id string) (string, protocol.UniquenessLevel) {
+		if mod == "" {
+			return id, protocol.Document
+		}
+		return mod + ":" + id, protocol.Global
+	}
+
+	kind := func(k protocol.MonikerKind) *protocol.MonikerKind { return &k }
+
+	var monikers []protocol.Moniker
+	seen := make(map[string]bool)
+	for obj := range targets {
+		var moniker protocol.Moniker
+		switch obj := obj.(type) {
+		case *types.PkgName:
+			path := PackagePath(obj.Imported().Path())
+			mod, err := modulePath(path)
+			if err != nil {
+				return nil, err
+			}
+			moniker = protocol.Moniker{Kind: kind(protocol.Import)}
+			moniker.Identifier, moniker.Unique = global(mod, string(path))
+
+		default:
+			if obj.Pkg() == nil {
+				// A built-in such as len or error.
+				moniker = protocol.Moniker{
+					Identifier: fmt.Sprintf("std:builtin:%s", obj.Name()),
+					Unique:     protocol.Global,
+					Kind:       kind(protocol.Import),
+				}
+				break
+			}
+
+			opath, ok := monikerObjectPath(obj)
+			if !ok {
+				// A local symbol: distinguish it from others of the
+				// same name in the document by its declaration,
+				// which may be in another file of the package.
+				tf := pkg.FileSet().File(obj.Pos())
+				if tf == nil {
+					return nil, bug.Errorf("no file for local symbol %v", obj)
+				}
+				offset, err := safetoken.Offset(tf, obj.Pos())
+				if err != nil {
+					return nil, err
+				}
+				moniker = protocol.Moniker{
+					Identifier: fmt.Sprintf("%s:%s@%d", obj.Pkg().Path(), obj.Name(), offset),
+					Unique:     protocol.Document,
+					Kind:       kind(protocol.Local),
+				}
+				break
+			}
+
+			path := PackagePath(obj.Pkg().Path())
+			mod, err := modulePath(path)
+			if err != nil {
+				return nil, err
+			}
+			moniker.Identifier, moniker.Unique = global(mod, fmt.Sprintf("%s:%s", path, opath))
+			switch {
+			case obj.Pkg() != pkg.Types():
+				moniker.Kind = kind(protocol.Import)
+			case obj.Exported():
+				moniker.Kind = kind(protocol.Export)
+			default:
+				moniker.Kind = kind(protocol.Local)
+			}
+		}
+		moniker.Scheme = monikerScheme
+		if !seen[moniker.Identifier] {
+			seen[moniker.Identifier] = true
+			monikers = append(monikers, moniker)
+		}
+	}
+	sort.Slice(monikers, func(i, j int) bool { return monikers[i].Identifier < monikers[j].Identifier })
+	return monikers, nil
+}
+
+// monikerObjectPath returns the object path of the symbol obj, or
+// false if it has none, such as when it is local to a function, or
+// is an init function or an object named _.
+func monikerObjectPath(obj types.Object) (objectpath.Path, bool) {
+	// objectpath.For requires the origin of a generic function or
+	// field, not an instantiation.
+	switch o := obj.(type) {
+	case *types.Func:
+		obj = o.Origin()
+	case *types.Var:
+		// Parameters and results are reachable through the type of
+		// their function, but can be referenced only within it.
+		if !o.IsField() && !typesinternal.IsPackageLevel(o) {
+			return "", false
+		}
+		obj = o.Origin()
+	case *types.TypeName:
+		if _, ok := types.Unalias(o.Type()).(*types.TypeParam); ok {
+			return "", false
+		}
+	}
+	path, err := objectpath.For(obj)
+	if err != nil {
+		// objectpath.For returns no path for unexported
+		// package-level objects other than types, but those in
+		// the package scope are identified by their names.
+		// (Init functions and objects named _ are not in scope.)
+		if obj.Pkg() != nil && obj.Pkg().Scope().Lookup(obj.Name()) == obj {
+			return objectpath.Path(obj.Name()), true
+		}
+		return "", false
+	}
+	return path, true
+}
+
+// isStandard reports whether the package belongs to the standard
+// library, that is, whether its files are in GOROOT.
+func isStandard(goroot string, mp *metadata.Package) bool {
+	files := slices.Concat(mp.CompiledGoFiles, mp.GoFiles) // unsafe has no compiled files
+	return goroot != "" && len(files) > 0 &&
+		pathutil.InDir(filepath.Join(goroot, "src"), files[0].Path())
+}
@@ -0,0 +1,685 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the "Move declarations to package" refactoring.
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// MoveToPackage moves the top-level declarations selected by rng in
+// file fh to a new file in the package in directory dest, creating
+// the package if necessary, and updates the references to them
+// throughout the workspace.
+//
+// Unexported package-level symbols referenced across the new package
+// boundary are exported by renaming them. MoveToPackage fails if the
+// move would create an import cycle or an import of an inaccessible
+// internal package, if the moved and remaining declarations would
+// refer to each other's unexported fields or methods, or if a method
+// would be separated from its receiver type.
+func MoveToPackage(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rng protocol.Range, dest protocol.DocumentURI) ([]protocol.DocumentChange, error) {
+	// The widest package includes the in-package tests, whose
+	// references to the moved declarations must be updated too.
+	pkg, pgf, err := WidestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, err
+	}
+	if perrors, terrors := pkg.ParseErrors(), pkg.TypeErrors(); len(perrors) > 0 || len(terrors) > 0 {
+		return nil, fmt.Errorf("cannot move declarations from package %s, which has errors", pkg.Types().Name())
+	}
+	start, end, err := pgf.RangePos(rng)
+	if err != nil {
+		return nil, err
+	}
+	start, end, firstSymbol, ok := selectedToplevelDecls(pgf, start, end)
+	if !ok {
+		return nil, errors.New("selection does not consist of complete top-level declarations")
+	}
+	pgf.CheckPos(start) // #70553
+	for _, spec := range pgf.File.Imports {
+		if metadata.UnquoteImportPath(spec) == "C" {
+			return nil, errors.New("cannot move declarations from a file that uses cgo")
+		}
+	}
+
+	var (
+		info      = pkg.TypesInfo()
+		srcTypes  = pkg.Types()
+		srcPath   = srcTypes.Path()
+		srcName   = srcTypes.Name()
+		srcScope  = srcTypes.Scope()
+		posn      = func(pos token.Pos) token.Position { return safetoken.StartPosition(pkg.FileSet(), pos) }
+		inRegion  = func(pos token.Pos) bool { return start <= pos && pos < end }
+		moved     = make(map[types.Object]bool) // package-level objects declared in the region
+		movedObjs []types.Object                // moved, in declaration order
+	)
+	for id, obj := range info.Defs {
+		if obj != nil && inRegion(id.Pos()) && obj.Parent() == srcScope {
+			moved[obj] = true
+			movedObjs = append(movedObjs, obj)
+		}
+	}
+	slices.SortFunc(movedObjs, func(x, y types.Object) int { return cmp.Compare(x.Pos(), y.Pos()) })
+
+	// Methods and their receiver types must be moved together.
+	for _, decl := range pgf.File.Decls {
+		if decl, ok := decl.(*ast.FuncDecl); ok && decl.Recv != nil && inRegion(decl.Pos()) {
+			fn := info.Defs[decl.Name].(*types.Func)
+			if _, recv := typesinternal.ReceiverNamed(fn.Signature().Recv()); recv != nil && !moved[recv.Obj()] {
+				return nil, fmt.Errorf("cannot move method %s without its receiver type %s", fn.Name(), recv.Obj().Name())
+			}
+		}
+	}
+	for _, obj := range movedObjs {
+		if tname, ok := obj.(*types.TypeName); ok && !tname.IsAlias() {
+			if named, ok := tname.Type().(*types.Named); ok {
+				for m := range named.Methods() {
+					if !inRegion(m.Pos()) {
+						return nil, fmt.Errorf("cannot move type %s without its method %s", tname.Name(), m.Name())
+					}
+				}
+			}
+		}
+	}
+
+	// Find or create the destination package.
+	dir := dest.Path()
+	if dir == pgf.URI.DirPath() {
+		return nil, fmt.Errorf("declarations are already in directory %s", dir)
+	}
+	g := snapshot.MetadataGraph()
+	var dstMeta *metadata.Package
+	for _, id := range slices.Sorted(maps.Keys(g.Packages)) {
+		mp := g.Packages[id]
+		if mp.ForTest == "" && !strings.HasSuffix(string(mp.Name), "_test") &&
+			len(mp.CompiledGoFiles) > 0 && mp.CompiledGoFiles[0].DirPath() == dir {
+			dstMeta = mp
+			break
+		}
+	}
+	var (
+		dstPath string
+		dstName string
+		dstPkg  *cache.Package // nil for a new package
+	)
+	if dstMeta != nil {
+		if !snapshot.IsWorkspacePackage(dstMeta.ID) {
+			return nil, fmt.Errorf("package %s is not in the workspace", dstMeta.PkgPath)
+		}
+		pkgs, err := snapshot.TypeCheck(ctx, dstMeta.ID)
+		if err != nil {
+			return nil, err
+		}
+		dstPkg = pkgs[0]
+		dstPath, dstName = dstPkg.Types().Path(), dstPkg.Types().Name()
+	} else {
+		entries, _ := os.ReadDir(dir) // missing directory is ok
+		for _, e := range entries {
+			if strings.HasSuffix(e.Name(), ".go") {
+				return nil, fmt.Errorf("directory %s contains Go files that do not belong to a workspace package", dir)
+			}
+		}
+		mod := pkg.Metadata().Module
+		if mod == nil {
+			return nil, fmt.Errorf("package %s does not belong to a module", srcPath)
+		}
+		rel, err := filepath.Rel(mod.Dir, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("directory %s is not within module %s", dir, mod.Path)
+		}
+		dstPath = path.Join(mod.Path, filepath.ToSlash(rel))
+		dstName = filepath.Base(dir)
+		if !token.IsIdentifier(dstName) || dstName == "_" {
+			return nil, fmt.Errorf("cannot derive a package name from directory %s", dir)
+		}
+	}
+	if dstName == "main" {
+		return nil, errors.New("cannot move declarations to package main")
+	}
+
+	// Unexported objects referenced across the boundary are exported.
+	exported := func(obj types.Object) (string, error) {
+		r, size := utf8.DecodeRuneInString(obj.Name())
+		if !unicode.IsLower(r) {
+			return "", fmt.Errorf("cannot export %s", obj.Name())
+		}
+		return string(unicode.ToUpper(r)) + obj.Name()[size:], nil
+	}
+	toExport := make(map[types.Object]string) // unexported objects referenced across the boundary
+	newName := func(obj types.Object) string {
+		if name, ok := toExport[obj]; ok {
+			return name
+		}
+		return obj.Name()
+	}
+
+	// Classify the references that cross the boundary.
+	type ref struct {
+		pgf *parsego.File
+		id  *ast.Ident
+		obj types.Object
+	}
+	var (
+		outgoing []ref        // references from moved code to remaining package-level objects
+		incoming []ref        // references from remaining code to moved objects
+		dstQuals []*ast.Ident // qualifiers "dst" of references dst.Z in moved code
+		srcFiles = make(map[protocol.DocumentURI]*parsego.File)
+	)
+	for _, pgf := range pkg.CompiledGoFiles() {
+		srcFiles[pgf.URI] = pgf
+		for cur := range pgf.Cursor.Preorder((*ast.Ident)(nil)) {
+			id := cur.Node().(*ast.Ident)
+			obj := info.Uses[id]
+			if pkgname, ok := obj.(*types.PkgName); ok {
+				if inRegion(id.Pos()) && pkgname.Imported().Path() == dstPath {
+					dstQuals = append(dstQuals, id)
+				}
+				continue
+			}
+			if obj == nil || obj.Pkg() != srcTypes || inRegion(id.Pos()) == inRegion(obj.Pos()) {
+				continue
+			}
+			if obj.Parent() != srcScope {
+				if !obj.Exported() {
+					return nil, fmt.Errorf("%v: cannot move declarations: reference to unexported %s would cross the package boundary", posn(id.Pos()), obj.Name())
+				}
+				continue
+			}
+			if !obj.Exported() {
+				if _, ok := toExport[obj]; !ok {
+					name, err := exported(obj)
+					if err != nil {
+						return nil, fmt.Errorf("%v: %v", posn(id.Pos()), err)
+					}
+					toExport[obj] = name
+				}
+			}
+			if inRegion(id.Pos()) {
+				outgoing = append(outgoing, ref{pgf, id, obj})
+			} else {
+				incoming = append(incoming, ref{pgf, id, obj})
+			}
+		}
+	}
+	// Check that the new name of each moved object
+	// is not already declared in dst.
+	for _, obj := range movedObjs {
+		name := newName(obj)
+		if dstPkg != nil {
+			if dstPkg.Types().Scope().Lookup(name) != nil {
+				return nil, fmt.Errorf("%s is already declared in package %s", name, dstName)
+			}
+			for _, pgf := range dstPkg.CompiledGoFiles() {
+				if scope := dstPkg.TypesInfo().Scopes[pgf.File]; scope != nil && scope.Lookup(name) != nil {
+					return nil, fmt.Errorf("%s conflicts with an import in %s", name, filepath.Base(pgf.URI.Path()))
+				}
+			}
+		}
+		if len(outgoing) > 0 && name == srcName {
+			return nil, fmt.Errorf("%s conflicts with the name of package %s", name, srcName)
+		}
+	}
+
+	// Export the unexported objects referenced across the boundary.
+	edits := make(map[protocol.DocumentURI][]diff.Edit)
+	for _, obj := range slices.SortedFunc(maps.Keys(toExport), func(x, y types.Object) int { return cmp.Compare(x.Pos(), y.Pos()) }) {
+		renames, _, err := renameObjects(toExport[obj], pkg, obj)
+		if err != nil {
+			return nil, fmt.Errorf("cannot export %s: %v", obj.Name(), err)
+		}
+		for uri, e := range renames {
+			edits[uri] = append(edits[uri], e...)
+		}
+	}
+
+	// imports records the imports to add (by path, with their names)
+	// and delete in each file.
+	type importChanges struct {
+		add    map[string]string
+		delete []*ast.ImportSpec
+	}
+	imports := make(map[protocol.DocumentURI]*importChanges)
+	importsOf := func(uri protocol.DocumentURI) *importChanges {
+		ic, ok := imports[uri]
+		if !ok {
+			ic = &importChanges{add: make(map[string]string)}
+			imports[uri] = ic
+		}
+		return ic
+	}
+	edit := func(pgf *parsego.File, start, end token.Pos, text string) error {
+		e, err := posEdit(pgf.Tok, start, end, text)
+		if err != nil {
+			return err
+		}
+		edits[pgf.URI] = append(edits[pgf.URI], e)
+		return nil
+	}
+
+	// qualifier returns the name by which file pgf of package p may
+	// refer to the package with the specified path, adding an import
+	// if necessary, or an error if that name is shadowed at pos.
+	qualifier := func(p *cache.Package, pgf *parsego.File, pos token.Pos, ipath, name string) (string, error) {
+		var pkgname *types.PkgName
+		for _, spec := range pgf.File.Imports {
+			if metadata.UnquoteImportPath(spec) == metadata.ImportPath(ipath) {
+				if pn := p.TypesInfo().PkgNameOf(spec); pn != nil && pn.Name() != "_" && pn.Name() != "." {
+					pkgname = pn
+					name = pn.Name()
+					break
+				}
+			}
+		}
+		if pkgname == nil {
+			importsOf(pgf.URI).add[ipath] = name
+		}
+		scope := p.TypesInfo().Scopes[pgf.File].Innermost(pos)
+		if _, obj := scope.LookupParent(name, pos); obj != nil && obj != pkgname {
+			return "", fmt.Errorf("%v: cannot refer to package %s: name %s is already in use", posn(pos), ipath, name)
+		}
+		return name, nil
+	}
+
+	// Qualify references in the moved code to the remaining code...
+	for _, r := range outgoing {
+		if _, obj := info.Scopes[r.pgf.File].Innermost(r.id.Pos()).LookupParent(srcName, r.id.Pos()); obj != nil && obj.Parent() != srcScope {
+			return nil, fmt.Errorf("%v: cannot refer to package %s: name %s is already in use", posn(r.id.Pos()), srcPath, srcName)
+		}
+		if err := edit(r.pgf, r.id.Pos(), r.id.End(), srcName+"."+newName(r.obj)); err != nil {
+			return nil, err
+		}
+	}
+	// ...and vice versa.
+	for _, r := range incoming {
+		name, err := qualifier(pkg, r.pgf, r.id.Pos(), dstPath, dstName)
+		if err != nil {
+			return nil, err
+		}
+		if err := edit(r.pgf, r.id.Pos(), r.id.End(), name+"."+newName(r.obj)); err != nil {
+			return nil, err
+		}
+	}
+	// References in the moved code to dst need no qualifier.
+	for _, id := range dstQuals {
+		sel, ok := enclosingSelector(pgf, id)
+		if !ok {
+			continue
+		}
+		if _, obj := info.Scopes[pgf.File].Innermost(id.Pos()).LookupParent(sel.Sel.Name, id.Pos()); obj != nil && obj.Parent() != srcScope && obj.Parent() != types.Universe {
+			return nil, fmt.Errorf("%v: cannot refer to %s: name is already in use", posn(id.Pos()), sel.Sel.Name)
+		}
+		if err := edit(pgf, sel.Pos(), sel.Sel.Pos(), ""); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update references in packages that import src.
+	var importerIDs []PackageID
+	for _, id := range slices.Sorted(maps.Keys(g.Packages)) {
+		mp := g.Packages[id]
+		if _, ok := mp.DepsByPkgPath[PackagePath(srcPath)]; ok && string(mp.PkgPath) != srcPath &&
+			!mp.IsIntermediateTestVariant() && snapshot.IsWorkspacePackage(id) {
+			importerIDs = append(importerIDs, id)
+		}
+	}
+	importers, err := snapshot.TypeCheck(ctx, importerIDs...)
+	if err != nil {
+		return nil, err
+	}
+	newEdges := make(map[string][]string) // new import edges, by package path
+	addEdge := func(from, to string) {
+		if from != to && !slices.Contains(newEdges[from], to) {
+			newEdges[from] = append(newEdges[from], to)
+		}
+	}
+	if len(outgoing) > 0 {
+		addEdge(dstPath, srcPath)
+	}
+	if len(incoming) > 0 {
+		addEdge(srcPath, dstPath)
+	}
+	seen := make(map[protocol.DocumentURI]bool)
+	for _, ipkg := range importers {
+		iinfo := ipkg.TypesInfo()
+		for _, ipgf := range ipkg.CompiledGoFiles() {
+			if seen[ipgf.URI] || srcFiles[ipgf.URI] != nil {
+				continue
+			}
+			seen[ipgf.URI] = true
+			uses := make(map[*types.PkgName]int) // number of uses of each import of src
+			for cur := range ipgf.Cursor.Preorder((*ast.Ident)(nil)) {
+				id := cur.Node().(*ast.Ident)
+				if pn, ok := iinfo.Uses[id].(*types.PkgName); ok && pn.Imported().Path() == srcPath {
+					uses[pn]++
+				}
+			}
+			updated := make(map[*types.PkgName]int)
+			for cur := range ipgf.Cursor.Preorder((*ast.SelectorExpr)(nil)) {
+				sel := cur.Node().(*ast.SelectorExpr)
+				x, ok := sel.X.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				pn, ok := iinfo.Uses[x].(*types.PkgName)
+				if !ok || pn.Imported().Path() != srcPath {
+					continue
+				}
+				obj := iinfo.Uses[sel.Sel]
+				if obj == nil || !slices.ContainsFunc(movedObjs, func(m types.Object) bool { return m.Name() == obj.Name() }) {
+					continue
+				}
+				if ipkg.Types().Path() == dstPath {
+					scope := iinfo.Scopes[ipgf.File].Innermost(sel.Pos())
+					if _, obj := scope.LookupParent(sel.Sel.Name, sel.Pos()); obj != nil {
+						return nil, fmt.Errorf("%v: cannot refer to %s: name is already in use", posn(sel.Pos()), sel.Sel.Name)
+					}
+					if err := edit(ipgf, sel.Pos(), sel.Sel.Pos(), ""); err != nil {
+						return nil, err
+					}
+				} else {
+					name, err := qualifier(ipkg, ipgf, x.Pos(), dstPath, dstName)
+					if err != nil {
+						return nil, err
+					}
+					if err := edit(ipgf, x.Pos(), x.End(), name); err != nil {
+						return nil, err
+					}
+					addEdge(ipkg.Types().Path(), dstPath)
+				}
+				updated[pn]++
+			}
+			for _, spec := range ipgf.File.Imports {
+				if spec.Name != nil && spec.Name.Name == "." && metadata.UnquoteImportPath(spec) == metadata.ImportPath(srcPath) {
+					return nil, fmt.Errorf("%v: cannot update dot import of package %s", posn(spec.Pos()), srcPath)
+				}
+				if pn := iinfo.PkgNameOf(spec); pn != nil && updated[pn] > 0 && updated[pn] == uses[pn] {
+					ic := importsOf(ipgf.URI)
+					ic.delete = append(ic.delete, spec)
+				}
+			}
+		}
+	}
+
+	// Compute the imports of the new file, and those that
+	// become unused in the source file.
+	for _, spec := range pgf.File.Imports {
+		if spec.Name != nil && spec.Name.Name == "." {
+			return nil, errors.New("cannot move declarations from a file containing dot imports")
+		}
+	}
+	adds, deletes, err := findImportEdits(pgf.File, info, start, end)
+	if err != nil {
+		return nil, err
+	}
+	importsOf(pgf.URI).delete = append(importsOf(pgf.URI).delete, deletes...)
+	var newImports []*ast.ImportSpec
+	for _, spec := range adds {
+		if ipath := string(metadata.UnquoteImportPath(spec)); ipath != dstPath {
+			newImports = append(newImports, spec)
+			addEdge(dstPath, ipath)
+		}
+	}
+
+	// Reject new imports that are invalid or create a cycle.
+	goList := snapshot.View().Type() != cache.GoPackagesDriverView
+	for _, from := range slices.Sorted(maps.Keys(newEdges)) {
+		for _, to := range newEdges[from] {
+			if !metadata.IsValidImport(PackagePath(from), PackagePath(to), goList) {
+				return nil, fmt.Errorf("cannot move declarations to %s: package %s may not import internal package %s", dstPath, from, to)
+			}
+			if to == srcPath && srcName == "main" {
+				return nil, fmt.Errorf("cannot move declarations to %s: package %s would import package main", dstPath, from)
+			}
+			if dependsOn(g, newEdges, to, from) {
+				return nil, fmt.Errorf("cannot move declarations to %s: package %s would import %s, which depends on %s, creating an import cycle", dstPath, from, to, from)
+			}
+		}
+	}
+
+	// Move the edits within the region to the new file,
+	// and delete the region and its trailing blank lines.
+	startOffset, endOffset, err := safetoken.Offsets(pgf.Tok, start, end)
+	if err != nil {
+		return nil, err
+	}
+	var regionEdits, otherEdits []diff.Edit
+	for _, e := range edits[pgf.URI] {
+		if startOffset <= e.Start && e.End <= endOffset {
+			regionEdits = append(regionEdits, diff.Edit{Start: e.Start - startOffset, End: e.End - startOffset, New: e.New})
+		} else {
+			otherEdits = append(otherEdits, e)
+		}
+	}
+	region, err := diff.Apply(string(pgf.Src[startOffset:endOffset]), dedupEdits(regionEdits))
+	if err != nil {
+		return nil, err
+	}
+	rest := pgf.Src[endOffset:]
+	endOffset += len(rest) - len(bytes.TrimLeft(rest, " \t\n"))
+	edits[pgf.URI] = append(otherEdits, diff.Edit{Start: startOffset, End: endOffset})
+
+	// Update the existing files.
+	var changes []protocol.DocumentChange
+	for _, uri := range slices.Sorted(maps.Keys(edits)) {
+		fpgf := srcFiles[uri]
+		if fpgf == nil {
+			for _, ipkg := range importers {
+				for _, f := range ipkg.CompiledGoFiles() {
+					if f.URI == uri {
+						fpgf = f
+					}
+				}
+			}
+		}
+		if fpgf == nil {
+			continue // e.g. generated file not in any package
+		}
+		newSrc, err := diff.Apply(string(fpgf.Src), dedupEdits(edits[uri]))
+		if err != nil {
+			return nil, err
+		}
+		if ic := imports[uri]; ic != nil && (len(ic.add) > 0 || len(ic.delete) > 0) {
+			newSrc, err = updateImports(uri, newSrc, ic.add, ic.delete)
+			if err != nil {
+				return nil, err
+			}
+		}
+		fh, err := snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+		textedits, err := protocol.EditsFromDiffEdits(fpgf.Mapper, diff.Strings(string(fpgf.Src), newSrc))
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, protocol.DocumentChangeEdit(fh, textedits))
+	}
+	// Create the new file.
+	var buf bytes.Buffer
+	if c := copyrightComment(pgf.File); c != nil {
+		start, end, err := pgf.NodeOffsets(c)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(pgf.Src[start:end])
+		buf.WriteString("\n\n")
+	}
+	if c := buildConstraintComment(pgf.File); c != nil {
+		start, end, err := pgf.NodeOffsets(c)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(pgf.Src[start:end])
+		buf.WriteString("\n\n")
+	}
+	fmt.Fprintf(&buf, "package %s\n", dstName)
+	var importLines []string
+	for _, spec := range newImports {
+		if spec.Name != nil {
+			importLines = append(importLines, spec.Name.Name+" "+spec.Path.Value)
+		} else {
+			importLines = append(importLines, spec.Path.Value)
+		}
+	}
+	if len(outgoing) > 0 {
+		line := strconv.Quote(srcPath)
+		if path.Base(srcPath) != srcName {
+			line = srcName + " " + line
+		}
+		if len(importLines) > 0 {
+			line = "\n" + line // separate group
+		}
+		importLines = append(importLines, line)
+	}
+	switch len(importLines) {
+	case 0:
+	case 1:
+		fmt.Fprintf(&buf, "import %s\n", importLines[0])
+	default:
+		fmt.Fprintf(&buf, "import (\n%s\n)\n", strings.Join(importLines, "\n"))
+	}
+	buf.WriteString(region)
+	newContent, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	newFile, err := chooseNewFile(ctx, snapshot, dir, firstSymbol)
+	if err != nil {
+		return nil, err
+	}
+	return append(changes,
+		protocol.DocumentChangeCreate(newFile.URI()),
+		protocol.DocumentChangeEdit(newFile, []protocol.TextEdit{
+			{Range: protocol.Range{}, NewText: string(newContent)},
+		})), nil
+}
+
+// dependsOn reports whether the package with path x depends, directly
+// or indirectly, on the package with path y, in the metadata graph g
+// extended by the import edges in newEdges.
+func dependsOn(g *metadata.Graph, newEdges map[string][]string, x, y string) bool {
+	seen := make(map[string]bool)
+	var visit func(p string) bool
+	visit = func(p string) bool {
+		if p == y {
+			return true
+		}
+		if seen[p] {
+			return false
+		}
+		seen[p] = true
+		for _, mp := range g.Packages {
+			if string(mp.PkgPath) == p && mp.ForTest == "" {
+				for dep := range mp.DepsByPkgPath {
+					if visit(string(dep)) {
+						return true
+					}
+				}
+			}
+		}
+		return slices.ContainsFunc(newEdges[p], visit)
+	}
+	return visit(x)
+}
+
+// enclosingSelector returns the selector expression X.Sel whose
+// operand is the identifier x.
+func enclosingSelector(pgf *parsego.File, x *ast.Ident) (*ast.SelectorExpr, bool) {
+	cur, ok := pgf.Cursor.FindNode(x)
+	if !ok {
+		return nil, false
+	}
+	sel, ok := cur.Parent().Node().(*ast.SelectorExpr)
+	return sel, ok && sel.X == x
+}
+
+// dedupEdits returns the edits, keeping only the last of those with
+// the same span, so that a qualified reference replaces the renaming
+// of the same identifier.
+func dedupEdits(edits []diff.Edit) []diff.Edit {
+	last := make(map[[2]int]diff.Edit)
+	var spans [][2]int
+	for _, e := range edits {
+		span := [2]int{e.Start, e.End}
+		if _, ok := last[span]; !ok {
+			spans = append(spans, span)
+		}
+		last[span] = e
+	}
+	result := make([]diff.Edit, 0, len(spans))
+	for _, span := range spans {
+		result = append(result, last[span])
+	}
+	return result
+}
+
+// updateImports returns src, the content of the file uri, after
+// adding the imports add (a mapping from path to name), deleting the
+// imports del (and the parentheses around a sole remaining import),
+// and formatting.
+func updateImports(uri protocol.DocumentURI, src string, add map[string]string, del []*ast.ImportSpec) (string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, uri.Path(), src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return "", err
+	}
+	for _, spec := range del {
+		name := ""
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		astutil.DeleteNamedImport(fset, f, name, string(metadata.UnquoteImportPath(spec)))
+	}
+	if len(del) > 0 {
+		// Remove the parens around a sole remaining import.
+		for _, decl := range f.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT && len(gen.Specs) == 1 && gen.Lparen.IsValid() {
+				gen.Lparen, gen.Rparen = token.NoPos, token.NoPos
+			}
+		}
+	}
+	for _, ipath := range slices.Sorted(maps.Keys(add)) {
+		name := add[ipath]
+		if path.Base(ipath) == name {
+			name = ""
+		}
+		astutil.AddNamedImport(fset, f, name, ipath)
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, f); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
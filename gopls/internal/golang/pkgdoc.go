@@ -264,19 +264,9 @@ type Web interface {
 	SrcURL(filename string, line, col8 int) protocol.URI
 }
 
-// PackageDocHTML formats the package documentation page.
-//
-// The posURL function returns a URL that when visited, has the side
-// effect of causing gopls to direct the client editor to navigate to
-// the specified file/line/column position, in UTF-8 coordinates.
-//
-// TODO(adonovan): this function could use some unit tests; we
-// shouldn't have to use integration tests to cover microdetails of
-// HTML rendering. (It is tempting to abstract this function so that
-// it depends only on FileSet/File/Types/TypeInfo/etc, but we should
-// bend the tests to the production interfaces, not the other way
-// around.)
-func PackageDocHTML(viewID string, pkg *cache.Package, web Web) ([]byte, error) {
+// docPackage returns the documentation of the exported symbols of
+// the package.
+func docPackage(pkg *cache.Package) *doc.Package {
 	// We can't use doc.NewFromFiles (even with doc.PreserveAST
 	// mode) as it calls ast.NewPackage which assumes that each
 	// ast.File has an ast.Scope and resolves identifiers to
@@ -331,6 +321,23 @@ func PackageDocHTML(viewID string, pkg *cache.Package, web Web) ([]byte, error)
 			return unexported(t.Name)
 		})
 	}
+	return docpkg
+}
+
+// PackageDocHTML formats the package documentation page.
+//
+// The posURL function returns a URL that when visited, has the side
+// effect of causing gopls to direct the client editor to navigate to
+// the specified file/line/column position, in UTF-8 coordinates.
+//
+// TODO(adonovan): this function could use some unit tests; we
+// shouldn't have to use integration tests to cover microdetails of
+// HTML rendering. (It is tempting to abstract this function so that
+// it depends only on FileSet/File/Types/TypeInfo/etc, but we should
+// bend the tests to the production interfaces, not the other way
+// around.)
+func PackageDocHTML(viewID string, pkg *cache.Package, web Web) ([]byte, error) {
+	docpkg := docPackage(pkg)
 
 	// docHTML renders the doc comment as Markdown.
 	// The fileNode is used to deduce the enclosing file
@@ -850,3 +857,94 @@ window.addEventListener('load', function() {
 
 	return buf.Bytes(), nil
 }
+
+// PackageDocText formats the documentation of the package as plain
+// text, in the style of "go doc -all", for display in the client
+// editor as a read-only document.
+func PackageDocText(pkg *cache.Package) []byte {
+	docpkg := docPackage(pkg)
+
+	// Doc comments of declarations are indented;
+	// the package doc comment is not.
+	var (
+		parse      = newDocCommentParser(pkg)
+		printer    = &comment.Printer{TextPrefix: "    ", TextCodePrefix: "    \t"}
+		pkgPrinter = &comment.Printer{TextCodePrefix: "\t"}
+	)
+
+	// nodeText returns the source text of a syntax tree.
+	nodeText := func(n ast.Node) string {
+		for _, file := range pkg.CompiledGoFiles() {
+			if goplsastutil.NodeContains(file.File, n.Pos()) {
+				start, end, err := safetoken.Offsets(file.Tok, n.Pos(), n.End())
+				if err == nil {
+					return string(file.Src[start:end])
+				}
+			}
+		}
+		var buf bytes.Buffer
+		if err := format.Node(&buf, pkg.FileSet(), n); err != nil {
+			return fmt.Sprintf("formatting error: %v", err)
+		}
+		return buf.String()
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "package %s // import %q\n\n", pkg.Types().Name(), pkg.Types().Path())
+	for _, f := range pkg.Syntax() {
+		if f.Doc != nil {
+			buf.Write(pkgPrinter.Text(parse(f.Doc, docpkg.Doc)))
+			buf.WriteByte('\n')
+			break
+		}
+	}
+
+	// decl emits a declaration followed by its doc comment.
+	decl := func(text string, docNode ast.Node, doc string) {
+		buf.WriteString(text)
+		buf.WriteByte('\n')
+		if doc != "" {
+			buf.Write(printer.Text(parse(docNode, doc)))
+		}
+		buf.WriteByte('\n')
+	}
+	values := func(vals []*doc.Value) {
+		for _, v := range vals {
+			decl2 := *v.Decl // shallow copy
+			decl2.Doc = nil
+			decl(nodeText(&decl2), v.Decl, v.Doc)
+		}
+	}
+	funcs := func(funcs []*doc.Func) {
+		for _, docfn := range funcs {
+			// The FuncType spans "func (recv) F(params) results".
+			decl(nodeText(docfn.Decl.Type), docfn.Decl, docfn.Doc)
+		}
+	}
+
+	if len(docpkg.Consts) > 0 {
+		buf.WriteString("CONSTANTS\n\n")
+		values(docpkg.Consts)
+	}
+	if len(docpkg.Vars) > 0 {
+		buf.WriteString("VARIABLES\n\n")
+		values(docpkg.Vars)
+	}
+	if len(docpkg.Funcs) > 0 {
+		buf.WriteString("FUNCTIONS\n\n")
+		funcs(docpkg.Funcs)
+	}
+	if len(docpkg.Types) > 0 {
+		buf.WriteString("TYPES\n\n")
+		for _, doctype := range docpkg.Types {
+			decl2 := *doctype.Decl // shallow copy
+			decl2.Doc = nil
+			decl(nodeText(&decl2), doctype.Decl, doctype.Doc)
+			values(doctype.Consts)
+			values(doctype.Vars)
+			funcs(doctype.Funcs)
+			funcs(doctype.Methods)
+		}
+	}
+	return buf.Bytes()
+}
@@ -67,6 +67,7 @@ import (
 	"golang.org/x/tools/gopls/internal/cache/parsego"
 	"golang.org/x/tools/gopls/internal/file"
 	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
 	goplsastutil "golang.org/x/tools/gopls/internal/util/astutil"
 	"golang.org/x/tools/gopls/internal/util/bug"
 	"golang.org/x/tools/gopls/internal/util/safetoken"
@@ -322,7 +323,7 @@ func renameFuncSignature(ctx context.Context, snapshot *cache.Snapshot, f file.H
 		}
 	}
 
-	var newParams []int
+	var newParams []command.ChangeSignatureParam
 	for name, field := range goplsastutil.FlatFields(newType.Params) {
 		if name == nil {
 			return nil, fmt.Errorf("need named fields")
@@ -334,14 +335,14 @@ func renameFuncSignature(ctx context.Context, snapshot *cache.Snapshot, f file.H
 		if newType := types.ExprString(field.Type); newType != info.typ {
 			return nil, fmt.Errorf("changing types (%s to %s) not yet supported", info.typ, newType)
 		}
-		newParams = append(newParams, info.idx)
+		newParams = append(newParams, command.ChangeSignatureParam{OldIndex: info.idx})
 	}
 
 	rng, err := pgf.PosRange(ftyp.Func, ftyp.Func)
 	if err != nil {
 		return nil, err
 	}
-	changes, err := ChangeSignature(ctx, snapshot, pkg, pgf, rng, newParams)
+	changes, err := ChangeSignature(ctx, snapshot, pkg, pgf, rng, newParams, nil, false)
 	if err != nil {
 		return nil, err
 	}
@@ -422,7 +423,16 @@ func Rename(ctx context.Context, snapshot *cache.Snapshot, f file.Handle, pp pro
 		return nil, false, err
 	}
 
-	// Convert edits to protocol form.
+	result, err := toProtocolEdits(ctx, snapshot, editMap)
+	if err != nil {
+		return nil, false, err
+	}
+	return result, inPackageName, nil
+}
+
+// toProtocolEdits converts a map of diff edits, as produced by the
+// renaming machinery, to protocol form.
+func toProtocolEdits(ctx context.Context, snapshot *cache.Snapshot, editMap map[protocol.DocumentURI][]diff.Edit) (map[protocol.DocumentURI][]protocol.TextEdit, error) {
 	result := make(map[protocol.DocumentURI][]protocol.TextEdit)
 	for uri, edits := range editMap {
 		// Sort and de-duplicate edits.
@@ -454,21 +464,21 @@ func Rename(ctx context.Context, snapshot *cache.Snapshot, f file.Handle, pp pro
 		// vendor/k8s.io/kubectl -> ../../staging/src/k8s.io/kubectl.
 		fh, err := snapshot.ReadFile(ctx, uri)
 		if err != nil {
-			return nil, false, err
+			return nil, err
 		}
 		data, err := fh.Content()
 		if err != nil {
-			return nil, false, err
+			return nil, err
 		}
 		m := protocol.NewMapper(uri, data)
 		textedits, err := protocol.EditsFromDiffEdits(m, edits)
 		if err != nil {
-			return nil, false, err
+			return nil, err
 		}
 		result[uri] = textedits
 	}
 
-	return result, inPackageName, nil
+	return result, nil
 }
 
 // renameOrdinary renames an ordinary (non-package) name throughout the workspace.
@@ -0,0 +1,515 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the refactor.rewrite.safeDelete code action.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/methodsets"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// safeDeleteTarget returns the object declared by the identifier
+// selected by [start, end), if it is a function, method, type, struct
+// field, or package-level constant.
+func safeDeleteTarget(info *types.Info, pgf *parsego.File, start, end token.Pos) (types.Object, *ast.Ident, bool) {
+	cur, ok := pgf.Cursor.FindPos(start, end)
+	if !ok {
+		return nil, nil, false
+	}
+	// The FuncType of a FuncDecl spans its name.
+	if decl, ok := cur.Parent().Node().(*ast.FuncDecl); ok && is[*ast.FuncType](cur.Node()) &&
+		decl.Name.Pos() <= start && end <= decl.Name.End() {
+		cur, _ = cur.Parent().FindNode(decl.Name)
+	}
+	id, ok := cur.Node().(*ast.Ident)
+	if !ok || id.Name == "_" {
+		return nil, nil, false
+	}
+	obj := info.Defs[id]
+	switch cur.Parent().Node().(type) {
+	case *ast.FuncDecl:
+		_, ok = obj.(*types.Func)
+	case *ast.TypeSpec:
+		_, ok = obj.(*types.TypeName)
+	case *ast.Field:
+		_, ok = cur.Parent().Parent().Parent().Node().(*ast.StructType)
+	case *ast.ValueSpec:
+		c, isConst := obj.(*types.Const)
+		ok = isConst && typesinternal.IsPackageLevel(c)
+	default:
+		ok = false
+	}
+	if !ok {
+		return nil, nil, false
+	}
+	return obj, id, true
+}
+
+// SafeDelete returns the changes that delete the declaration of the
+// function, method, type, struct field, or constant whose name is
+// selected by rng, along with its doc comment, and with the methods
+// of a type. Imports that become unused are deleted too.
+//
+// The deletion is refused, with an error that lists the references,
+// if the declaration is referenced anywhere in the workspace other
+// than within the deleted declarations. If deleteTests is set,
+// references from test functions are permitted, and those functions
+// are deleted as well.
+//
+// The deletion is also refused if it might break the program in ways
+// that are not revealed by references: if a method may be needed to
+// satisfy an interface, if a field has a struct tag (and so is likely
+// accessed by reflection) or is initialized by a struct literal that
+// does not name its fields, or if deleting a constant would change
+// the implicit values of the constants that follow it.
+func SafeDelete(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rng protocol.Range, deleteTests bool) ([]protocol.DocumentChange, error) {
+	// The widest package includes the in-package tests.
+	pkg, pgf, err := WidestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, err
+	}
+	if perrors, terrors := pkg.ParseErrors(), pkg.TypeErrors(); len(perrors) > 0 || len(terrors) > 0 {
+		return nil, fmt.Errorf("cannot delete declarations from package %s, which has errors", pkg.Types().Name())
+	}
+	start, end, err := pgf.RangePos(rng)
+	if err != nil {
+		return nil, err
+	}
+	info := pkg.TypesInfo()
+	obj, id, ok := safeDeleteTarget(info, pgf, start, end)
+	if !ok {
+		return nil, errors.New("selection is not the name of a function, method, type, field, or constant declaration")
+	}
+	kind := objectKind(obj)
+	posn := func(pos token.Pos) token.Position { return safetoken.StartPosition(pkg.FileSet(), pos) }
+
+	// Compute the deletions, per file, and check the conditions
+	// for each kind of declaration that references cannot reveal.
+	d := &deleter{files: make(map[protocol.DocumentURI]*deletionFile)}
+	cur, _ := pgf.Cursor.FindNode(id)
+	switch obj := obj.(type) {
+	case *types.Func:
+		decl := cur.Parent().Node().(*ast.FuncDecl)
+		if decl.Recv == nil && (obj.Name() == "init" || obj.Name() == "main" && pkg.Types().Name() == "main") {
+			return nil, fmt.Errorf("cannot delete %s function, which is called by the runtime", obj.Name())
+		}
+		if decl.Doc != nil {
+			for _, c := range decl.Doc.List {
+				if strings.HasPrefix(c.Text, "//go:linkname ") || strings.HasPrefix(c.Text, "//export ") {
+					return nil, fmt.Errorf("cannot delete %s %s, which may be referenced from outside Go (%s)", kind, obj.Name(), strings.Fields(c.Text)[0])
+				}
+			}
+		}
+		if obj.Signature().Recv() != nil {
+			if err := checkMethodInterfaces(ctx, snapshot, pkg, obj); err != nil {
+				return nil, err
+			}
+		}
+		if err := d.deleteNode(pgf, info, decl, decl.Doc); err != nil {
+			return nil, err
+		}
+
+	case *types.TypeName:
+		spec := cur.Parent().Node().(*ast.TypeSpec)
+		gen := cur.Parent().Parent().Node().(*ast.GenDecl)
+		if len(gen.Specs) == 1 {
+			err = d.deleteNode(pgf, info, gen, gen.Doc)
+		} else {
+			err = d.deleteNode(pgf, info, spec, spec.Doc)
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Delete the type's methods too.
+		if named, ok := obj.Type().(*types.Named); ok && !obj.IsAlias() {
+			for m := range named.Methods() {
+				mpgf, ok := enclosingFile(pkg, m.Pos())
+				if !ok {
+					return nil, fmt.Errorf("cannot find declaration of method %s", m.Name())
+				}
+				mcur, ok := mpgf.Cursor.FindPos(m.Pos(), m.Pos())
+				if !ok {
+					return nil, fmt.Errorf("cannot find declaration of method %s", m.Name())
+				}
+				decl := mcur.Parent().Node().(*ast.FuncDecl)
+				if err := d.deleteNode(mpgf, info, decl, decl.Doc); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+	case *types.Var:
+		field := cur.Parent().Node().(*ast.Field)
+		if len(field.Names) > 1 {
+			return nil, fmt.Errorf("cannot delete field %s, which is declared together with other fields", obj.Name())
+		}
+		if field.Tag != nil {
+			return nil, fmt.Errorf("cannot delete field %s: it has a struct tag, so it may be accessed by reflection", obj.Name())
+		}
+		// A struct literal that does not name its fields would
+		// become invalid. (Such literals of exported struct types
+		// in other packages are not detected.)
+		st := info.TypeOf(cur.Parent().Parent().Parent().Node().(*ast.StructType))
+		for _, upgf := range pkg.CompiledGoFiles() {
+			for lcur := range upgf.Cursor.Preorder((*ast.CompositeLit)(nil)) {
+				lit := lcur.Node().(*ast.CompositeLit)
+				if t := info.TypeOf(lit); t != nil && types.Identical(t.Underlying(), st) &&
+					len(lit.Elts) > 0 && !is[*ast.KeyValueExpr](lit.Elts[0]) {
+					return nil, fmt.Errorf("cannot delete field %s: the struct literal at %s does not name its fields", obj.Name(), posn(lit.Pos()))
+				}
+			}
+		}
+		if err := d.deleteNode(pgf, info, field, field.Doc); err != nil {
+			return nil, err
+		}
+
+	case *types.Const:
+		spec := cur.Parent().Node().(*ast.ValueSpec)
+		gen := cur.Parent().Parent().Node().(*ast.GenDecl)
+		if len(spec.Names) > 1 {
+			return nil, fmt.Errorf("cannot delete constant %s, which is declared together with other constants", obj.Name())
+		}
+		// Deleting a constant other than the last of a group that
+		// uses iota or implicit repetition would change the values
+		// of those that follow it.
+		if index := slices.Index(gen.Specs, ast.Spec(spec)); index < len(gen.Specs)-1 &&
+			slices.ContainsFunc(gen.Specs, func(s ast.Spec) bool {
+				s1 := s.(*ast.ValueSpec)
+				return len(s1.Values) == 0 || slices.ContainsFunc(s1.Values, func(e ast.Expr) bool { return usesIota(info, e) })
+			}) {
+			return nil, fmt.Errorf("cannot delete constant %s: it would change the values of the constants that follow it", obj.Name())
+		}
+		if len(gen.Specs) == 1 {
+			err = d.deleteNode(pgf, info, gen, gen.Doc)
+		} else {
+			err = d.deleteNode(pgf, info, spec, spec.Doc)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Find the references that are not within the deleted declarations.
+	pp, err := pgf.PosPosition(id.Pos())
+	if err != nil {
+		return nil, err
+	}
+	refs, err := references(ctx, snapshot, fh, pp, false)
+	if err != nil {
+		return nil, err
+	}
+	var remaining, inTests []protocol.Location
+	for _, ref := range refs {
+		if d.contains(ref.location) {
+			continue
+		}
+		remaining = append(remaining, ref.location)
+		if strings.HasSuffix(ref.location.URI.Path(), "_test.go") {
+			inTests = append(inTests, ref.location)
+		}
+	}
+	if len(remaining) > 0 && (!deleteTests || len(inTests) < len(remaining)) {
+		var buf strings.Builder
+		fmt.Fprintf(&buf, "cannot delete %s %s, which is referenced", kind, obj.Name())
+		if len(inTests) == len(remaining) {
+			buf.WriteString(" only by tests")
+		}
+		buf.WriteString(":")
+		const max = 10
+		for i, loc := range remaining {
+			if i == max {
+				fmt.Fprintf(&buf, "\n\t(and %d more)", len(remaining)-max)
+				break
+			}
+			fmt.Fprintf(&buf, "\n\t%s:%d:%d", loc.URI.Path(), loc.Range.Start.Line+1, loc.Range.Start.Character+1)
+		}
+		return nil, errors.New(buf.String())
+	}
+
+	// Delete the test functions that refer to the declaration.
+	for _, loc := range inTests {
+		if d.contains(loc) {
+			continue // already deleted
+		}
+		tpkg, tpgf := pkg, (*parsego.File)(nil)
+		if f, err := pkg.File(loc.URI); err == nil {
+			tpgf = f
+		} else if tpkg, tpgf, err = NarrowestPackageForFile(ctx, snapshot, loc.URI); err != nil {
+			return nil, err
+		}
+		pos, err := tpgf.PositionPos(loc.Range.Start)
+		if err != nil {
+			return nil, err
+		}
+		var decl *ast.FuncDecl
+		for _, d := range tpgf.File.Decls {
+			if d.Pos() <= pos && pos < d.End() {
+				decl, _ = d.(*ast.FuncDecl)
+				break
+			}
+		}
+		if decl == nil || decl.Recv != nil || !isTestFuncName(decl.Name.Name) {
+			return nil, fmt.Errorf("cannot delete %s %s, which is referenced at %s:%d:%d, outside a test function",
+				kind, obj.Name(), loc.URI.Path(), loc.Range.Start.Line+1, loc.Range.Start.Character+1)
+		}
+		if err := d.deleteNode(tpgf, tpkg.TypesInfo(), decl, decl.Doc); err != nil {
+			return nil, err
+		}
+	}
+
+	return d.changes(ctx, snapshot)
+}
+
+// isTestFuncName reports whether name is that of a test, benchmark,
+// fuzz test, or example function.
+func isTestFuncName(name string) bool {
+	for _, prefix := range []string{"Test", "Benchmark", "Fuzz", "Example"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkMethodInterfaces returns an error if the concrete method fn,
+// declared in pkg, may be required for its receiver type to satisfy
+// an interface, declared in pkg, one of its dependencies, or a
+// workspace package.
+func checkMethodInterfaces(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, fn *types.Func) error {
+	recv := fn.Signature().Recv().Type()
+	if types.IsInterface(recv) {
+		return nil
+	}
+
+	// Search the interfaces of pkg and its dependencies.
+	if _, named := typesinternal.ReceiverNamed(fn.Signature().Recv()); named != nil && named.TypeParams().Len() == 0 {
+		ptr := types.NewPointer(named)
+		seen := make(map[*types.Package]bool)
+		var search func(p *types.Package) error
+		search = func(p *types.Package) error {
+			if seen[p] {
+				return nil
+			}
+			seen[p] = true
+			scope := p.Scope()
+			for _, name := range scope.Names() {
+				tname, ok := scope.Lookup(name).(*types.TypeName)
+				if !ok {
+					continue
+				}
+				iface, ok := tname.Type().Underlying().(*types.Interface)
+				if !ok || !iface.IsMethodSet() {
+					continue
+				}
+				for m := range iface.Methods() {
+					if m.Id() == fn.Id() && types.Implements(ptr, iface) {
+						return fmt.Errorf("cannot delete method %s: %s implements %s, which requires it",
+							fn.Name(), named.Obj().Name(), types.TypeString(tname.Type(), types.RelativeTo(pkg.Types())))
+					}
+				}
+			}
+			for _, imp := range p.Imports() {
+				if err := search(imp); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if err := search(pkg.Types()); err != nil {
+			return err
+		}
+	}
+
+	// Search the interfaces of the workspace packages, which may
+	// import pkg, using the method-set index.
+	key, hasMethods := methodsets.KeyOf(methodsets.EnsurePointer(recv))
+	if !hasMethods {
+		return nil
+	}
+	workspace, err := snapshot.WorkspaceMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	ids := make([]PackageID, len(workspace))
+	for i, mp := range workspace {
+		ids[i] = mp.ID
+	}
+	indexes, err := snapshot.MethodSets(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		for _, res := range index.Search(key, fn) {
+			return fmt.Errorf("cannot delete method %s: its receiver type implements the interface with the method at %s",
+				fn.Name(), methodsetLocation(ctx, snapshot, res.Location))
+		}
+	}
+	return nil
+}
+
+// methodsetLocation formats a location reported by the method-set
+// index as file:line:column, or just the file name in case of error.
+func methodsetLocation(ctx context.Context, snapshot *cache.Snapshot, loc methodsets.Location) string {
+	uri := protocol.URIFromPath(loc.Filename)
+	fh, err := snapshot.ReadFile(ctx, uri)
+	if err != nil {
+		return loc.Filename
+	}
+	content, err := fh.Content()
+	if err != nil {
+		return loc.Filename
+	}
+	pos, err := protocol.NewMapper(uri, content).OffsetPosition(loc.Start)
+	if err != nil {
+		return loc.Filename
+	}
+	return fmt.Sprintf("%s:%d:%d", loc.Filename, pos.Line+1, pos.Character+1)
+}
+
+// A deleter accumulates the deletions of declarations, per file.
+type deleter struct {
+	files map[protocol.DocumentURI]*deletionFile
+}
+
+// A deletionFile holds the deletions within a single file.
+type deletionFile struct {
+	pgf       *parsego.File
+	info      *types.Info // of a package containing the file
+	edits     []analysis.TextEdit
+	locations []protocol.Location // of the deleted nodes
+}
+
+// deleteNode records the deletion of node n, along with its doc
+// comment, from file pgf, whose package has type information info.
+func (d *deleter) deleteNode(pgf *parsego.File, info *types.Info, n ast.Node, doc *ast.CommentGroup) error {
+	start, end := n.Pos(), n.End()
+	if doc != nil {
+		start = doc.Pos()
+	}
+	// Include a comment that follows on the same line.
+	for _, cg := range pgf.File.Comments {
+		if cg.Pos() >= end && safetoken.Line(pgf.Tok, cg.Pos()) == safetoken.Line(pgf.Tok, end) {
+			end = cg.End()
+			break
+		}
+	}
+	edit, err := deleteLines(pgf, start, end)
+	if err != nil {
+		return err
+	}
+	loc, err := pgf.PosLocation(start, end)
+	if err != nil {
+		return err
+	}
+	f, ok := d.files[pgf.URI]
+	if !ok {
+		f = &deletionFile{pgf: pgf, info: info}
+		d.files[pgf.URI] = f
+	}
+	f.edits = append(f.edits, edit)
+	f.locations = append(f.locations, loc)
+	return nil
+}
+
+// contains reports whether loc lies within a deleted node.
+func (d *deleter) contains(loc protocol.Location) bool {
+	if f, ok := d.files[loc.URI]; ok {
+		for _, del := range f.locations {
+			if protocol.ComparePosition(del.Range.Start, loc.Range.Start) <= 0 &&
+				protocol.ComparePosition(loc.Range.End, del.Range.End) <= 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// changes returns the changes that apply the deletions, and delete
+// the imports that they leave unused.
+func (d *deleter) changes(ctx context.Context, snapshot *cache.Snapshot) ([]protocol.DocumentChange, error) {
+	var changes []protocol.DocumentChange
+	for _, uri := range slices.Sorted(maps.Keys(d.files)) {
+		f := d.files[uri]
+		deleted := func(pos token.Pos) bool {
+			for _, edit := range f.edits {
+				if edit.Pos <= pos && pos < edit.End {
+					return true
+				}
+			}
+			return false
+		}
+
+		// Find the imports used only within the deletions.
+		var unused []*ast.ImportSpec
+		for _, spec := range f.pgf.File.Imports {
+			pkgname := f.info.PkgNameOf(spec)
+			if pkgname == nil || pkgname.Name() == "_" || pkgname.Name() == "." {
+				continue
+			}
+			used, usedAfter := false, false
+			for cur := range f.pgf.Cursor.Preorder((*ast.Ident)(nil)) {
+				id := cur.Node().(*ast.Ident)
+				if f.info.Uses[id] == pkgname {
+					used = true
+					if !deleted(id.Pos()) {
+						usedAfter = true
+						break
+					}
+				}
+			}
+			if used && !usedAfter {
+				unused = append(unused, spec)
+			}
+		}
+
+		var edits []diff.Edit
+		for _, edit := range f.edits {
+			e, err := posEdit(f.pgf.Tok, edit.Pos, edit.End, "")
+			if err != nil {
+				return nil, err
+			}
+			edits = append(edits, e)
+		}
+		diff.SortEdits(edits)
+		src := string(f.pgf.Src)
+		newSrc, err := diff.Apply(src, edits)
+		if err != nil {
+			return nil, err
+		}
+		if len(unused) > 0 {
+			if newSrc, err = updateImports(uri, newSrc, nil, unused); err != nil {
+				return nil, err
+			}
+		}
+		fh, err := snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+		textedits, err := protocol.EditsFromDiffEdits(f.pgf.Mapper, diff.Strings(src, newSrc))
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, protocol.DocumentChangeEdit(fh, textedits))
+	}
+	return changes, nil
+}
@@ -19,7 +19,6 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
 
 	"golang.org/x/tools/gopls/internal/cache"
 	"golang.org/x/tools/gopls/internal/cache/metadata"
@@ -87,7 +86,6 @@ func SemanticTokens(ctx context.Context, snapshot *cache.Snapshot, fh file.Handl
 			tv.tokens,
 			snapshot.Options().EnabledSemanticTokenTypes(),
 			snapshot.Options().EnabledSemanticTokenModifiers()),
-		ResultID: time.Now().String(), // for delta requests, but we've never seen any
 	}, nil
 }
 
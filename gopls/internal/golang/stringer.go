@@ -0,0 +1,187 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the source.generateStringer code action and the
+// gopls.generate_stringer command that implements it.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/stringer"
+)
+
+// GenerateStringer returns the changes that create or replace the
+// file <type>_string.go containing the String method that the
+// stringer command would generate for the type at the location
+// given by args.
+func GenerateStringer(ctx context.Context, snapshot *cache.Snapshot, args command.GenerateStringerArgs) ([]protocol.DocumentChange, error) {
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, args.Location.URI)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := pgf.RangePos(args.Location.Range)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := stringerType(pkg, pgf, start, end)
+	if !ok {
+		return nil, errors.New("selection is not the name of an integer type with constants")
+	}
+	flags := stringer.Flags{
+		Types:       []string{obj.Name()},
+		TrimPrefix:  args.TrimPrefix,
+		LineComment: args.LineComment,
+	}
+	src, err := stringerSource(pkg, pgf, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	uri := stringerFile(pgf, obj)
+	fh, err := snapshot.ReadFile(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	if old, err := fh.Content(); err == nil {
+		// Replace the existing file.
+		edits, err := protocol.EditsFromDiffEdits(protocol.NewMapper(uri, old), diff.Bytes(old, src))
+		if err != nil {
+			return nil, err
+		}
+		return []protocol.DocumentChange{protocol.DocumentChangeEdit(fh, edits)}, nil
+	}
+	return []protocol.DocumentChange{
+		protocol.DocumentChangeCreate(uri),
+		protocol.DocumentChangeEdit(fh, []protocol.TextEdit{
+			{Range: protocol.Range{}, NewText: string(src)},
+		}),
+	}, nil
+}
+
+// stringerType returns the named integer type whose name is selected,
+// if it has constants and no String method other than one in the file
+// that stringer would generate.
+func stringerType(pkg *cache.Package, pgf *parsego.File, start, end token.Pos) (*types.TypeName, bool) {
+	cur, ok := pgf.Cursor.FindPos(start, end)
+	if !ok {
+		return nil, false
+	}
+	id, ok := cur.Node().(*ast.Ident)
+	if !ok {
+		return nil, false
+	}
+	if spec, ok := cur.Parent().Node().(*ast.TypeSpec); !ok || spec.Name != id || spec.TypeParams != nil {
+		return nil, false
+	}
+	obj, ok := pkg.TypesInfo().Defs[id].(*types.TypeName)
+	if !ok || obj.IsAlias() {
+		return nil, false
+	}
+	if basic, ok := obj.Type().Underlying().(*types.Basic); !ok || basic.Info()&types.IsInteger == 0 {
+		return nil, false
+	}
+	if m, _, _ := types.LookupFieldOrMethod(obj.Type(), true, obj.Pkg(), "String"); m != nil {
+		if safetoken.StartPosition(pkg.FileSet(), m.Pos()).Filename != stringerFile(pgf, obj).Path() {
+			return nil, false
+		}
+	}
+	values, err := stringerValues(pkg, obj.Name(), stringer.Flags{})
+	return obj, err == nil && len(values) > 0
+}
+
+// stringerValues returns the constants of the named type in pkg.
+func stringerValues(pkg *cache.Package, typeName string, flags stringer.Flags) ([]stringer.Value, error) {
+	var files []*ast.File
+	for _, pgf := range pkg.CompiledGoFiles() {
+		files = append(files, pgf.File)
+	}
+	return stringer.FindValues(files, pkg.TypesInfo().Defs, typeName, flags.TrimPrefix, flags.LineComment)
+}
+
+// stringerSource returns the contents of the file that the stringer
+// command would generate for the types in pkg with the given flags.
+// The types are declared in the file pgf.
+func stringerSource(pkg *cache.Package, pgf *parsego.File, flags stringer.Flags) ([]byte, error) {
+	var g stringer.Generator
+	g.Header(stringerArgs(flags), pgf.File.Name.Name)
+	for _, typeName := range flags.Types {
+		values, err := stringerValues(pkg, typeName, flags)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("no values defined for type %s", typeName)
+		}
+		g.Generate(typeName, values)
+	}
+	return g.Format()
+}
+
+// stringerArgs returns the arguments of the stringer command with
+// the given flags.
+func stringerArgs(flags stringer.Flags) []string {
+	args := []string{"-type=" + strings.Join(flags.Types, ",")}
+	if flags.TrimPrefix != "" {
+		args = append(args, "-trimprefix="+flags.TrimPrefix)
+	}
+	if flags.LineComment {
+		args = append(args, "-linecomment")
+	}
+	return args
+}
+
+// stringerFile returns the file that the stringer command would
+// generate for the type declared in pgf.
+func stringerFile(pgf *parsego.File, obj *types.TypeName) protocol.DocumentURI {
+	suffix := "_string.go"
+	if strings.HasSuffix(pgf.URI.Path(), "_test.go") {
+		suffix = "_string_test.go"
+	}
+	return protocol.URIFromPath(filepath.Join(pgf.URI.DirPath(), strings.ToLower(obj.Name())+suffix))
+}
+
+// stringerDirective returns the flags of a //go:generate directive in
+// pkg that runs the stringer command for the named type, if any.
+func stringerDirective(pkg *cache.Package, typeName string) (stringer.Flags, bool) {
+	for _, pgf := range pkg.CompiledGoFiles() {
+		for _, group := range pgf.File.Comments {
+			for _, c := range group.List {
+				rest, ok := strings.CutPrefix(c.Text, "//go:generate ")
+				if !ok {
+					continue
+				}
+				// Accept "stringer", "go run golang.org/x/tools/cmd/stringer[@version]",
+				// and "go tool stringer".
+				words := strings.Fields(rest)
+				for i, word := range words {
+					word, _, _ = strings.Cut(word, "@")
+					if word == "stringer" || strings.HasSuffix(word, "/stringer") {
+						flags, err := stringer.ParseFlags(words[i+1:])
+						if err == nil && slices.Contains(flags.Types, typeName) {
+							return flags, true
+						}
+						break
+					}
+				}
+			}
+		}
+	}
+	return stringer.Flags{}, false
+}
@@ -0,0 +1,394 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the refactor.rewrite.{add,remove}Tags code
+// actions and the gopls.modify_tags command that implements them.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	"golang.org/x/tools/gopls/internal/settings"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/diff"
+)
+
+// ModifyTags returns the edits that add or remove the keys and options
+// of the struct tags of the fields at the location given by args.
+func ModifyTags(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, args command.ModifyTagsArgs) ([]protocol.DocumentChange, error) {
+	pgf, err := snapshot.ParseGo(ctx, fh, parsego.Full)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := pgf.RangePos(args.Location.Range)
+	if err != nil {
+		return nil, err
+	}
+	st, fields, ok := selectedFields(pgf, start, end)
+	if !ok {
+		return nil, errors.New("selection is not within a struct type")
+	}
+	transform := settings.StructTagTransform(args.Transform)
+	if transform == "" {
+		transform = snapshot.Options().StructTagTransform
+	}
+	switch transform {
+	case settings.StructTagCamelCase,
+		settings.StructTagPascalCase,
+		settings.StructTagSnakeCase,
+		settings.StructTagKebabCase,
+		settings.StructTagKeep:
+	default:
+		return nil, fmt.Errorf("unknown struct tag transform %q", transform)
+	}
+	edits, err := tagEdits(pgf, fields, args, transform)
+	if err != nil {
+		return nil, err
+	}
+	if len(edits) == 0 {
+		return nil, nil
+	}
+
+	// Reformat the struct type, since the changed tags
+	// may require realignment of the fields.
+	var textedits []protocol.TextEdit
+	if src, err := diff.Apply(string(pgf.Src), edits); err != nil {
+		return nil, err
+	} else if formatted, err := format.Source([]byte(src)); err == nil {
+		start, end, err := safetoken.Offsets(pgf.Tok, st.Pos(), st.End())
+		if err != nil {
+			return nil, err
+		}
+		textedits, err = rangeEdits(pgf, formatted, []lineSpan{lineSpanOf(pgf.Src, start, end)})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		textedits, err = protocol.EditsFromDiffEdits(pgf.Mapper, edits)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return []protocol.DocumentChange{protocol.DocumentChangeEdit(fh, textedits)}, nil
+}
+
+// selectedFields returns the innermost struct type enclosing the
+// selection, or declared by the type declaration whose name is
+// selected, and those of its fields that intersect the selection.
+// If the selection is empty or intersects no fields, it returns
+// all the fields.
+func selectedFields(pgf *parsego.File, start, end token.Pos) (*ast.StructType, []*ast.Field, bool) {
+	cur, ok := pgf.Cursor.FindPos(start, end)
+	if !ok {
+		return nil, nil, false
+	}
+	var st *ast.StructType
+	if !is[*ast.StructType](cur.Node()) && !is[*ast.TypeSpec](cur.Node()) {
+		for cur = range cur.Ancestors((*ast.StructType)(nil), (*ast.TypeSpec)(nil)) {
+			break
+		}
+	}
+	switch n := cur.Node().(type) {
+	case *ast.StructType:
+		st = n
+	case *ast.TypeSpec:
+		st, _ = n.Type.(*ast.StructType)
+	}
+	if st == nil || len(st.Fields.List) == 0 {
+		return nil, nil, false
+	}
+	var fields []*ast.Field
+	if start < end {
+		for _, field := range st.Fields.List {
+			if field.Pos() < end && start < field.End() {
+				fields = append(fields, field)
+			}
+		}
+	}
+	if len(fields) == 0 {
+		fields = st.Fields.List
+	}
+	return st, fields, true
+}
+
+// A tagItem is a key:"value" pair of a conventional struct tag.
+type tagItem struct{ key, value string }
+
+// tagEdits returns the edits that modify the struct tags of the
+// specified fields as described by args.
+func tagEdits(pgf *parsego.File, fields []*ast.Field, args command.ModifyTagsArgs, transform settings.StructTagTransform) ([]diff.Edit, error) {
+	var edits []diff.Edit
+	for _, field := range fields {
+		var items []tagItem
+		if field.Tag != nil {
+			tag, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			items, err = parseStructTag(tag)
+			if err != nil {
+				return nil, fmt.Errorf("field at line %d: %v", safetoken.Line(pgf.Tok, field.Pos()), err)
+			}
+		}
+		newItems := modifyTag(field, items, args, transform)
+		if slices.Equal(items, newItems) {
+			continue
+		}
+
+		var (
+			pos, end = field.Type.End(), field.Type.End()
+			text     string
+		)
+		if field.Tag != nil {
+			pos, end = field.Tag.Pos(), field.Tag.End()
+		}
+		if len(newItems) == 0 {
+			pos = field.Type.End()
+		} else {
+			text = formatStructTag(newItems)
+			if field.Tag == nil {
+				text = " " + text
+			}
+		}
+		startOffset, endOffset, err := safetoken.Offsets(pgf.Tok, pos, end)
+		if err != nil {
+			return nil, err
+		}
+		edits = append(edits, diff.Edit{Start: startOffset, End: endOffset, New: text})
+	}
+	return edits, nil
+}
+
+// modifyTag returns the items of the tag of the field after the
+// changes described by args.
+func modifyTag(field *ast.Field, items []tagItem, args command.ModifyTagsArgs, transform settings.StructTagTransform) []tagItem {
+	items = slices.DeleteFunc(slices.Clone(items), func(item tagItem) bool {
+		return slices.Contains(args.Remove, item.key)
+	})
+
+	// Add keys only to named, exported fields,
+	// since only they can be accessed by reflection.
+	if len(field.Names) == 1 && field.Names[0].IsExported() {
+		name := transformFieldName(field.Names[0].Name, transform)
+	nextKey:
+		for _, key := range args.Add {
+			for i, item := range items {
+				if item.key == key {
+					if args.Overwrite {
+						value := name
+						if _, opts, ok := strings.Cut(item.value, ","); ok {
+							value += "," + opts
+						}
+						items[i].value = value
+					}
+					continue nextKey
+				}
+			}
+			items = append(items, tagItem{key, name})
+		}
+	}
+
+	// Update the options of each key, except those
+	// that cause the field to be ignored.
+	for i, item := range items {
+		if item.value == "-" {
+			continue
+		}
+		opts := strings.Split(item.value, ",")
+		for _, spec := range args.AddOptions {
+			if opt, ok := tagOption(spec, item.key); ok && !slices.Contains(opts[1:], opt) {
+				opts = append(opts, opt)
+			}
+		}
+		for _, spec := range args.RemoveOptions {
+			if opt, ok := tagOption(spec, item.key); ok {
+				opts = append(opts[:1], slices.DeleteFunc(opts[1:], func(o string) bool { return o == opt })...)
+			}
+		}
+		items[i].value = strings.Join(opts, ",")
+	}
+	return items
+}
+
+// tagOption returns the option of an option specifier, either
+// "key=option" or "option", if it applies to the given key.
+func tagOption(spec, key string) (string, bool) {
+	if k, opt, ok := strings.Cut(spec, "="); ok {
+		return opt, k == key
+	}
+	return spec, true
+}
+
+// parseStructTag parses a struct tag in the conventional format of
+// space-separated key:"value" pairs, following reflect.StructTag.Lookup.
+func parseStructTag(tag string) ([]tagItem, error) {
+	var items []tagItem
+	for {
+		tag = strings.TrimLeft(tag, " ")
+		if tag == "" {
+			return items, nil
+		}
+
+		// Scan to colon. A space, a quote or a control character is a syntax error.
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return nil, errors.New("struct tag is not of the form key:\"value\"")
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		// Scan quoted string to find value.
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return nil, fmt.Errorf("struct tag value of key %s is not terminated", key)
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			return nil, fmt.Errorf("struct tag value of key %s is malformed", key)
+		}
+		items = append(items, tagItem{key, value})
+		tag = tag[i+1:]
+	}
+}
+
+// formatStructTag returns the struct tag literal for the given items,
+// a raw string literal unless the tag contains a backquote.
+func formatStructTag(items []tagItem) string {
+	var pairs []string
+	for _, item := range items {
+		pairs = append(pairs, item.key+":"+strconv.Quote(item.value))
+	}
+	tag := strings.Join(pairs, " ")
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+// transformFieldName returns the struct tag value for the named field
+// according to the transform.
+func transformFieldName(name string, transform settings.StructTagTransform) string {
+	if transform == settings.StructTagKeep {
+		return name
+	}
+	words := splitWords(name)
+	for i, word := range words {
+		switch transform {
+		case settings.StructTagCamelCase:
+			if i == 0 {
+				word = strings.ToLower(word)
+			} else {
+				word = upperFirst(word)
+			}
+		case settings.StructTagPascalCase:
+			word = upperFirst(word)
+		default:
+			word = strings.ToLower(word)
+		}
+		words[i] = word
+	}
+	switch transform {
+	case settings.StructTagSnakeCase:
+		return strings.Join(words, "_")
+	case settings.StructTagKebabCase:
+		return strings.Join(words, "-")
+	}
+	return strings.Join(words, "")
+}
+
+// splitWords splits a Go identifier into words, at underscores and at
+// changes of case, treating a run of capitals as a single word (an
+// initialism) except for its last letter if a lower-case letter
+// follows: "HTTPServer_ID2" becomes "HTTP", "Server", "ID2".
+func splitWords(name string) []string {
+	var (
+		words []string
+		runes = []rune(name)
+		start = 0
+	)
+	for i, r := range runes {
+		switch {
+		case r == '_':
+			if start < i {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+		case i > start && unicode.IsUpper(r) &&
+			(!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])):
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// upperFirst returns s with its first letter in upper case.
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// omitemptyKeys is the set of well-known struct tag keys that
+// support the omitempty option.
+var omitemptyKeys = map[string]bool{
+	"bson": true,
+	"json": true,
+	"toml": true,
+	"xml":  true,
+	"yaml": true,
+}
+
+// structTagKeys returns the keys of the struct tags of the fields,
+// in order of first appearance. It returns false if any struct tag
+// is malformed.
+func structTagKeys(fields []*ast.Field) ([]string, bool) {
+	var keys []string
+	for _, field := range fields {
+		if field.Tag == nil {
+			continue
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			return nil, false
+		}
+		items, err := parseStructTag(tag)
+		if err != nil {
+			return nil, false
+		}
+		for _, item := range items {
+			if !slices.Contains(keys, item.key) {
+				keys = append(keys, item.key)
+			}
+		}
+	}
+	return keys, true
+}
@@ -0,0 +1,37 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/settings"
+)
+
+func TestTransformFieldName(t *testing.T) {
+	for _, test := range []struct {
+		name                              string
+		camel, pascal, snake, kebab, keep string
+	}{
+		{"Name", "name", "Name", "name", "name", "Name"},
+		{"UserID", "userID", "UserID", "user_id", "user-id", "UserID"},
+		{"HTTPServer", "httpServer", "HTTPServer", "http_server", "http-server", "HTTPServer"},
+		{"Base64Data", "base64Data", "Base64Data", "base64_data", "base64-data", "Base64Data"},
+		{"Snake_Case", "snakeCase", "SnakeCase", "snake_case", "snake-case", "Snake_Case"},
+		{"X", "x", "X", "x", "x", "X"},
+	} {
+		for transform, want := range map[settings.StructTagTransform]string{
+			settings.StructTagCamelCase:  test.camel,
+			settings.StructTagPascalCase: test.pascal,
+			settings.StructTagSnakeCase:  test.snake,
+			settings.StructTagKebabCase:  test.kebab,
+			settings.StructTagKeep:       test.keep,
+		} {
+			if got := transformFieldName(test.name, transform); got != want {
+				t.Errorf("transformFieldName(%q, %s) = %q, want %q", test.name, transform, got, want)
+			}
+		}
+	}
+}
@@ -81,11 +81,46 @@ func insertDeclsAfter(ctx context.Context, snapshot *cache.Snapshot, mp *metadat
 		return nil, nil, bug.Errorf("can't find metadata for file %s among dependencies of %s", declPGF.URI, mp)
 	}
 
-	// Build import environment for the declaring file.
+	// Compute insertion point for new declarations:
+	// after the top-level declaration enclosing the (package-level) type.
+	insertOffset, err := safetoken.Offset(declPGF.Tok, declPGF.File.End())
+	if err != nil {
+		return nil, nil, bug.Errorf("internal error: end position outside file bounds: %v", err)
+	}
+	symOffset, err := safetoken.Offset(fset.File(sym.Pos()), sym.Pos())
+	if err != nil {
+		return nil, nil, bug.Errorf("internal error: finding type decl offset: %v", err)
+	}
+	for _, decl := range declPGF.File.Decls {
+		declEndOffset, err := safetoken.Offset(declPGF.Tok, decl.End())
+		if err != nil {
+			return nil, nil, bug.Errorf("internal error: finding decl offset: %v", err)
+		}
+		if declEndOffset > symOffset {
+			insertOffset = declEndOffset
+			break
+		}
+	}
+
+	diffs, err := insertDecls(snapshot, declMeta, declPGF, insertOffset, emit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokeninternal.FileSetFor(declPGF.Tok), // edits use declPGF.Tok
+		&analysis.SuggestedFix{TextEdits: diffToTextEdits(declPGF.Tok, diffs)},
+		nil
+}
+
+// insertDecls calls the emit function to generate new declarations,
+// respecting the import environment of file pgf of package mp, and
+// splices those declarations into the file at the specified offset,
+// updating imports as needed. It returns the edits to the file.
+func insertDecls(snapshot *cache.Snapshot, mp *metadata.Package, pgf *parsego.File, insertOffset int, emit emitter) ([]diff.Edit, error) {
+	// Build import environment for the file.
 	// (typesinternal.FileQualifier works only for complete
 	// import mappings, and requires types.)
 	importEnv := make(map[ImportPath]string) // value is local name
-	for _, imp := range declPGF.File.Imports {
+	for _, imp := range pgf.File.Imports {
 		importPath := metadata.UnquoteImportPath(imp)
 		var name string
 		if imp.Name != nil {
@@ -98,7 +133,7 @@ func insertDeclsAfter(ctx context.Context, snapshot *cache.Snapshot, mp *metadat
 		} else {
 			// Use the correct name from the metadata of the imported
 			// package---not a guess based on the import path.
-			mp := snapshot.Metadata(declMeta.DepsByImpPath[importPath])
+			mp := snapshot.Metadata(mp.DepsByImpPath[importPath])
 			if mp == nil {
 				continue // can't happen?
 			}
@@ -125,7 +160,7 @@ func insertDeclsAfter(ctx context.Context, snapshot *cache.Snapshot, mp *metadat
 		// TODO(adonovan): don't ignore vendor prefix.
 		//
 		// Ignore the current package import.
-		if pkg.Path() == sym.Pkg().Path() {
+		if pkg.Path() == string(mp.PkgPath) {
 			return ""
 		}
 
@@ -135,8 +170,8 @@ func insertDeclsAfter(ctx context.Context, snapshot *cache.Snapshot, mp *metadat
 			// Insert new import using package's declared name.
 			//
 			// TODO(adonovan): resolve conflict between declared
-			// name and existing file-level (declPGF.File.Imports)
-			// or package-level (sym.Pkg.Scope) decls by
+			// name and existing file-level (pgf.File.Imports)
+			// or package-level (mp's scope) decls by
 			// generating a fresh name.
 			name = pkg.Name()
 			importEnv[importPath] = name
@@ -151,43 +186,21 @@ func insertDeclsAfter(ctx context.Context, snapshot *cache.Snapshot, mp *metadat
 		return name
 	}
 
-	// Compute insertion point for new declarations:
-	// after the top-level declaration enclosing the (package-level) type.
-	insertOffset, err := safetoken.Offset(declPGF.Tok, declPGF.File.End())
-	if err != nil {
-		return nil, nil, bug.Errorf("internal error: end position outside file bounds: %v", err)
-	}
-	symOffset, err := safetoken.Offset(fset.File(sym.Pos()), sym.Pos())
-	if err != nil {
-		return nil, nil, bug.Errorf("internal error: finding type decl offset: %v", err)
-	}
-	for _, decl := range declPGF.File.Decls {
-		declEndOffset, err := safetoken.Offset(declPGF.Tok, decl.End())
-		if err != nil {
-			return nil, nil, bug.Errorf("internal error: finding decl offset: %v", err)
-		}
-		if declEndOffset > symOffset {
-			insertOffset = declEndOffset
-			break
-		}
-	}
-
 	// Splice the new declarations into the file content.
 	var buf bytes.Buffer
-	input := declPGF.Mapper.Content // unfixed content of file
+	input := pgf.Mapper.Content // unfixed content of file
 	buf.Write(input[:insertOffset])
 	buf.WriteByte('\n')
-	err = emit(&buf, qual)
-	if err != nil {
-		return nil, nil, err
+	if err := emit(&buf, qual); err != nil {
+		return nil, err
 	}
 	buf.Write(input[insertOffset:])
 
 	// Re-parse the file.
-	fset = token.NewFileSet()
-	newF, err := parser.ParseFile(fset, declPGF.URI.Path(), buf.Bytes(), parser.ParseComments|parser.SkipObjectResolution)
+	fset := token.NewFileSet()
+	newF, err := parser.ParseFile(fset, pgf.URI.Path(), buf.Bytes(), parser.ParseComments|parser.SkipObjectResolution)
 	if err != nil {
-		return nil, nil, fmt.Errorf("could not reparse file: %w", err)
+		return nil, fmt.Errorf("could not reparse file: %w", err)
 	}
 
 	// Splice the new imports into the syntax tree.
@@ -198,14 +211,11 @@ func insertDeclsAfter(ctx context.Context, snapshot *cache.Snapshot, mp *metadat
 	// Pretty-print.
 	var output bytes.Buffer
 	if err := format.Node(&output, fset, newF); err != nil {
-		return nil, nil, fmt.Errorf("format.Node: %w", err)
+		return nil, fmt.Errorf("format.Node: %w", err)
 	}
 
 	// Report the diff.
-	diffs := diff.Bytes(input, output.Bytes())
-	return tokeninternal.FileSetFor(declPGF.Tok), // edits use declPGF.Tok
-		&analysis.SuggestedFix{TextEdits: diffToTextEdits(declPGF.Tok, diffs)},
-		nil
+	return diff.Bytes(input, output.Bytes()), nil
 }
 
 // diffToTextEdits converts diff (offset-based) edits to analysis (token.Pos) form.
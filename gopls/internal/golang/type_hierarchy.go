@@ -0,0 +1,413 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/tools/go/types/typeutil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/cache/methodsets"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/event"
+)
+
+// This file defines the type hierarchy operations
+// (textDocument/prepareTypeHierarchy, typeHierarchy/supertypes, and
+// typeHierarchy/subtypes).
+//
+// Go has no inheritance, so the hierarchy is derived from two
+// relations:
+//
+//  - implementation: an interface type is a supertype of each type
+//    that implements it (including other interfaces);
+//  - embedding: a struct or interface type is a subtype of each
+//    named type it embeds.
+//
+// As with Implementation, the search for implementations is split into
+// a "local" part within the declaring package, which uses type-checker
+// data structures, and a "global" part across all other packages of
+// the workspace and their dependencies, which uses the methodsets index.
+// The search for embedding types type-checks the reverse dependencies
+// of the declaring package, as rename does.
+
+// PrepareTypeHierarchy returns the TypeHierarchyItem for the named
+// type referenced at the given position, if any.
+func PrepareTypeHierarchy(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pp protocol.Position) ([]protocol.TypeHierarchyItem, error) {
+	ctx, done := event.Start(ctx, "golang.PrepareTypeHierarchy")
+	defer done()
+
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, err
+	}
+	pos, err := pgf.PositionPos(pp)
+	if err != nil {
+		return nil, err
+	}
+
+	_, obj, _ := referencedObject(pkg, pgf, pos)
+	tname := hierarchyTypeName(obj)
+	if tname == nil {
+		return nil, nil
+	}
+	item, err := typeHierarchyItem(ctx, snapshot, pkg, tname)
+	if err != nil {
+		return nil, err
+	}
+	return []protocol.TypeHierarchyItem{item}, nil
+}
+
+// Supertypes returns the types that are supertypes of the type
+// denoted by item: the named types it embeds, and the interfaces it
+// implements.
+func Supertypes(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, item protocol.TypeHierarchyItem) ([]protocol.TypeHierarchyItem, error) {
+	ctx, done := event.Start(ctx, "golang.Supertypes")
+	defer done()
+
+	return relatedTypes(ctx, snapshot, fh, item, true)
+}
+
+// Subtypes returns the types that are subtypes of the type denoted by
+// item: the types that implement it (if it is an interface), and the
+// types that embed it, in its package or any package that imports it.
+func Subtypes(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, item protocol.TypeHierarchyItem) ([]protocol.TypeHierarchyItem, error) {
+	ctx, done := event.Start(ctx, "golang.Subtypes")
+	defer done()
+
+	return relatedTypes(ctx, snapshot, fh, item, false)
+}
+
+// relatedTypes returns the supertypes (if super) or subtypes of the
+// type denoted by item, sorted by location.
+func relatedTypes(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, item protocol.TypeHierarchyItem, super bool) ([]protocol.TypeHierarchyItem, error) {
+	// Type check the declaring package of the item.
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, err
+	}
+	pos, err := pgf.PositionPos(item.SelectionRange.Start)
+	if err != nil {
+		return nil, err
+	}
+	_, obj, _ := referencedObject(pkg, pgf, pos)
+	tname := hierarchyTypeName(obj)
+	if tname == nil {
+		return nil, fmt.Errorf("no type at %s:%v", item.URI, item.SelectionRange.Start)
+	}
+	T := tname.Type()
+
+	var (
+		mu    sync.Mutex
+		items []protocol.TypeHierarchyItem
+		seen  = make(map[protocol.Location]bool)
+	)
+	addItem := func(item protocol.TypeHierarchyItem) {
+		mu.Lock()
+		defer mu.Unlock()
+		loc := protocol.Location{URI: item.URI, Range: item.SelectionRange}
+		if !seen[loc] {
+			seen[loc] = true
+			items = append(items, item)
+		}
+	}
+	addTypeName := func(tname *types.TypeName) error {
+		item, err := typeHierarchyItem(ctx, snapshot, pkg, tname)
+		if err != nil {
+			return err
+		}
+		addItem(item)
+		return nil
+	}
+
+	// Embedding relation.
+	if super {
+		for _, embedded := range embeddedTypeNames(T) {
+			if err := addTypeName(embedded); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		// Search the declaring package and its reverse dependencies
+		// for types that embed T. An unexported type can be embedded
+		// only by its own package and direct importers (through
+		// aliases), whereas an exported one can be embedded, through
+		// aliases, by any transitive importer.
+		declURI := protocol.URIFromPath(safetoken.StartPosition(pkg.FileSet(), tname.Pos()).Filename)
+		rdeps, err := typeCheckReverseDependencies(ctx, snapshot, declURI, tname.Exported())
+		if err != nil {
+			return nil, err
+		}
+		// The packages were type-checked anew, so compare
+		// type names by package path and name, not identity.
+		isT := func(embedded *types.TypeName) bool {
+			return embedded.Pkg().Path() == tname.Pkg().Path() && embedded.Name() == tname.Name()
+		}
+		for _, rdep := range rdeps {
+			scope := rdep.Types().Scope()
+			for _, name := range scope.Names() {
+				cand := hierarchyTypeName(scope.Lookup(name))
+				if cand == nil || cand.Pkg() != rdep.Types() {
+					continue
+				}
+				if slices.ContainsFunc(embeddedTypeNames(cand.Type()), isT) {
+					item, err := typeHierarchyItem(ctx, snapshot, rdep, cand)
+					if err != nil {
+						return nil, err
+					}
+					addItem(item)
+				}
+			}
+		}
+	}
+
+	// Implementation relation.
+	key, hasMethods := methodsets.KeyOf(T)
+	if !hasMethods {
+		// A type with no methods implements only empty interfaces,
+		// and an empty interface is implemented by every type.
+		// Neither is worth reporting.
+		return sortTypeHierarchyItems(items), nil
+	}
+	if !super && !types.IsInterface(T) {
+		return sortTypeHierarchyItems(items), nil // only interfaces have implementations
+	}
+
+	// Local search: package-level types of the declaring package.
+	var msets typeutil.MethodSetCache
+	scope := pkg.Types().Scope()
+	for _, name := range scope.Names() {
+		cand := hierarchyTypeName(scope.Lookup(name))
+		if cand == nil || cand == tname || cand.Pkg() != tname.Pkg() {
+			continue
+		}
+		candType := cand.Type()
+		if msets.MethodSet(methodsets.EnsurePointer(candType)).Len() == 0 {
+			continue // no point reporting that every type satisfies 'any'
+		}
+		var related bool
+		if super {
+			related = types.IsInterface(candType) && implementsIntf(T, candType)
+		} else {
+			related = implementsIntf(candType, T)
+		}
+		if related {
+			if err := addTypeName(cand); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Global search: all other packages in the workspace
+	// and their dependencies.
+	globalMetas, err := snapshot.AllMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	metadata.RemoveIntermediateTestVariants(&globalMetas)
+	var (
+		globalIDs   []PackageID
+		globalPaths []PackagePath
+	)
+	for _, mp := range globalMetas {
+		if mp.PkgPath == PackagePath(tname.Pkg().Path()) {
+			continue // declaring package is handled by local search
+		}
+		globalIDs = append(globalIDs, mp.ID)
+		globalPaths = append(globalPaths, mp.PkgPath)
+	}
+	indexes, err := snapshot.MethodSets(ctx, globalIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("querying method sets: %v", err)
+	}
+	var group errgroup.Group
+	for i, index := range indexes {
+		var results []methodsets.TypeResult
+		if super {
+			results = index.Supertypes(key)
+		} else {
+			results = index.Subtypes(key)
+		}
+		pkgPath := globalPaths[i]
+		for _, res := range results {
+			group.Go(func() error {
+				item, err := indexTypeHierarchyItem(ctx, snapshot, pkgPath, res)
+				if err != nil {
+					return err
+				}
+				addItem(item)
+				return nil
+			})
+		}
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return sortTypeHierarchyItems(items), nil
+}
+
+// hierarchyTypeName returns the declaration of the named
+// (non-alias, non-instantiated) type denoted by obj, or nil if obj
+// does not denote a named type declared in a package.
+func hierarchyTypeName(obj types.Object) *types.TypeName {
+	tname, ok := obj.(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	named, ok := types.Unalias(tname.Type()).(*types.Named)
+	if !ok {
+		return nil // e.g. type parameter, or alias of unnamed type
+	}
+	tname = named.Origin().Obj()
+	if tname.Pkg() == nil {
+		return nil // e.g. error
+	}
+	return tname
+}
+
+// embeddedTypeNames returns the named types embedded in the
+// underlying struct or interface type of T.
+func embeddedTypeNames(T types.Type) []*types.TypeName {
+	var embedded []types.Type
+	switch u := T.Underlying().(type) {
+	case *types.Struct:
+		for field := range u.Fields() {
+			if field.Embedded() {
+				t := field.Type()
+				if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+					t = ptr.Elem()
+				}
+				embedded = append(embedded, t)
+			}
+		}
+	case *types.Interface:
+		for t := range u.EmbeddedTypes() {
+			embedded = append(embedded, t)
+		}
+	}
+	var tnames []*types.TypeName
+	for _, t := range embedded {
+		if named, ok := types.Unalias(t).(*types.Named); ok {
+			if tname := hierarchyTypeName(named.Obj()); tname != nil {
+				tnames = append(tnames, tname)
+			}
+		}
+	}
+	return tnames
+}
+
+// implementsIntf reports whether type x implements interface y,
+// considering the method set of *x if x is a named non-interface type.
+func implementsIntf(x, y types.Type) bool {
+	iface, ok := y.Underlying().(*types.Interface)
+	if !ok {
+		return false
+	}
+	// Generic types are uninstantiated; compare method sets
+	// modulo type parameters, as Implementation does.
+	if isGeneric(x) || isGeneric(y) {
+		var msets typeutil.MethodSetCache
+		if types.IsInterface(x) {
+			// interface/interface: every method of y must be in x.
+			ymset := msets.MethodSet(y)
+			for i := range ymset.Len() {
+				ym := ymset.At(i).Obj().(*types.Func)
+				xobj, _, _ := types.LookupFieldOrMethod(x, false, ym.Pkg(), ym.Name())
+				xm, ok := xobj.(*types.Func)
+				if !ok || !unify(xm.Signature(), ym.Signature()) {
+					return false
+				}
+			}
+			return true
+		}
+		return concreteImplementsIntf(&msets, methodsets.EnsurePointer(x), y)
+	}
+	return types.Implements(methodsets.EnsurePointer(x), iface)
+}
+
+// isGeneric reports whether t is a generic named type.
+func isGeneric(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.TypeParams().Len() > 0
+}
+
+// typeHierarchyItem returns the TypeHierarchyItem for a type
+// declared in pkg or one of its dependencies.
+func typeHierarchyItem(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, tname *types.TypeName) (protocol.TypeHierarchyItem, error) {
+	loc, err := mapPosition(ctx, pkg.FileSet(), snapshot, tname.Pos(), adjustedObjEnd(tname))
+	if err != nil {
+		return protocol.TypeHierarchyItem{}, err
+	}
+	return protocol.TypeHierarchyItem{
+		Name:           tname.Name(),
+		Kind:           typeHierarchyKind(types.IsInterface(tname.Type())),
+		Tags:           []protocol.SymbolTag{},
+		Detail:         fmt.Sprintf("%s • %s", tname.Pkg().Path(), filepath.Base(loc.URI.Path())),
+		URI:            loc.URI,
+		Range:          loc.Range,
+		SelectionRange: loc.Range,
+	}, nil
+}
+
+// indexTypeHierarchyItem returns the TypeHierarchyItem for a type
+// found by a search of the methodsets index of package pkgPath.
+func indexTypeHierarchyItem(ctx context.Context, snapshot *cache.Snapshot, pkgPath PackagePath, res methodsets.TypeResult) (protocol.TypeHierarchyItem, error) {
+	uri := protocol.URIFromPath(res.Location.Filename)
+	fh, err := snapshot.ReadFile(ctx, uri)
+	if err != nil {
+		return protocol.TypeHierarchyItem{}, err // cancelled, perhaps
+	}
+	content, err := fh.Content()
+	if err != nil {
+		return protocol.TypeHierarchyItem{}, err // nonexistent or deleted ("can't happen")
+	}
+	start, end := res.Location.Start, res.Location.End
+	if !(0 <= start && start <= end && end <= len(content)) {
+		return protocol.TypeHierarchyItem{}, fmt.Errorf("invalid location %s:#%d-%d", uri, start, end)
+	}
+	m := protocol.NewMapper(uri, content)
+	rng, err := m.OffsetRange(start, end)
+	if err != nil {
+		return protocol.TypeHierarchyItem{}, err
+	}
+	return protocol.TypeHierarchyItem{
+		Name:           string(content[start:end]),
+		Kind:           typeHierarchyKind(res.IsInterface),
+		Tags:           []protocol.SymbolTag{},
+		Detail:         fmt.Sprintf("%s • %s", pkgPath, filepath.Base(uri.Path())),
+		URI:            uri,
+		Range:          rng,
+		SelectionRange: rng,
+	}, nil
+}
+
+// typeHierarchyKind returns the symbol kind of a type hierarchy item.
+func typeHierarchyKind(isInterface bool) protocol.SymbolKind {
+	if isInterface {
+		return protocol.Interface
+	}
+	return protocol.Class
+}
+
+// sortTypeHierarchyItems sorts items by location.
+func sortTypeHierarchyItems(items []protocol.TypeHierarchyItem) []protocol.TypeHierarchyItem {
+	sort.Slice(items, func(i, j int) bool {
+		x := protocol.Location{URI: items[i].URI, Range: items[i].SelectionRange}
+		y := protocol.Location{URI: items[j].URI, Range: items[j].SelectionRange}
+		return protocol.CompareLocation(x, y) < 0
+	})
+	return items
+}
@@ -0,0 +1,298 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/event"
+	"golang.org/x/tools/internal/gocommand"
+)
+
+// directives are the go.mod directives offered by completion at the
+// start of a line.
+var directives = []string{"go", "toolchain", "module", "require", "replace", "exclude", "retract", "godebug", "tool", "ignore"}
+
+// Completion returns completions at the cursor position in a go.mod
+// file: directive names; module paths and versions in require,
+// replace, and exclude directives; versions of the current module in
+// retract directives; and versions in go and toolchain directives.
+//
+// Module paths are found in the module cache, and in GOPROXY if it
+// is a file:// URL. Versions are found in the module cache and in the
+// proxies of GOPROXY (see [cache.Snapshot.ModuleProxyVersions]).
+func Completion(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, position protocol.Position) (*protocol.CompletionList, error) {
+	ctx, done := event.Start(ctx, "mod.Completion")
+	defer done()
+
+	// Work on the text of the file, rather than its syntax tree,
+	// as the line being edited is often incomplete.
+	content, err := fh.Content()
+	if err != nil {
+		return nil, err
+	}
+	mapper := protocol.NewMapper(fh.URI(), content)
+	cursor, err := mapper.PositionOffset(position)
+	if err != nil {
+		return nil, fmt.Errorf("computing cursor offset: %w", err)
+	}
+	lineStart := strings.LastIndexByte(string(content[:cursor]), '\n') + 1
+	line := string(content[lineStart:cursor])
+	if strings.Contains(line, "//") {
+		return &protocol.CompletionList{}, nil // in a comment
+	}
+
+	// Find the directive and its arguments before the cursor.
+	verb := blockVerb(string(content[:lineStart]))
+	args := strings.Fields(line)
+	if verb == "" && len(args) > 0 && (len(args) > 1 || endsInSpace(line)) {
+		verb, args = args[0], args[1:]
+	}
+	var word string // the partial argument at the cursor
+	if len(args) > 0 && !endsInSpace(line) {
+		word, args = args[len(args)-1], args[:len(args)-1]
+	}
+
+	env := snapshot.View().Folder().Env
+	var (
+		candidates []string
+		kind       = protocol.ValueCompletion
+	)
+	switch verb {
+	case "":
+		candidates, kind = directives, protocol.KeywordCompletion
+
+	case "require", "exclude", "replace":
+		if i := slices.Index(args, "=>"); i >= 0 {
+			// The replacement of a replace directive.
+			args = args[i+1:]
+			if len(args) > 0 && modfile.IsDirectoryPath(args[0]) {
+				break // no version
+			}
+		}
+		switch len(args) {
+		case 0:
+			candidates, kind = modulePaths(env, word), protocol.ModuleCompletion
+		case 1:
+			candidates = moduleVersions(ctx, snapshot, args[0])
+		}
+
+	case "retract":
+		word = strings.TrimLeft(word, "[")
+		word = strings.TrimRight(word, ",]")
+		if path := modfile.ModulePath(content); path != "" {
+			candidates = moduleVersions(ctx, snapshot, path)
+		}
+
+	case "go":
+		candidates = goVersions(env)
+
+	case "toolchain":
+		candidates = toolchainVersions(ctx, snapshot)
+	}
+
+	// Replace the partial argument with each matching candidate.
+	rng, err := mapper.OffsetRange(cursor-len(word), cursor)
+	if err != nil {
+		return nil, err
+	}
+	items := []protocol.CompletionItem{} // must be a slice
+	for i, c := range candidates {
+		if !strings.HasPrefix(c, word) {
+			continue
+		}
+		items = append(items, protocol.CompletionItem{
+			Label:    c,
+			Kind:     kind,
+			SortText: fmt.Sprintf("%05d", i), // preserve order, e.g. newest version first
+			TextEdit: &protocol.Or_CompletionItem_textEdit{Value: protocol.TextEdit{
+				Range:   rng,
+				NewText: c,
+			}},
+		})
+	}
+	return &protocol.CompletionList{Items: items}, nil
+}
+
+// blockVerbRe matches the first line of a directive block, such as "require (".
+var blockVerbRe = regexp.MustCompile(`^\s*(\w+)\s*\(\s*(//.*)?$`)
+
+// blockVerb returns the verb of the directive block, if any, that
+// encloses the end of the text before a line.
+func blockVerb(before string) string {
+	lines := strings.Split(before, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, ")") {
+			return ""
+		}
+		if m := blockVerbRe.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+func endsInSpace(line string) bool {
+	return line != "" && (line[len(line)-1] == ' ' || line[len(line)-1] == '\t')
+}
+
+// moduleDirs returns the directories that hold module information in
+// the layout of a module proxy: the download cache of GOMODCACHE,
+// and the directories of file:// URLs in GOPROXY.
+func moduleDirs(env cache.GoEnv) []string {
+	var dirs []string
+	if env.GOMODCACHE != "" {
+		dirs = append(dirs, filepath.Join(env.GOMODCACHE, "cache", "download"))
+	}
+	for _, proxy := range proxies(env) {
+		if u, err := url.Parse(proxy); err == nil && u.Scheme == "file" {
+			dirs = append(dirs, filepath.FromSlash(u.Path))
+		}
+	}
+	return dirs
+}
+
+// proxies returns the URLs of the proxies in GOPROXY, in order.
+func proxies(env cache.GoEnv) []string {
+	var urls []string
+	for _, proxy := range strings.FieldsFunc(env.GOPROXY, func(r rune) bool { return r == ',' || r == '|' }) {
+		switch proxy {
+		case "off":
+			return urls
+		case "direct", "noproxy":
+			continue
+		}
+		urls = append(urls, proxy)
+	}
+	return urls
+}
+
+// modulePaths returns the paths of the modules with the specified
+// prefix in the module cache and file:// proxies, sorted.
+func modulePaths(env cache.GoEnv, prefix string) []string {
+	// Stop traversing once we've seen 10k directories to stay responsive.
+	const numSeenBound = 10000
+	var (
+		paths       []string
+		numSeen     int
+		stopWalking = errors.New("hit numSeenBound")
+	)
+	for _, root := range moduleDirs(env) {
+		filepath.WalkDir(root, func(dir string, entry fs.DirEntry, err error) error {
+			if err != nil || !entry.IsDir() {
+				return nil
+			}
+			if numSeen++; numSeen > numSeenBound {
+				return stopWalking
+			}
+			if dir == root {
+				return nil
+			}
+			rel, err := filepath.Rel(root, dir)
+			if err != nil {
+				return filepath.SkipDir
+			}
+			if entry.Name() == "@v" {
+				// The parent is a module.
+				if path, err := module.UnescapePath(filepath.ToSlash(filepath.Dir(rel))); err == nil && strings.HasPrefix(path, prefix) {
+					paths = append(paths, path)
+				}
+				return filepath.SkipDir
+			}
+			// Prune directories that cannot contain a match.
+			path, err := module.UnescapePath(filepath.ToSlash(rel))
+			if err != nil || !strings.HasPrefix(path, prefix) && !strings.HasPrefix(prefix, path+"/") {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+	}
+	slices.Sort(paths)
+	return slices.Compact(paths)
+}
+
+// moduleVersions returns the known versions of the module with the
+// specified path, newest first, from the module cache and GOPROXY.
+func moduleVersions(ctx context.Context, snapshot *cache.Snapshot, path string) []string {
+	escPath, err := module.EscapePath(path)
+	if err != nil {
+		return nil
+	}
+	var versions []string
+
+	// Versions in the download cache have .info files.
+	if gomodcache := snapshot.View().Folder().Env.GOMODCACHE; gomodcache != "" {
+		dir := filepath.Join(gomodcache, "cache", "download", filepath.FromSlash(escPath), "@v")
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			if name, ok := strings.CutSuffix(entry.Name(), ".info"); ok {
+				if v, err := module.UnescapeVersion(name); err == nil {
+					versions = append(versions, v)
+				}
+			}
+		}
+	}
+
+	proxyVersions, err := snapshot.ModuleProxyVersions(ctx, path)
+	if err != nil {
+		event.Error(ctx, "listing module versions", err)
+	}
+	versions = append(versions, proxyVersions...)
+
+	versions = slices.DeleteFunc(versions, func(v string) bool { return !semver.IsValid(v) })
+	slices.SortFunc(versions, func(x, y string) int { return semver.Compare(y, x) })
+	return slices.Compact(versions)
+}
+
+// goVersions returns the versions offered for a go directive, newest
+// first: the version of the local Go toolchain, and each earlier Go
+// 1.N language version that supports modules.
+func goVersions(env cache.GoEnv) []string {
+	var versions []string
+	if v := strings.TrimPrefix(gocommand.ParseGoVersionOutput(env.GoVersionOutput), "go"); v != "" {
+		versions = append(versions, v)
+	}
+	for minor := env.GoVersion; minor >= 11; minor-- {
+		versions = append(versions, fmt.Sprintf("1.%d", minor))
+	}
+	return slices.Compact(versions)
+}
+
+// toolchainVersions returns the versions offered for a toolchain
+// directive, newest first: the version of the local Go toolchain, and
+// the versions of the golang.org/toolchain module for this platform.
+func toolchainVersions(ctx context.Context, snapshot *cache.Snapshot) []string {
+	env := snapshot.View().Folder().Env
+	var versions []string
+	if v := gocommand.ParseGoVersionOutput(env.GoVersionOutput); strings.HasPrefix(v, "go") {
+		versions = append(versions, v)
+	}
+	// Toolchain module versions have the form v0.0.1-go1.22.0.linux-amd64.
+	suffix := "." + env.GOOS + "-" + env.GOARCH
+	for _, v := range moduleVersions(ctx, snapshot, "golang.org/toolchain") {
+		if _, goVersion, ok := strings.Cut(v, "-"); ok && strings.HasSuffix(goVersion, suffix) {
+			if goVersion = strings.TrimSuffix(goVersion, suffix); !slices.Contains(versions, goVersion) {
+				versions = append(versions, goVersion)
+			}
+		}
+	}
+	return versions
+}
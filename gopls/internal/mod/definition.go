@@ -0,0 +1,116 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mod
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/event"
+)
+
+// Definition returns the location of the go.mod file of the module
+// required or replaced by the directive at the cursor position: the
+// go.mod file in a local replacement directory, or else in the module
+// cache.
+func Definition(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, position protocol.Position) ([]protocol.Location, error) {
+	ctx, done := event.Start(ctx, "mod.Definition")
+	defer done()
+
+	pm, err := snapshot.ParseMod(ctx, fh)
+	if err != nil {
+		return nil, fmt.Errorf("getting modfile handle: %w", err)
+	}
+	if pm.File == nil {
+		return nil, nil
+	}
+	offset, err := pm.Mapper.PositionOffset(position)
+	if err != nil {
+		return nil, fmt.Errorf("computing cursor position: %w", err)
+	}
+	in := func(syntax *modfile.Line) bool {
+		return syntax.Start.Byte <= offset && offset <= syntax.End.Byte
+	}
+
+	// Find the module at the cursor, and apply any replacement.
+	var target module.Version
+	for _, req := range pm.File.Require {
+		if in(req.Syntax) {
+			target = replacement(pm.File, req.Mod)
+		}
+	}
+	for _, rep := range pm.File.Replace {
+		if in(rep.Syntax) {
+			// The module on the side of the arrow of the cursor.
+			target = rep.New
+			line := string(pm.Mapper.Content[rep.Syntax.Start.Byte:rep.Syntax.End.Byte])
+			if arrow := strings.Index(line, "=>"); arrow >= 0 && offset < rep.Syntax.Start.Byte+arrow {
+				target = rep.Old
+			}
+		}
+	}
+	if target.Path == "" {
+		return nil, nil // not on a require or replace directive
+	}
+
+	goMod, err := moduleGoMod(snapshot.View().Folder().Env, pm.URI.DirPath(), target)
+	if err != nil {
+		return nil, err
+	}
+	return []protocol.Location{{URI: protocol.URIFromPath(goMod)}}, nil
+}
+
+// replacement returns the replacement of module m in the go.mod
+// file f, or m itself if it is not replaced.
+func replacement(f *modfile.File, m module.Version) module.Version {
+	for _, rep := range f.Replace {
+		if rep.Old.Path == m.Path && (rep.Old.Version == "" || rep.Old.Version == m.Version) {
+			return rep.New
+		}
+	}
+	return m
+}
+
+// moduleGoMod returns the path of the go.mod file of module m: in its
+// directory, if it is a local path (relative to dir), or else in its
+// extracted directory or download directory in the module cache.
+func moduleGoMod(env cache.GoEnv, dir string, m module.Version) (string, error) {
+	if m.Version == "" {
+		if !modfile.IsDirectoryPath(m.Path) {
+			return "", fmt.Errorf("no version for module %s", m.Path)
+		}
+		path := filepath.FromSlash(m.Path)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		return filepath.Join(path, "go.mod"), nil
+	}
+
+	escPath, err := module.EscapePath(m.Path)
+	if err != nil {
+		return "", err
+	}
+	escVersion, err := module.EscapeVersion(m.Version)
+	if err != nil {
+		return "", err
+	}
+	for _, goMod := range []string{
+		filepath.Join(env.GOMODCACHE, filepath.FromSlash(escPath)+"@"+escVersion, "go.mod"),
+		filepath.Join(env.GOMODCACHE, "cache", "download", filepath.FromSlash(escPath), "@v", escVersion+".mod"),
+	} {
+		if _, err := os.Stat(goMod); err == nil {
+			return goMod, nil
+		}
+	}
+	return "", fmt.Errorf("module %s not found in the module cache", m)
+}
@@ -36,17 +36,21 @@ const (
 	DiagnoseFiles           Command = "gopls.diagnose_files"
 	Doc                     Command = "gopls.doc"
 	EditGoDirective         Command = "gopls.edit_go_directive"
+	ExtractInterface        Command = "gopls.extract_interface"
 	ExtractToNewFile        Command = "gopls.extract_to_new_file"
 	FetchVulncheckResult    Command = "gopls.fetch_vulncheck_result"
 	FreeSymbols             Command = "gopls.free_symbols"
 	GCDetails               Command = "gopls.gc_details"
 	Generate                Command = "gopls.generate"
+	GenerateStringer        Command = "gopls.generate_stringer"
 	GoGetPackage            Command = "gopls.go_get_package"
 	ListImports             Command = "gopls.list_imports"
 	ListKnownPackages       Command = "gopls.list_known_packages"
 	MaybePromptForTelemetry Command = "gopls.maybe_prompt_for_telemetry"
 	MemStats                Command = "gopls.mem_stats"
+	ModifyTags              Command = "gopls.modify_tags"
 	Modules                 Command = "gopls.modules"
+	MoveToPackage           Command = "gopls.move_to_package"
 	PackageSymbols          Command = "gopls.package_symbols"
 	Packages                Command = "gopls.packages"
 	RegenerateCgo           Command = "gopls.regenerate_cgo"
@@ -55,6 +59,7 @@ const (
 	RunGoWorkCommand        Command = "gopls.run_go_work_command"
 	RunGovulncheck          Command = "gopls.run_govulncheck"
 	RunTests                Command = "gopls.run_tests"
+	SafeDelete              Command = "gopls.safe_delete"
 	ScanImports             Command = "gopls.scan_imports"
 	StartDebugging          Command = "gopls.start_debugging"
 	StartProfile            Command = "gopls.start_profile"
@@ -81,17 +86,21 @@ var Commands = []Command{
 	DiagnoseFiles,
 	Doc,
 	EditGoDirective,
+	ExtractInterface,
 	ExtractToNewFile,
 	FetchVulncheckResult,
 	FreeSymbols,
 	GCDetails,
 	Generate,
+	GenerateStringer,
 	GoGetPackage,
 	ListImports,
 	ListKnownPackages,
 	MaybePromptForTelemetry,
 	MemStats,
+	ModifyTags,
 	Modules,
+	MoveToPackage,
 	PackageSymbols,
 	Packages,
 	RegenerateCgo,
@@ -100,6 +109,7 @@ var Commands = []Command{
 	RunGoWorkCommand,
 	RunGovulncheck,
 	RunTests,
+	SafeDelete,
 	ScanImports,
 	StartDebugging,
 	StartProfile,
@@ -189,6 +199,12 @@ func Dispatch(ctx context.Context, params *protocol.ExecuteCommandParams, s Inte
 			return nil, err
 		}
 		return nil, s.EditGoDirective(ctx, a0)
+	case ExtractInterface:
+		var a0 ExtractInterfaceArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return s.ExtractInterface(ctx, a0)
 	case ExtractToNewFile:
 		var a0 protocol.Location
 		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
@@ -220,6 +236,12 @@ func Dispatch(ctx context.Context, params *protocol.ExecuteCommandParams, s Inte
 			return nil, err
 		}
 		return nil, s.Generate(ctx, a0)
+	case GenerateStringer:
+		var a0 GenerateStringerArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return s.GenerateStringer(ctx, a0)
 	case GoGetPackage:
 		var a0 GoGetPackageArgs
 		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
@@ -242,12 +264,24 @@ func Dispatch(ctx context.Context, params *protocol.ExecuteCommandParams, s Inte
 		return nil, s.MaybePromptForTelemetry(ctx)
 	case MemStats:
 		return s.MemStats(ctx)
+	case ModifyTags:
+		var a0 ModifyTagsArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return s.ModifyTags(ctx, a0)
 	case Modules:
 		var a0 ModulesArgs
 		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
 			return nil, err
 		}
 		return s.Modules(ctx, a0)
+	case MoveToPackage:
+		var a0 MoveToPackageArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return s.MoveToPackage(ctx, a0)
 	case PackageSymbols:
 		var a0 PackageSymbolsArgs
 		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
@@ -296,6 +330,12 @@ func Dispatch(ctx context.Context, params *protocol.ExecuteCommandParams, s Inte
 			return nil, err
 		}
 		return nil, s.RunTests(ctx, a0)
+	case SafeDelete:
+		var a0 SafeDeleteArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return s.SafeDelete(ctx, a0)
 	case ScanImports:
 		return nil, s.ScanImports(ctx)
 	case StartDebugging:
@@ -450,6 +490,14 @@ func NewEditGoDirectiveCommand(title string, a0 EditGoDirectiveArgs) *protocol.C
 	}
 }
 
+func NewExtractInterfaceCommand(title string, a0 ExtractInterfaceArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   ExtractInterface.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
 func NewExtractToNewFileCommand(title string, a0 protocol.Location) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
@@ -490,6 +538,14 @@ func NewGenerateCommand(title string, a0 GenerateArgs) *protocol.Command {
 	}
 }
 
+func NewGenerateStringerCommand(title string, a0 GenerateStringerArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   GenerateStringer.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
 func NewGoGetPackageCommand(title string, a0 GoGetPackageArgs) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
@@ -530,6 +586,14 @@ func NewMemStatsCommand(title string) *protocol.Command {
 	}
 }
 
+func NewModifyTagsCommand(title string, a0 ModifyTagsArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   ModifyTags.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
 func NewModulesCommand(title string, a0 ModulesArgs) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
@@ -538,6 +602,14 @@ func NewModulesCommand(title string, a0 ModulesArgs) *protocol.Command {
 	}
 }
 
+func NewMoveToPackageCommand(title string, a0 MoveToPackageArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   MoveToPackage.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
 func NewPackageSymbolsCommand(title string, a0 PackageSymbolsArgs) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
@@ -602,6 +674,14 @@ func NewRunTestsCommand(title string, a0 RunTestsArgs) *protocol.Command {
 	}
 }
 
+func NewSafeDeleteCommand(title string, a0 SafeDeleteArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   SafeDelete.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
 func NewScanImportsCommand(title string) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
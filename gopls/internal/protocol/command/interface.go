@@ -153,6 +153,43 @@ type Interface interface {
 	// Used by the code action of the same name.
 	ExtractToNewFile(context.Context, protocol.Location) error
 
+	// ExtractInterface: Extract an interface from a concrete type
+	//
+	// This command declares an interface type whose methods are
+	// those of a concrete type, and optionally changes the types
+	// of selected parameters to the new interface.
+	// Used by the code action of the same name.
+	ExtractInterface(context.Context, ExtractInterfaceArgs) (*protocol.WorkspaceEdit, error)
+
+	// MoveToPackage: Move declarations to another package
+	//
+	// This command moves the selected top-level declarations to a
+	// new file in another package, and updates all references to
+	// them in the workspace.
+	MoveToPackage(context.Context, MoveToPackageArgs) (*protocol.WorkspaceEdit, error)
+
+	// SafeDelete: Delete an unreferenced declaration
+	//
+	// This command deletes the selected function, method, type,
+	// struct field, or constant, after checking that nothing in the
+	// workspace refers to it. Used by the code action of the same name.
+	SafeDelete(context.Context, SafeDeleteArgs) (*protocol.WorkspaceEdit, error)
+
+	// ModifyTags: Add or remove struct tags
+	//
+	// This command adds or removes keys and options in the struct tags
+	// of the fields of the selected struct type, or of the selected
+	// fields. Used by the refactor.rewrite.{add,remove}Tags code actions.
+	ModifyTags(context.Context, ModifyTagsArgs) (*protocol.WorkspaceEdit, error)
+
+	// GenerateStringer: Generate a String method
+	//
+	// This command generates a String method for the selected named
+	// integer type from its constants, as the stringer command does,
+	// in the file <type>_string.go. Used by the source.generateStringer
+	// code action.
+	GenerateStringer(context.Context, GenerateStringerArgs) (*protocol.WorkspaceEdit, error)
+
 	// StartDebugging: Start the gopls debug server
 	//
 	// Start the gopls debug server if it isn't running, and return the debug
@@ -238,8 +275,11 @@ type Interface interface {
 
 	// ChangeSignature: Perform a "change signature" refactoring
 	//
-	// This command is experimental, currently only supporting parameter removal.
-	// Its signature will certainly change in the future (pun intended).
+	// This command is experimental: it adds, removes, renames, and
+	// reorders the parameters and results of a function, updating its
+	// calls and, optionally, the corresponding methods of related
+	// interfaces and types.
+	// Its signature may change in the future (pun intended).
 	ChangeSignature(context.Context, ChangeSignatureArgs) (*protocol.WorkspaceEdit, error)
 
 	// DiagnoseFiles: Cause server to publish diagnostics for the specified files.
@@ -583,14 +623,35 @@ type AddTelemetryCountersArgs struct {
 //   - If the element is an integer, it references a positional parameter in the
 //     old signature.
 //   - If the element is a string, it is parsed as a new field to add.
+//   - If the element is an object, its OldIndex or NewField (see above) is
+//     qualified by a Name, to rename a field of the old signature, or by a
+//     Default, the value of a new field.
+//
+// The Default of a new parameter is the argument expression added to each
+// call; the Default of a new result is the value added to each return
+// statement. Both default to the zero value of the field's type. Types
+// and Default expressions are interpreted in the scope of the function
+// declaration, so they may refer only to packages imported by its file.
 //
 // Suppose we have a function `F(a, b int) (string, error)`. Here are some
 // examples of refactoring this signature in practice, eliding the 'Location'
 // and 'ResolveEdits' fields.
 //   - `{ "NewParams": [0], "NewResults": [0, 1] }` removes the second parameter
 //   - `{ "NewParams": [1, 0], "NewResults": [0, 1] }` flips the parameter order
-//   - `{ "NewParams": [0, 1, "a int"], "NewResults": [0, 1] }` adds a new field
-//   - `{ "NewParams": [1, 2], "NewResults": [1] }` drops the `error` result
+//   - `{ "NewParams": [0, 1, "c int"], "NewResults": [0, 1] }` adds a new field
+//   - `{ "NewParams": [0, 1, { "NewField": "c int", "Default": "42" }], "NewResults": [0, 1] }`
+//     adds a new field, passing 42 at each call
+//   - `{ "NewParams": [{ "OldIndex": 0, "Name": "x" }, 1], "NewResults": [0, 1] }`
+//     renames the first parameter
+//   - `{ "NewParams": [0, 1], "NewResults": [1] }` drops the `string` result
+//
+// If the function is a method and UpdateInterfaces is set, the
+// corresponding methods of the interfaces it implements, and of the other
+// types that implement them, are changed too. In that case, calls that
+// cannot be updated, such as dynamic calls through an interface, are
+// reported as conflicts, and no changes are made. Otherwise, only the
+// method itself and its static calls are changed. In either case, a
+// reference to the function that is not a call is a conflict.
 type ChangeSignatureArgs struct {
 	// Location is any range inside the function signature. By convention, this
 	// is the same location provided in the codeAction request.
@@ -604,17 +665,24 @@ type ChangeSignatureArgs struct {
 	// NewResults describes results of the new signature (see above).
 	// An int value references a result in the old signature by index.
 	// A string value describes a new result field (e.g. "err error").
+	// If NewResults is omitted, the results are unchanged.
 	NewResults []ChangeSignatureParam
 
+	// UpdateInterfaces causes the corresponding methods of related
+	// interfaces and types to be changed too (see above).
+	UpdateInterfaces bool
+
 	// Whether to resolve and return the edits.
 	ResolveEdits bool
 }
 
 // ChangeSignatureParam implements the API described in the doc string of
-// [ChangeSignatureArgs]: a union of JSON int | string.
+// [ChangeSignatureArgs]: a union of JSON int | string | object.
 type ChangeSignatureParam struct {
 	OldIndex int
 	NewField string
+	Name     string // new name of an old field
+	Default  string // value of a new field
 }
 
 func (a *ChangeSignatureParam) UnmarshalJSON(b []byte) error {
@@ -628,16 +696,161 @@ func (a *ChangeSignatureParam) UnmarshalJSON(b []byte) error {
 		a.OldIndex = i
 		return nil
 	}
-	return fmt.Errorf("must be int or string")
+	type object ChangeSignatureParam // (no UnmarshalJSON method)
+	if err := json.Unmarshal(b, (*object)(a)); err == nil {
+		return nil
+	}
+	return fmt.Errorf("must be int, string, or object")
 }
 
 func (a ChangeSignatureParam) MarshalJSON() ([]byte, error) {
+	if a.Name != "" || a.Default != "" {
+		type object ChangeSignatureParam // (no MarshalJSON method)
+		return json.Marshal(object(a))
+	}
 	if a.NewField != "" {
 		return json.Marshal(a.NewField)
 	}
 	return json.Marshal(a.OldIndex)
 }
 
+// ExtractInterfaceArgs specifies an "extract interface" refactoring.
+//
+// The concrete type is indicated by Location, which is either the name
+// of a type declaration, or a selection of one or more complete method
+// declarations of the same type. The new interface declares the
+// exported methods named by Methods, or if Methods is empty, the
+// selected methods or all exported methods of the type.
+//
+// The interface is declared in the file Dest, which may belong to a
+// package that uses the concrete type: after the type declaration if
+// Dest declares the type, otherwise at the end of the file.
+//
+// Each element of UseSites is the location of a parameter, in a
+// function of the package of Dest, whose type is T or *T. Its type is
+// changed to the new interface, if that type implements the interface
+// and the function uses the parameter only to call methods of the
+// interface.
+type ExtractInterfaceArgs struct {
+	// Location of the type name or the selected methods,
+	// as passed to CodeAction.
+	Location protocol.Location
+
+	// Name of the new interface. By default, it is the name of the
+	// concrete type with the suffix "Interface".
+	Name string
+
+	// Methods lists the names of the methods of the interface.
+	Methods []string
+
+	// Dest is the file in which to declare the interface.
+	// By default, it is the file that declares the concrete type.
+	Dest protocol.DocumentURI
+
+	// UseSites are the locations of parameters whose type to change
+	// to the new interface.
+	UseSites []protocol.Location
+
+	// Whether to resolve and return the edits.
+	ResolveEdits bool
+}
+
+// MoveToPackageArgs specifies a "move to package" refactoring.
+//
+// The declarations are indicated by Location, which must select one
+// or more complete top-level declarations, as for the "Extract
+// declarations to new file" code action. They are moved to a new
+// file in the package in directory Dest, which is created if it does
+// not already contain a package.
+//
+// Unexported package-level symbols that are referenced across the
+// new package boundary are exported. The move is refused if it would
+// create an import cycle, if the moved declarations and those that
+// remain would refer to each other's unexported fields or methods, or
+// if a method would be separated from its receiver type.
+type MoveToPackageArgs struct {
+	// Location of the selected declarations.
+	Location protocol.Location
+
+	// Dest is the directory of the destination package.
+	Dest protocol.DocumentURI
+
+	// Whether to resolve and return the edits.
+	ResolveEdits bool
+}
+
+// SafeDeleteArgs specifies a declaration to be deleted by the
+// gopls.safe_delete command.
+//
+// The deletion is refused if the declaration is referenced, directly
+// or, for a method, through an interface it may be required to
+// satisfy. If DeleteTests is set, references from test functions are
+// permitted, and those functions are deleted too.
+type SafeDeleteArgs struct {
+	// Location of the name of the declaration.
+	Location protocol.Location
+
+	// Whether to also delete the test functions that refer to it.
+	DeleteTests bool
+
+	// Whether to resolve and return the edits.
+	ResolveEdits bool
+}
+
+// ModifyTagsArgs specifies changes to the struct tags of fields for
+// the gopls.modify_tags command.
+//
+// Keys are added only to named, exported fields that do not already
+// have them (unless Overwrite is set). Options apply only to keys
+// that are present after keys have been added and removed.
+type ModifyTagsArgs struct {
+	// Location of the struct type or of the selected fields. If the
+	// range is empty or selects no fields, all fields are affected.
+	Location protocol.Location
+
+	// Keys to add, such as "json".
+	Add []string
+
+	// Options to add, such as "json=omitempty" for the json key,
+	// or "omitempty" for every key.
+	AddOptions []string
+
+	// Keys to remove.
+	Remove []string
+
+	// Options to remove, in the same form as AddOptions.
+	RemoveOptions []string
+
+	// Transform determines the value of each added key from the
+	// field name: "camelcase", "pascalcase", "snakecase", "kebabcase",
+	// or "keep". If empty, the structTagTransform setting is used.
+	Transform string
+
+	// Whether to replace the values of existing keys in Add,
+	// preserving their options.
+	Overwrite bool
+
+	// Whether to resolve and return the edits.
+	ResolveEdits bool
+}
+
+// GenerateStringerArgs specifies a String method to generate, with
+// the options of the -trimprefix and -linecomment flags of stringer.
+type GenerateStringerArgs struct {
+	// Location of the name of the type.
+	Location protocol.Location
+
+	// Prefix to trim from the constant names.
+	TrimPrefix string
+
+	// Whether to use the line comment of each constant, if any,
+	// instead of its name.
+	LineComment bool
+
+	// Whether to resolve and return the edits.
+	ResolveEdits bool
+}
+
 // DiagnoseFilesArgs specifies a set of files for which diagnostics are wanted.
 type DiagnoseFilesArgs struct {
 	Files []protocol.DocumentURI
@@ -79,7 +79,7 @@ func propStar(name string, t NameType, gotype string) (omitempty, indirect bool)
 		case nothing:
 			indirect, omitempty = false, false
 		case wantStar:
-			indirect, omitempty = false, false
+			indirect, omitempty = true, false
 		case wantOpt:
 			indirect, omitempty = false, true
 		case wantOptStar:
@@ -50,6 +50,9 @@ var goplsStar = map[prop]int{
 	{"WorkDoneProgressParams", "workDoneToken"}:               wantOpt,     // test failures
 	{"WorkspaceClientCapabilities", "didChangeConfiguration"}: wantOpt,     // A.B.C.D
 	{"WorkspaceClientCapabilities", "didChangeWatchedFiles"}:  wantOpt,     // A.B.C.D
+
+	{"WorkspaceFullDocumentDiagnosticReport", "version"}:      wantStar, // null if not open
+	{"WorkspaceUnchangedDocumentDiagnosticReport", "version"}: wantStar, // null if not open
 }
 
 // keep track of which entries in goplsStar are used
@@ -73,6 +76,10 @@ var renameProp = map[prop]string{
 	{"RelatedFullDocumentDiagnosticReport", "relatedDocuments"}:      "map[DocumentURI]any",
 	{"RelatedUnchangedDocumentDiagnosticReport", "relatedDocuments"}: "map[DocumentURI]any",
 
+	// The documents served by workspace/textDocumentContent are not files.
+	{"TextDocumentContentParams", "uri"}:        "URI",
+	{"TextDocumentContentRefreshParams", "uri"}: "URI",
+
 	// PJW: this one is tricky.
 	{"ServerCapabilities", "codeActionProvider"}: "any",
 
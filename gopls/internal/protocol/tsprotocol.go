@@ -5304,7 +5304,7 @@ type TextDocumentContentOptions struct {
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification#textDocumentContentParams
 type TextDocumentContentParams struct {
 	// The uri of the text document.
-	URI DocumentURI `json:"uri"`
+	URI URI `json:"uri"`
 }
 
 // Parameters for the `workspace/textDocumentContent/refresh` request.
@@ -5315,7 +5315,7 @@ type TextDocumentContentParams struct {
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification#textDocumentContentRefreshParams
 type TextDocumentContentRefreshParams struct {
 	// The uri of the text document to refresh.
-	URI DocumentURI `json:"uri"`
+	URI URI `json:"uri"`
 }
 
 // Text document content provider registration options.
@@ -6091,7 +6091,7 @@ type WorkspaceFullDocumentDiagnosticReport struct {
 	URI DocumentURI `json:"uri"`
 	// The version number for which the diagnostics are reported.
 	// If the document is not marked as open `null` can be provided.
-	Version int32 `json:"version"`
+	Version *int32 `json:"version"`
 	FullDocumentDiagnosticReport
 }
 
@@ -6203,7 +6203,7 @@ type WorkspaceUnchangedDocumentDiagnosticReport struct {
 	URI DocumentURI `json:"uri"`
 	// The version number for which the diagnostics are reported.
 	// If the document is not marked as open `null` can be provided.
-	Version int32 `json:"version"`
+	Version *int32 `json:"version"`
 	UnchangedDocumentDiagnosticReport
 }
 
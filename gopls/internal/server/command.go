@@ -707,16 +707,27 @@ func (c *commandHandler) Doc(ctx context.Context, args command.DocArgs) (protoco
 			return err
 		}
 
+		// Compute package path and optional symbol fragment
+		// (e.g. "#Buffer.Len") from the the selection.
+		pkgpath, fragment, _ := golang.DocFragment(pkg, pgf, start, end)
+
+		// If the client can display documents served by
+		// workspace/textDocumentContent, open the text document,
+		// which works even if the client can't reach the web server.
+		if c.s.Options().TextDocumentContentSupported {
+			result = pkgDocContentURI(deps.snapshot.View().ID(), pkgpath, fragment)
+			if args.ShowDocument {
+				openClientDocument(ctx, c.s.client, result, c.s.Options())
+			}
+			return nil
+		}
+
 		// Start web server.
 		web, err := c.s.getWeb()
 		if err != nil {
 			return err
 		}
 
-		// Compute package path and optional symbol fragment
-		// (e.g. "#Buffer.Len") from the the selection.
-		pkgpath, fragment, _ := golang.DocFragment(pkg, pgf, start, end)
-
 		// Direct the client to open the /pkg page.
 		result = web.PkgURL(deps.snapshot.View().ID(), pkgpath, fragment)
 		if args.ShowDocument {
@@ -1129,6 +1140,100 @@ func (c *commandHandler) ExtractToNewFile(ctx context.Context, args protocol.Loc
 	})
 }
 
+func (c *commandHandler) ExtractInterface(ctx context.Context, args command.ExtractInterfaceArgs) (*protocol.WorkspaceEdit, error) {
+	var result *protocol.WorkspaceEdit
+	err := c.run(ctx, commandConfig{
+		forURI: args.Location.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		pkg, pgf, err := golang.NarrowestPackageForFile(ctx, deps.snapshot, args.Location.URI)
+		if err != nil {
+			return err
+		}
+		changes, err := golang.ExtractInterface(ctx, deps.snapshot, pkg, pgf, args)
+		if err != nil {
+			return err
+		}
+		if args.ResolveEdits {
+			result = protocol.NewWorkspaceEdit(changes...)
+			return nil
+		}
+		return applyChanges(ctx, c.s.client, changes)
+	})
+	return result, err
+}
+
+func (c *commandHandler) MoveToPackage(ctx context.Context, args command.MoveToPackageArgs) (*protocol.WorkspaceEdit, error) {
+	var result *protocol.WorkspaceEdit
+	err := c.run(ctx, commandConfig{
+		forURI: args.Location.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		changes, err := golang.MoveToPackage(ctx, deps.snapshot, deps.fh, args.Location.Range, args.Dest)
+		if err != nil {
+			return err
+		}
+		if args.ResolveEdits {
+			result = protocol.NewWorkspaceEdit(changes...)
+			return nil
+		}
+		return applyChanges(ctx, c.s.client, changes)
+	})
+	return result, err
+}
+
+func (c *commandHandler) SafeDelete(ctx context.Context, args command.SafeDeleteArgs) (*protocol.WorkspaceEdit, error) {
+	var result *protocol.WorkspaceEdit
+	err := c.run(ctx, commandConfig{
+		forURI: args.Location.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		changes, err := golang.SafeDelete(ctx, deps.snapshot, deps.fh, args.Location.Range, args.DeleteTests)
+		if err != nil {
+			return err
+		}
+		if args.ResolveEdits {
+			result = protocol.NewWorkspaceEdit(changes...)
+			return nil
+		}
+		return applyChanges(ctx, c.s.client, changes)
+	})
+	return result, err
+}
+
+func (c *commandHandler) ModifyTags(ctx context.Context, args command.ModifyTagsArgs) (*protocol.WorkspaceEdit, error) {
+	var result *protocol.WorkspaceEdit
+	err := c.run(ctx, commandConfig{
+		forURI: args.Location.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		changes, err := golang.ModifyTags(ctx, deps.snapshot, deps.fh, args)
+		if err != nil {
+			return err
+		}
+		if args.ResolveEdits {
+			result = protocol.NewWorkspaceEdit(changes...)
+			return nil
+		}
+		return applyChanges(ctx, c.s.client, changes)
+	})
+	return result, err
+}
+
+func (c *commandHandler) GenerateStringer(ctx context.Context, args command.GenerateStringerArgs) (*protocol.WorkspaceEdit, error) {
+	var result *protocol.WorkspaceEdit
+	err := c.run(ctx, commandConfig{
+		forURI: args.Location.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		changes, err := golang.GenerateStringer(ctx, deps.snapshot, args)
+		if err != nil {
+			return err
+		}
+		if args.ResolveEdits {
+			result = protocol.NewWorkspaceEdit(changes...)
+			return nil
+		}
+		return applyChanges(ctx, c.s.client, changes)
+	})
+	return result, err
+}
+
 func (c *commandHandler) StartDebugging(ctx context.Context, args command.DebuggingArgs) (result command.DebuggingResult, _ error) {
 	addr := args.Addr
 	if addr == "" {
@@ -1575,6 +1680,13 @@ func openClientEditor(ctx context.Context, cli protocol.Client, loc protocol.Loc
 	showDocumentImpl(ctx, cli, protocol.URI(loc.URI), &loc.Range, opts)
 }
 
+// openClientDocument causes the LSP client to open the specified
+// document, such as one served by workspace/textDocumentContent,
+// in the editor.
+func openClientDocument(ctx context.Context, cli protocol.Client, uri protocol.URI, opts *settings.Options) {
+	showDocumentImpl(ctx, cli, uri, &protocol.Range{}, opts)
+}
+
 func showDocumentImpl(ctx context.Context, cli protocol.Client, url protocol.URI, rangeOpt *protocol.Range, opts *settings.Options) {
 	if !opts.ShowDocumentSupported {
 		return // no op
@@ -1620,16 +1732,7 @@ func (c *commandHandler) ChangeSignature(ctx context.Context, args command.Chang
 			return err
 		}
 
-		// For now, gopls only supports parameter permutation or removal.
-		var perm []int
-		for _, newParam := range args.NewParams {
-			if newParam.NewField != "" {
-				return fmt.Errorf("adding new parameters is currently unsupported")
-			}
-			perm = append(perm, newParam.OldIndex)
-		}
-
-		docedits, err := golang.ChangeSignature(ctx, deps.snapshot, pkg, pgf, args.Location.Range, perm)
+		docedits, err := golang.ChangeSignature(ctx, deps.snapshot, pkg, pgf, args.Location.Range, args.NewParams, args.NewResults, args.UpdateInterfaces)
 		if err != nil {
 			return err
 		}
@@ -1703,6 +1806,10 @@ func (c *commandHandler) Views(ctx context.Context) ([]command.View, error) {
 }
 
 func (c *commandHandler) FreeSymbols(ctx context.Context, viewID string, loc protocol.Location) error {
+	if c.s.Options().TextDocumentContentSupported {
+		openClientDocument(ctx, c.s.client, freeSymbolsContentURI(viewID, loc), c.s.Options())
+		return nil
+	}
 	web, err := c.s.getWeb()
 	if err != nil {
 		return err
@@ -1713,6 +1820,10 @@ func (c *commandHandler) FreeSymbols(ctx context.Context, viewID string, loc pro
 }
 
 func (c *commandHandler) Assembly(ctx context.Context, viewID, packageID, symbol string) error {
+	if c.s.Options().TextDocumentContentSupported {
+		openClientDocument(ctx, c.s.client, assemblyContentURI(viewID, packageID, symbol), c.s.Options())
+		return nil
+	}
 	web, err := c.s.getWeb()
 	if err != nil {
 		return err
@@ -6,6 +6,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -13,6 +14,7 @@ import (
 	"golang.org/x/tools/gopls/internal/golang"
 	"golang.org/x/tools/gopls/internal/golang/completion"
 	"golang.org/x/tools/gopls/internal/label"
+	"golang.org/x/tools/gopls/internal/mod"
 	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/gopls/internal/settings"
 	"golang.org/x/tools/gopls/internal/telemetry"
@@ -42,7 +44,11 @@ func (s *server) Completion(ctx context.Context, params *protocol.CompletionPara
 	case file.Go:
 		candidates, surrounding, err = completion.Completion(ctx, snapshot, fh, params.Position, params.Context)
 	case file.Mod:
-		candidates, surrounding = nil, nil
+		cl, err := mod.Completion(ctx, snapshot, fh, params.Position)
+		if err != nil {
+			break
+		}
+		return cl, nil
 	case file.Work:
 		cl, err := work.Completion(ctx, snapshot, fh, params.Position)
 		if err != nil {
@@ -140,14 +146,15 @@ func toProtocolCompletionItems(candidates []completion.CompletionItem, surroundi
 			continue
 		}
 
-		doc := &protocol.Or_CompletionItem_documentation{
-			Value: protocol.MarkupContent{
-				Kind:  protocol.Markdown,
-				Value: golang.DocCommentToMarkdown(candidate.Documentation, options),
-			},
+		// Documentation that will be computed by a later resolve
+		// request is omitted.
+		var doc *protocol.Or_CompletionItem_documentation
+		if candidate.Resolve == nil || candidate.Resolve.DeclURI == "" {
+			doc = completionDocumentation(candidate.Documentation, options)
 		}
-		if options.PreferredContentFormat != protocol.Markdown {
-			doc.Value = candidate.Documentation
+		var data any
+		if candidate.Resolve != nil {
+			data = candidate.Resolve
 		}
 		var edits *protocol.Or_CompletionItem_textEdit
 		if options.InsertReplaceSupported {
@@ -194,8 +201,64 @@ func toProtocolCompletionItems(candidates []completion.CompletionItem, surroundi
 			Documentation: doc,
 			Tags:          protocol.NonNilSlice(candidate.Tags),
 			Deprecated:    candidate.Deprecated,
+			Data:          data,
 		}
 		items = append(items, item)
 	}
 	return items, nil
 }
+
+// completionDocumentation returns the documentation of a completion
+// item in the client's preferred format.
+func completionDocumentation(doc string, options *settings.Options) *protocol.Or_CompletionItem_documentation {
+	if options.PreferredContentFormat != protocol.Markdown {
+		return &protocol.Or_CompletionItem_documentation{Value: doc}
+	}
+	return &protocol.Or_CompletionItem_documentation{
+		Value: protocol.MarkupContent{
+			Kind:  protocol.Markdown,
+			Value: golang.DocCommentToMarkdown(doc, options),
+		},
+	}
+}
+
+// ResolveCompletionItem computes the properties of a completion item
+// that the client asked to resolve lazily: its documentation and the
+// edits that add any necessary import.
+func (s *server) ResolveCompletionItem(ctx context.Context, item *protocol.CompletionItem) (*protocol.CompletionItem, error) {
+	ctx, done := event.Start(ctx, "lsp.Server.resolveCompletionItem")
+	defer done()
+
+	if item.Data == nil {
+		return item, nil // nothing to resolve
+	}
+	// item.Data was decoded as a generic JSON value.
+	var data completion.ResolveData
+	raw, err := json.Marshal(item.Data)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("invalid completion item data: %v", err)
+	}
+
+	fh, snapshot, release, err := s.fileOf(ctx, data.URI)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	resolved, err := completion.Resolve(ctx, snapshot, fh, &data)
+	if err != nil {
+		return nil, err
+	}
+	item.AdditionalTextEdits = append(item.AdditionalTextEdits, resolved.AdditionalTextEdits...)
+	if data.DeclURI != "" {
+		item.Documentation = completionDocumentation(resolved.Documentation, snapshot.Options())
+		if resolved.Tags != nil {
+			item.Tags = resolved.Tags
+		}
+		item.Deprecated = item.Deprecated || resolved.Deprecated
+	}
+	return item, nil
+}
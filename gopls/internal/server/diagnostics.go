@@ -76,6 +76,162 @@ func (s *server) Diagnostic(ctx context.Context, params *protocol.DocumentDiagno
 	}, nil
 }
 
+// DiagnosticWorkspace implements the workspace/diagnostic request, the
+// pull-model counterpart of publishDiagnostics for all files of the
+// workspace, not just the open ones.
+//
+// Each report carries a result ID derived from the hash of the file's
+// diagnostics. Files whose result ID matches the one supplied by the
+// client are reported as unchanged. If the client supplied a partial
+// result token, the reports of each view are streamed as progress
+// notifications as soon as they are available.
+func (s *server) DiagnosticWorkspace(ctx context.Context, params *protocol.WorkspaceDiagnosticParams) (*protocol.WorkspaceDiagnosticReport, error) {
+	ctx, done := event.Start(ctx, "server.DiagnosticWorkspace")
+	defer done()
+
+	jsonrpc2.Async(ctx) // allow asynchronous collection of diagnostics
+
+	previous := make(map[protocol.DocumentURI]string)
+	for _, prev := range params.PreviousResultIds {
+		previous[prev.URI] = prev.Value
+	}
+
+	result := &protocol.WorkspaceDiagnosticReport{
+		Items: []protocol.WorkspaceDocumentDiagnosticReport{},
+	}
+	reported := make(map[protocol.DocumentURI]bool)
+
+	// emit delivers a batch of reports, either as partial results
+	// or as part of the final result.
+	emit := func(items []protocol.WorkspaceDocumentDiagnosticReport) error {
+		if len(items) == 0 {
+			return nil
+		}
+		if token := params.PartialResultToken; token != nil {
+			return s.client.Progress(ctx, &protocol.ProgressParams{
+				Token: *token,
+				Value: protocol.WorkspaceDiagnosticReportPartialResult{Items: items},
+			})
+		}
+		result.Items = append(result.Items, items...)
+		return nil
+	}
+
+	for _, view := range s.session.Views() {
+		snapshot, release, err := view.Snapshot()
+		if err != nil {
+			continue // view is shutting down
+		}
+		diagnostics, err := s.workspaceDiagnostics(ctx, snapshot)
+		release()
+		if err != nil {
+			return nil, err
+		}
+
+		var items []protocol.WorkspaceDocumentDiagnosticReport
+		for uri, fileDiags := range moremaps.Sorted(diagnostics) {
+			// A file may belong to several views (e.g. for
+			// different GOOS/GOARCH); report it only once.
+			if reported[uri] {
+				continue
+			}
+			reported[uri] = true
+			items = append(items, s.workspaceDocumentReport(uri, fileDiags, previous[uri]))
+		}
+		if err := emit(items); err != nil {
+			return nil, err
+		}
+	}
+
+	// Files for which the client has previous results but which
+	// no longer have diagnostics in any view (e.g. because they
+	// were deleted) are reported as clean.
+	var items []protocol.WorkspaceDocumentDiagnosticReport
+	for _, prev := range params.PreviousResultIds {
+		if !reported[prev.URI] {
+			reported[prev.URI] = true
+			items = append(items, s.workspaceDocumentReport(prev.URI, nil, prev.Value))
+		}
+	}
+	if err := emit(items); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// workspaceDiagnostics computes the diagnostics of the given snapshot
+// for workspace/diagnostic requests, using the same pipeline as
+// published diagnostics.
+//
+// The result has an entry (possibly empty) for every compiled Go file
+// of the workspace packages, so that fixed errors are cleared.
+func (s *server) workspaceDiagnostics(ctx context.Context, snapshot *cache.Snapshot) (diagMap, error) {
+	diagnostics, err := s.diagnose(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	workspacePkgs, err := snapshot.WorkspaceMetadata(ctx)
+	if err != nil {
+		if s.shouldIgnoreError(snapshot, err) {
+			return diagnostics, ctx.Err()
+		}
+		return nil, err
+	}
+	for _, mp := range workspacePkgs {
+		for _, uri := range mp.CompiledGoFiles {
+			if _, ok := diagnostics[uri]; !ok && !snapshot.IgnoredFile(uri) {
+				diagnostics[uri] = nil
+			}
+		}
+	}
+	return diagnostics, nil
+}
+
+// workspaceDocumentReport returns the workspace/diagnostic report for
+// a single file. It returns an "unchanged" report if the result ID
+// of the diagnostics matches the client's previous result ID.
+func (s *server) workspaceDocumentReport(uri protocol.DocumentURI, diagnostics []*cache.Diagnostic, previousResultID string) protocol.WorkspaceDocumentDiagnosticReport {
+	var hash file.Hash
+	for _, diag := range diagnostics {
+		hash.XORWith(diag.Hash())
+	}
+	resultID := hash.String()
+	// The version is null for files that are not open.
+	var version *int32
+	for _, o := range s.session.Overlays() {
+		if o.URI() == uri {
+			v := o.Version()
+			version = &v
+			break
+		}
+	}
+	if resultID == previousResultID {
+		return protocol.WorkspaceDocumentDiagnosticReport{
+			Value: protocol.WorkspaceUnchangedDocumentDiagnosticReport{
+				URI:     uri,
+				Version: version,
+				UnchangedDocumentDiagnosticReport: protocol.UnchangedDocumentDiagnosticReport{
+					Kind:     string(protocol.DiagnosticUnchanged),
+					ResultID: resultID,
+				},
+			},
+		}
+	}
+	sortDiagnostics(diagnostics)
+	return protocol.WorkspaceDocumentDiagnosticReport{
+		Value: protocol.WorkspaceFullDocumentDiagnosticReport{
+			URI:     uri,
+			Version: version,
+			FullDocumentDiagnosticReport: protocol.FullDocumentDiagnosticReport{
+				Kind:     string(protocol.DiagnosticFull),
+				ResultID: resultID,
+				Items:    toProtocolDiagnostics(diagnostics),
+			},
+		},
+	}
+}
+
 // fileDiagnostics holds the current state of published diagnostics for a file.
 type fileDiagnostics struct {
 	publishedHash file.Hash // hash of the last set of diagnostics published for this URI
@@ -406,7 +562,7 @@ func (s *server) diagnose(ctx context.Context, snapshot *cache.Snapshot) (diagMa
 	s.updateCriticalErrorStatus(ctx, snapshot, statusErr)
 
 	// Diagnose template (.tmpl) files.
-	tmplReports := template.Diagnostics(snapshot)
+	tmplReports := template.Diagnostics(ctx, snapshot)
 	// NOTE(rfindley): typeCheckSource is not accurate here.
 	// (but this will be gone soon anyway).
 	store("diagnosing templates", tmplReports, nil)
@@ -4,12 +4,17 @@ import (
 	"context"
 	"fmt"
 	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/gopls/internal/file"
 	"golang.org/x/tools/gopls/internal/golang"
 	"golang.org/x/tools/gopls/internal/golang/completion"
 	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/internal/event"
-	"os"
-	"time"
 )
 
 // DidCreateFiles should have the following behaviors:
@@ -67,3 +72,156 @@ func (s *server) DidCreateFiles(ctx context.Context, params *protocol.CreateFile
 	time.Sleep(500 * time.Millisecond) // added for testing, should be removed later.
 	return nil
 }
+
+// WillRenameFiles returns the edits needed to update the imports of
+// packages moved by the renaming of a directory. The client applies
+// them before renaming the files.
+func (s *server) WillRenameFiles(ctx context.Context, params *protocol.RenameFilesParams) (*protocol.WorkspaceEdit, error) {
+	ctx, done := event.Start(ctx, "lsp.Server.willRenameFiles")
+	defer done()
+
+	// A renamed package may be loaded by several views.
+	// Use the edits of the first view that has any for each file.
+	var changes []protocol.DocumentChange
+	seen := make(map[protocol.DocumentURI]bool)
+	for _, view := range s.session.Views() {
+		snapshot, release, err := view.Snapshot()
+		if err != nil {
+			continue // view is shut down
+		}
+		edits, err := golang.RenameFiles(ctx, snapshot, params.Files)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		for uri, e := range edits {
+			if seen[uri] {
+				continue
+			}
+			seen[uri] = true
+			fh, err := snapshot.ReadFile(ctx, uri)
+			if err != nil {
+				release()
+				return nil, err
+			}
+			changes = append(changes, protocol.DocumentChangeEdit(fh, e))
+		}
+		release()
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return protocol.NewWorkspaceEdit(changes...), nil
+}
+
+// DidRenameFiles informs the session that files have been renamed,
+// in case the client does not also report the changes through
+// file watching.
+func (s *server) DidRenameFiles(ctx context.Context, params *protocol.RenameFilesParams) error {
+	ctx, done := event.Start(ctx, "lsp.Server.didRenameFiles")
+	defer done()
+
+	var modifications []file.Modification
+	for _, rename := range params.Files {
+		oldURI, err := protocol.ParseDocumentURI(rename.OldURI)
+		if err != nil {
+			return err
+		}
+		newURI, err := protocol.ParseDocumentURI(rename.NewURI)
+		if err != nil {
+			return err
+		}
+		// The old location no longer exists, so enumerate the
+		// files of a renamed directory at its new location.
+		for _, rel := range filesUnder(newURI.Path()) {
+			modifications = append(modifications,
+				file.Modification{URI: protocol.URIFromPath(filepath.Join(oldURI.Path(), rel)), Action: file.Delete, OnDisk: true},
+				file.Modification{URI: protocol.URIFromPath(filepath.Join(newURI.Path(), rel)), Action: file.Create, OnDisk: true})
+		}
+	}
+	if len(modifications) == 0 {
+		return nil
+	}
+	return s.didModifyFiles(ctx, modifications, FromDidChangeWatchedFiles)
+}
+
+// filesUnder returns the relative names of the Go and go.mod files
+// within the directory tree rooted at path. If path is a file, it
+// returns ".".
+func filesUnder(path string) []string {
+	var files []string
+	filepath.WalkDir(path, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // ignore unreadable entries
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if name == path || strings.HasSuffix(name, ".go") || filepath.Base(name) == "go.mod" {
+			rel, err := filepath.Rel(path, name)
+			if err == nil {
+				files = append(files, rel)
+			}
+		}
+		return nil
+	})
+	return files
+}
+
+// WillDeleteFiles warns the user if the files or directories about
+// to be deleted declare anything that is referenced from elsewhere in
+// the workspace. It never returns any edits.
+func (s *server) WillDeleteFiles(ctx context.Context, params *protocol.DeleteFilesParams) (*protocol.WorkspaceEdit, error) {
+	ctx, done := event.Start(ctx, "lsp.Server.willDeleteFiles")
+	defer done()
+
+	var refs []string
+	seen := make(map[string]bool)
+	for _, view := range s.session.Views() {
+		snapshot, release, err := view.Snapshot()
+		if err != nil {
+			continue // view is shut down
+		}
+		viewRefs, err := golang.DeletedFileReferences(ctx, snapshot, params.Files)
+		release()
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range viewRefs {
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+	}
+	if len(refs) > 0 {
+		const max = 10
+		msg := "Deleting these files will break references to "
+		if len(refs) > max {
+			msg += strings.Join(refs[:max], ", ") + fmt.Sprintf(", and %d more", len(refs)-max)
+		} else {
+			msg += strings.Join(refs, ", ")
+		}
+		showMessage(ctx, s.client, protocol.Warning, msg)
+	}
+	return nil, nil
+}
+
+// DidDeleteFiles informs the session that files have been deleted,
+// in case the client does not also report the changes through
+// file watching.
+func (s *server) DidDeleteFiles(ctx context.Context, params *protocol.DeleteFilesParams) error {
+	ctx, done := event.Start(ctx, "lsp.Server.didDeleteFiles")
+	defer done()
+
+	var modifications []file.Modification
+	for _, del := range params.Files {
+		uri, err := protocol.ParseDocumentURI(del.URI)
+		if err != nil {
+			return err
+		}
+		// Deletions of directories are expanded by didModifyFiles.
+		modifications = append(modifications, file.Modification{URI: uri, Action: file.Delete, OnDisk: true})
+	}
+	return s.didModifyFiles(ctx, modifications, FromDidChangeWatchedFiles)
+}
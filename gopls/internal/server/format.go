@@ -36,3 +36,46 @@ func (s *server) Formatting(ctx context.Context, params *protocol.DocumentFormat
 	}
 	return nil, nil // empty result
 }
+
+func (s *server) RangeFormatting(ctx context.Context, params *protocol.DocumentRangeFormattingParams) ([]protocol.TextEdit, error) {
+	ctx, done := event.Start(ctx, "lsp.Server.rangeFormatting", label.URI.Of(params.TextDocument.URI))
+	defer done()
+
+	return s.formatRanges(ctx, params.TextDocument.URI, []protocol.Range{params.Range})
+}
+
+func (s *server) RangesFormatting(ctx context.Context, params *protocol.DocumentRangesFormattingParams) ([]protocol.TextEdit, error) {
+	ctx, done := event.Start(ctx, "lsp.Server.rangesFormatting", label.URI.Of(params.TextDocument.URI))
+	defer done()
+
+	return s.formatRanges(ctx, params.TextDocument.URI, params.Ranges)
+}
+
+func (s *server) formatRanges(ctx context.Context, uri protocol.DocumentURI, rngs []protocol.Range) ([]protocol.TextEdit, error) {
+	fh, snapshot, release, err := s.fileOf(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if snapshot.FileKind(fh) != file.Go {
+		return nil, nil // empty result
+	}
+	return golang.FormatRanges(ctx, snapshot, fh, rngs)
+}
+
+func (s *server) OnTypeFormatting(ctx context.Context, params *protocol.DocumentOnTypeFormattingParams) ([]protocol.TextEdit, error) {
+	ctx, done := event.Start(ctx, "lsp.Server.onTypeFormatting", label.URI.Of(params.TextDocument.URI))
+	defer done()
+
+	fh, snapshot, release, err := s.fileOf(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if snapshot.FileKind(fh) != file.Go {
+		return nil, nil // empty result
+	}
+	return golang.FormatOnType(ctx, snapshot, fh, params.Position, params.Ch)
+}
@@ -121,7 +121,7 @@ func (s *server) Initialize(ctx context.Context, params *protocol.ParamInitializ
 		diagnosticProvider = &protocol.Or_ServerCapabilities_diagnosticProvider{
 			Value: protocol.DiagnosticOptions{
 				InterFileDependencies: true,
-				WorkspaceDiagnostics:  false, // we don't support workspace/diagnostic
+				WorkspaceDiagnostics:  true,
 			},
 		}
 	}
@@ -147,28 +147,42 @@ func (s *server) Initialize(ctx context.Context, params *protocol.ParamInitializ
 			CodeLensProvider:      &protocol.CodeLensOptions{}, // must be non-nil to enable the code lens capability
 			CompletionProvider: &protocol.CompletionOptions{
 				TriggerCharacters: []string{"."},
+				ResolveProvider:   true,
 			},
 			DefinitionProvider:         &protocol.Or_ServerCapabilities_definitionProvider{Value: true},
 			TypeDefinitionProvider:     &protocol.Or_ServerCapabilities_typeDefinitionProvider{Value: true},
 			ImplementationProvider:     &protocol.Or_ServerCapabilities_implementationProvider{Value: true},
 			DocumentFormattingProvider: &protocol.Or_ServerCapabilities_documentFormattingProvider{Value: true},
-			DocumentSymbolProvider:     &protocol.Or_ServerCapabilities_documentSymbolProvider{Value: true},
-			WorkspaceSymbolProvider:    &protocol.Or_ServerCapabilities_workspaceSymbolProvider{Value: true},
+			DocumentRangeFormattingProvider: &protocol.Or_ServerCapabilities_documentRangeFormattingProvider{
+				Value: protocol.DocumentRangeFormattingOptions{RangesSupport: true},
+			},
+			DocumentOnTypeFormattingProvider: &protocol.DocumentOnTypeFormattingOptions{
+				FirstTriggerCharacter: "}",
+				MoreTriggerCharacter:  []string{"\n"},
+			},
+			DocumentSymbolProvider:  &protocol.Or_ServerCapabilities_documentSymbolProvider{Value: true},
+			WorkspaceSymbolProvider: &protocol.Or_ServerCapabilities_workspaceSymbolProvider{Value: true},
 			ExecuteCommandProvider: &protocol.ExecuteCommandOptions{
 				Commands: protocol.NonNilSlice(options.SupportedCommands),
 			},
-			FoldingRangeProvider:      &protocol.Or_ServerCapabilities_foldingRangeProvider{Value: true},
-			HoverProvider:             &protocol.Or_ServerCapabilities_hoverProvider{Value: true},
-			DocumentHighlightProvider: &protocol.Or_ServerCapabilities_documentHighlightProvider{Value: true},
-			DocumentLinkProvider:      &protocol.DocumentLinkOptions{},
-			InlayHintProvider:         protocol.InlayHintOptions{},
-			DiagnosticProvider:        diagnosticProvider,
-			ReferencesProvider:        &protocol.Or_ServerCapabilities_referencesProvider{Value: true},
-			RenameProvider:            renameOpts,
-			SelectionRangeProvider:    &protocol.Or_ServerCapabilities_selectionRangeProvider{Value: true},
+			FoldingRangeProvider:       &protocol.Or_ServerCapabilities_foldingRangeProvider{Value: true},
+			HoverProvider:              &protocol.Or_ServerCapabilities_hoverProvider{Value: true},
+			DocumentHighlightProvider:  &protocol.Or_ServerCapabilities_documentHighlightProvider{Value: true},
+			DocumentLinkProvider:       &protocol.DocumentLinkOptions{},
+			InlayHintProvider:          protocol.InlayHintOptions{},
+			InlineValueProvider:        &protocol.Or_ServerCapabilities_inlineValueProvider{Value: true},
+			LinkedEditingRangeProvider: &protocol.Or_ServerCapabilities_linkedEditingRangeProvider{Value: true},
+			MonikerProvider:            &protocol.Or_ServerCapabilities_monikerProvider{Value: true},
+			DiagnosticProvider:         diagnosticProvider,
+			ReferencesProvider:         &protocol.Or_ServerCapabilities_referencesProvider{Value: true},
+			RenameProvider:             renameOpts,
+			SelectionRangeProvider:     &protocol.Or_ServerCapabilities_selectionRangeProvider{Value: true},
+			TypeHierarchyProvider:      &protocol.Or_ServerCapabilities_typeHierarchyProvider{Value: true},
 			SemanticTokensProvider: protocol.SemanticTokensOptions{
 				Range: &protocol.Or_SemanticTokensOptions_range{Value: true},
-				Full:  &protocol.Or_SemanticTokensOptions_full{Value: true},
+				Full: &protocol.Or_SemanticTokensOptions_full{
+					Value: protocol.SemanticTokensFullDelta{Delta: true},
+				},
 				Legend: protocol.SemanticTokensLegend{
 					TokenTypes:     moreslices.ConvertStrings[string](semtok.TokenTypes),
 					TokenModifiers: moreslices.ConvertStrings[string](semtok.TokenModifiers),
@@ -189,6 +203,10 @@ func (s *server) Initialize(ctx context.Context, params *protocol.ParamInitializ
 					Supported:           true,
 					ChangeNotifications: "workspace/didChangeWorkspaceFolders",
 				},
+				FileOperations: fileOperationOptions(),
+				TextDocumentContent: &protocol.Or_WorkspaceOptions_textDocumentContent{
+					Value: protocol.TextDocumentContentOptions{Scheme: contentScheme},
+				},
 			},
 		},
 		ServerInfo: &protocol.ServerInfo{
@@ -198,6 +216,40 @@ func (s *server) Initialize(ctx context.Context, params *protocol.ParamInitializ
 	}, nil
 }
 
+// fileOperationOptions returns the server capabilities for the
+// workspace/{will,did}{Rename,Delete}Files requests. Only renamings
+// of directories affect import paths, but deletions of Go files may
+// break references too.
+func fileOperationOptions() *protocol.FileOperationOptions {
+	folderKind, fileKind := protocol.FolderPattern, protocol.FilePattern
+	folders := protocol.FileOperationFilter{
+		Scheme: "file",
+		Pattern: protocol.FileOperationPattern{
+			Glob:    "**",
+			Matches: &folderKind,
+		},
+	}
+	goFiles := protocol.FileOperationFilter{
+		Scheme: "file",
+		Pattern: protocol.FileOperationPattern{
+			Glob:    "**/*.go",
+			Matches: &fileKind,
+		},
+	}
+	renames := &protocol.FileOperationRegistrationOptions{
+		Filters: []protocol.FileOperationFilter{folders},
+	}
+	deletes := &protocol.FileOperationRegistrationOptions{
+		Filters: []protocol.FileOperationFilter{folders, goFiles},
+	}
+	return &protocol.FileOperationOptions{
+		WillRename: renames,
+		DidRename:  renames,
+		WillDelete: deletes,
+		DidDelete:  deletes,
+	}
+}
+
 func (s *server) Initialized(ctx context.Context, params *protocol.InitializedParams) error {
 	ctx, done := event.Start(ctx, "lsp.Server.initialized")
 	defer done()
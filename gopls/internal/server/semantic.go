@@ -6,6 +6,8 @@ package server
 
 import (
 	"context"
+	"slices"
+	"strconv"
 
 	"golang.org/x/tools/gopls/internal/file"
 	"golang.org/x/tools/gopls/internal/golang"
@@ -16,7 +18,30 @@ import (
 )
 
 func (s *server) SemanticTokensFull(ctx context.Context, params *protocol.SemanticTokensParams) (*protocol.SemanticTokens, error) {
-	return s.semanticTokens(ctx, params.TextDocument, nil)
+	tokens, err := s.semanticTokens(ctx, params.TextDocument, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.saveSemanticTokens(params.TextDocument.URI, tokens)
+	return tokens, nil
+}
+
+func (s *server) SemanticTokensFullDelta(ctx context.Context, params *protocol.SemanticTokensDeltaParams) (any, error) {
+	tokens, err := s.semanticTokens(ctx, params.TextDocument, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	prev := s.saveSemanticTokens(params.TextDocument.URI, tokens)
+	if prev == nil || prev.ResultID != params.PreviousResultID {
+		// The previous result is unknown (or was superseded):
+		// the client must accept the full result.
+		return tokens, nil
+	}
+	return &protocol.SemanticTokensDelta{
+		ResultID: tokens.ResultID,
+		Edits:    semanticTokensEdits(prev.Data, tokens.Data),
+	}, nil
 }
 
 func (s *server) SemanticTokensRange(ctx context.Context, params *protocol.SemanticTokensRangeParams) (*protocol.SemanticTokens, error) {
@@ -54,3 +79,67 @@ func (s *server) semanticTokens(ctx context.Context, td protocol.TextDocumentIde
 	// as it is not marked optional in the protocol (golang/go#67885).
 	return &protocol.SemanticTokens{Data: []uint32{}}, nil
 }
+
+// saveSemanticTokens assigns a new result ID to a full semantic tokens
+// result for the specified document, and records it as the basis for
+// the next semanticTokens/full/delta request.
+// It returns the previously recorded result, if any.
+func (s *server) saveSemanticTokens(uri protocol.DocumentURI, tokens *protocol.SemanticTokens) (prev *protocol.SemanticTokens) {
+	s.semanticTokensMu.Lock()
+	defer s.semanticTokensMu.Unlock()
+
+	s.lastResultID++
+	tokens.ResultID = strconv.FormatUint(s.lastResultID, 10)
+	prev = s.lastSemanticTokens[uri]
+	s.lastSemanticTokens[uri] = tokens
+	return prev
+}
+
+// forgetSemanticTokens discards the saved semantic tokens of the
+// specified documents.
+func (s *server) forgetSemanticTokens(uris ...protocol.DocumentURI) {
+	s.semanticTokensMu.Lock()
+	defer s.semanticTokensMu.Unlock()
+
+	for _, uri := range uris {
+		delete(s.lastSemanticTokens, uri)
+	}
+}
+
+// semanticTokensEdits returns the edits that transform the encoded
+// semantic tokens prev into next.
+//
+// It computes a single edit spanning the region between the longest
+// common prefix and suffix of whole tokens. Since the encoding of each
+// token is relative to its predecessor, a change to a file typically
+// affects only the few tokens near the change, so the edit is small
+// even for very large files.
+func semanticTokensEdits(prev, next []uint32) []protocol.SemanticTokensEdit {
+	const tokenLen = 5 // each token is encoded as 5 integers
+
+	// Find the length of the common prefix, in whole tokens.
+	prefix := 0
+	for prefix+tokenLen <= min(len(prev), len(next)) &&
+		slices.Equal(prev[prefix:prefix+tokenLen], next[prefix:prefix+tokenLen]) {
+		prefix += tokenLen
+	}
+
+	// Find the length of the common suffix, in whole tokens,
+	// not overlapping the prefix.
+	suffix := 0
+	for prefix+suffix+tokenLen <= min(len(prev), len(next)) &&
+		slices.Equal(prev[len(prev)-suffix-tokenLen:len(prev)-suffix], next[len(next)-suffix-tokenLen:len(next)-suffix]) {
+		suffix += tokenLen
+	}
+
+	deleteCount := len(prev) - prefix - suffix
+	data := next[prefix : len(next)-suffix]
+	if deleteCount == 0 && len(data) == 0 {
+		return []protocol.SemanticTokensEdit{} // unchanged
+	}
+	return []protocol.SemanticTokensEdit{{
+		Start:       uint32(prefix),
+		DeleteCount: uint32(deleteCount),
+		Data:        slices.Clone(data),
+	}}
+}
@@ -49,6 +49,7 @@ func New(session *cache.Session, client protocol.ClientCloser, options *settings
 		progress:            progress.NewTracker(client),
 		options:             options,
 		viewsToDiagnose:     make(map[*cache.View]uint64),
+		lastSemanticTokens:  make(map[protocol.DocumentURI]*protocol.SemanticTokens),
 	}
 }
 
@@ -128,6 +129,12 @@ type server struct {
 	optionsMu sync.Mutex
 	options   *settings.Options
 
+	// Track the most recent full semantic tokens result for each
+	// document, for computing semanticTokens/full/delta responses.
+	semanticTokensMu   sync.Mutex
+	lastSemanticTokens map[protocol.DocumentURI]*protocol.SemanticTokens
+	lastResultID       uint64 // for semantic tokens; guarded by semanticTokensMu
+
 	// Track the most recent completion results, for measuring completion efficacy
 	efficacyMu      sync.Mutex
 	efficacyURI     protocol.DocumentURI
@@ -135,6 +142,12 @@ type server struct {
 	efficacyItems   []protocol.CompletionItem
 	efficacyPos     protocol.Position
 
+	// Track the documents served by workspace/textDocumentContent,
+	// and the package directories on which each depends, for
+	// sending refresh requests.
+	contentMu   sync.Mutex
+	contentDeps map[protocol.URI][]protocol.DocumentURI
+
 	// Web server (for package documentation, etc) associated with this
 	// LSP server. Opened on demand, and closed during LSP Shutdown.
 	webOnce sync.Once
@@ -0,0 +1,240 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+// This file defines the read-only documents that gopls serves
+// through the workspace/textDocumentContent request, as an
+// alternative to the web server (see server.go) for clients that
+// cannot reach it, such as those of remote editing sessions.
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/event"
+)
+
+// contentScheme is the URI scheme of the documents served by
+// workspace/textDocumentContent.
+//
+// Valid URIs:
+//
+//	gopls:///pkgdoc/PKGPATH?view=%s#FRAGMENT                     - show doc for package
+//	gopls:///assembly/SYMBOL?view=%s&pkg=%s&symbol=%s            - show assembly of func symbol
+//	gopls:///freesymbols/FILE?view=%s&file=%s&range=%d:%d:%d:%d - show report of free symbols
+//
+// The path identifies the kind of document, and ends with a name
+// for display by the client; the query holds the parameters.
+const contentScheme = "gopls"
+
+// contentURI returns a URI for a document served by
+// workspace/textDocumentContent.
+func contentURI(kind, name string, query url.Values) protocol.URI {
+	u := url.URL{
+		Scheme:   contentScheme,
+		Path:     "/" + kind + "/" + name,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+// pkgDocContentURI returns the URI of the documentation of the
+// specified package. The optional fragment (e.g. "Buffer.Len")
+// identifies a symbol within it.
+func pkgDocContentURI(viewID string, path golang.PackagePath, fragment string) protocol.URI {
+	uri := contentURI("pkgdoc", string(path), url.Values{"view": {viewID}})
+	if fragment != "" {
+		uri += (&url.URL{Fragment: fragment}).String()
+	}
+	return uri
+}
+
+// assemblyContentURI returns the URI of an assembly listing of the
+// specified function symbol.
+func assemblyContentURI(viewID, packageID, symbol string) protocol.URI {
+	return contentURI("assembly", symbol, url.Values{
+		"view":   {viewID},
+		"pkg":    {packageID},
+		"symbol": {symbol},
+	})
+}
+
+// freeSymbolsContentURI returns the URI of a report on the free
+// symbols referenced within the selection span (loc).
+func freeSymbolsContentURI(viewID string, loc protocol.Location) protocol.URI {
+	return contentURI("freesymbols", path.Base(loc.URI.Path()), url.Values{
+		"view": {viewID},
+		"file": {string(loc.URI)},
+		"range": {fmt.Sprintf("%d:%d:%d:%d",
+			loc.Range.Start.Line,
+			loc.Range.Start.Character,
+			loc.Range.End.Line,
+			loc.Range.End.Character)},
+	})
+}
+
+func (s *server) TextDocumentContent(ctx context.Context, params *protocol.TextDocumentContentParams) (*string, error) {
+	ctx, done := event.Start(ctx, "lsp.Server.textDocumentContent")
+	defer done()
+
+	u, err := url.Parse(params.URI)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != contentScheme {
+		return nil, fmt.Errorf("unsupported URI scheme %q", u.Scheme)
+	}
+	query := u.Query()
+
+	// Get snapshot of specified view.
+	view, err := s.session.View(query.Get("view"))
+	if err != nil {
+		return nil, err
+	}
+	snapshot, release, err := view.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var (
+		content []byte
+		mp      *metadata.Package // the package on which the content depends
+	)
+	kind, name, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	switch kind {
+	case "pkgdoc":
+		// Find package by path.
+		for _, p := range snapshot.MetadataGraph().Packages {
+			if string(p.PkgPath) == name && p.ForTest == "" {
+				mp = p
+				break
+			}
+		}
+		if mp == nil {
+			return nil, fmt.Errorf("package %q not found", name)
+		}
+		pkgs, err := snapshot.TypeCheck(ctx, mp.ID)
+		if err != nil {
+			return nil, err
+		}
+		content = golang.PackageDocText(pkgs[0])
+
+	case "assembly":
+		pkgID := metadata.PackageID(query.Get("pkg"))
+		symbol := query.Get("symbol")
+		if pkgID == "" || symbol == "" {
+			return nil, fmt.Errorf("assembly URI requires pkg and symbol")
+		}
+		pkgs, err := snapshot.TypeCheck(ctx, pkgID)
+		if err != nil {
+			return nil, err
+		}
+		mp = pkgs[0].Metadata()
+		content, err = golang.AssemblyText(ctx, snapshot, pkgs[0], symbol)
+		if err != nil {
+			return nil, err
+		}
+
+	case "freesymbols":
+		loc := protocol.Location{URI: protocol.DocumentURI(query.Get("file"))}
+		if _, err := fmt.Sscanf(query.Get("range"), "%d:%d:%d:%d",
+			&loc.Range.Start.Line,
+			&loc.Range.Start.Character,
+			&loc.Range.End.Line,
+			&loc.Range.End.Character,
+		); err != nil {
+			return nil, fmt.Errorf("invalid range")
+		}
+		pkg, pgf, err := golang.NarrowestPackageForFile(ctx, snapshot, loc.URI)
+		if err != nil {
+			return nil, err
+		}
+		start, end, err := pgf.RangePos(loc.Range)
+		if err != nil {
+			return nil, err
+		}
+		mp = pkg.Metadata()
+		content = golang.FreeSymbolsText(pkg, pgf, start, end)
+
+	default:
+		return nil, fmt.Errorf("unknown document kind %q", kind)
+	}
+
+	s.recordContentDependency(params.URI, mp)
+	text := string(content)
+	return &text, nil
+}
+
+// recordContentDependency records that the content of the document
+// with the specified URI depends on the files of package mp, so that
+// the client is asked to refresh it when they change.
+func (s *server) recordContentDependency(uri protocol.URI, mp *metadata.Package) {
+	s.contentMu.Lock()
+	defer s.contentMu.Unlock()
+
+	if s.contentDeps == nil {
+		s.contentDeps = make(map[protocol.URI][]protocol.DocumentURI)
+	}
+	// Depend on the package directories, so that added files count too.
+	var dirs []protocol.DocumentURI
+	for _, f := range mp.CompiledGoFiles {
+		if dir := f.Dir(); !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	s.contentDeps[uri] = dirs
+}
+
+// pruneContentDependencies forgets the dependencies of served
+// documents whose view no longer exists. It is called whenever the
+// set of views changes.
+func (s *server) pruneContentDependencies() {
+	s.contentMu.Lock()
+	defer s.contentMu.Unlock()
+	for uri := range s.contentDeps {
+		u, err := url.Parse(uri)
+		if err == nil {
+			_, err = s.session.View(u.Query().Get("view"))
+		}
+		if err != nil {
+			delete(s.contentDeps, uri)
+		}
+	}
+}
+
+// refreshTextDocumentContent asks the client to refresh each served
+// document whose content depends on one of the modified files.
+//
+// A document is forgotten once it has been refreshed: if the client
+// still displays it, it requests the content again.
+func (s *server) refreshTextDocumentContent(ctx context.Context, modifications []file.Modification) {
+	var refresh []protocol.URI
+	s.contentMu.Lock()
+	for uri, dirs := range s.contentDeps {
+		for _, mod := range modifications {
+			if slices.Contains(dirs, mod.URI.Dir()) {
+				refresh = append(refresh, uri)
+				delete(s.contentDeps, uri)
+				break
+			}
+		}
+	}
+	s.contentMu.Unlock()
+
+	for _, uri := range refresh {
+		if err := s.client.TextDocumentContentRefresh(ctx, &protocol.TextDocumentContentRefreshParams{URI: uri}); err != nil {
+			event.Error(ctx, "client.textDocumentContent/refresh", err)
+		}
+	}
+}
@@ -207,6 +207,8 @@ func (s *server) DidClose(ctx context.Context, params *protocol.DidCloseTextDocu
 	ctx, done := event.Start(ctx, "lsp.Server.didClose", label.URI.Of(params.TextDocument.URI))
 	defer done()
 
+	s.forgetSemanticTokens(params.TextDocument.URI)
+
 	return s.didModifyFiles(ctx, []file.Modification{
 		{
 			URI:     params.TextDocument.URI,
@@ -271,6 +273,14 @@ func (s *server) didModifyFiles(ctx context.Context, modifications []file.Modifi
 		wg.Done()
 	}()
 
+	// Ask the client to refresh any documents served by
+	// workspace/textDocumentContent that depend on the changes.
+	wg.Add(1)
+	go func() {
+		s.refreshTextDocumentContent(modCtx, modifications)
+		wg.Done()
+	}()
+
 	// After any file modifications, we need to update our watched files,
 	// in case something changed. Compute the new set of directories to watch,
 	// and if it differs from the current set, send updated registrations.
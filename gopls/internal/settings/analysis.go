@@ -58,6 +58,7 @@ import (
 	"golang.org/x/tools/gopls/internal/analysis/simplifycompositelit"
 	"golang.org/x/tools/gopls/internal/analysis/simplifyrange"
 	"golang.org/x/tools/gopls/internal/analysis/simplifyslice"
+	"golang.org/x/tools/gopls/internal/analysis/stalestringer"
 	"golang.org/x/tools/gopls/internal/analysis/unusedfunc"
 	"golang.org/x/tools/gopls/internal/analysis/unusedparams"
 	"golang.org/x/tools/gopls/internal/analysis/unusedvariable"
@@ -183,6 +184,7 @@ func init() {
 		{analyzer: embeddirective.Analyzer},
 		{analyzer: waitgroup.Analyzer}, // to appear in cmd/vet@go1.25
 		{analyzer: hostport.Analyzer},  // to appear in cmd/vet@go1.25
+		{analyzer: stalestringer.Analyzer},
 
 		// disabled due to high false positives
 		{analyzer: shadow.Analyzer, nonDefault: true}, // very noisy
@@ -81,29 +81,38 @@ const (
 	GoTest                     protocol.CodeActionKind = "source.test"
 	GoToggleCompilerOptDetails protocol.CodeActionKind = "source.toggleCompilerOptDetails"
 	AddTest                    protocol.CodeActionKind = "source.addTest"
+	GenerateStringer           protocol.CodeActionKind = "source.generateStringer"
 
 	// gopls
 	GoplsDocFeatures protocol.CodeActionKind = "gopls.doc.features"
 
 	// refactor.rewrite
+	RefactorRewriteAddTags            protocol.CodeActionKind = "refactor.rewrite.addTags"
 	RefactorRewriteChangeQuote        protocol.CodeActionKind = "refactor.rewrite.changeQuote"
 	RefactorRewriteFillStruct         protocol.CodeActionKind = "refactor.rewrite.fillStruct"
 	RefactorRewriteFillSwitch         protocol.CodeActionKind = "refactor.rewrite.fillSwitch"
+	RefactorRewriteIfToSwitch         protocol.CodeActionKind = "refactor.rewrite.ifToSwitch"
 	RefactorRewriteInvertIf           protocol.CodeActionKind = "refactor.rewrite.invertIf"
 	RefactorRewriteJoinLines          protocol.CodeActionKind = "refactor.rewrite.joinLines"
+	RefactorRewriteRemoveTags         protocol.CodeActionKind = "refactor.rewrite.removeTags"
 	RefactorRewriteRemoveUnusedParam  protocol.CodeActionKind = "refactor.rewrite.removeUnusedParam"
 	RefactorRewriteMoveParamLeft      protocol.CodeActionKind = "refactor.rewrite.moveParamLeft"
 	RefactorRewriteMoveParamRight     protocol.CodeActionKind = "refactor.rewrite.moveParamRight"
 	RefactorRewriteSplitLines         protocol.CodeActionKind = "refactor.rewrite.splitLines"
 	RefactorRewriteEliminateDotImport protocol.CodeActionKind = "refactor.rewrite.eliminateDotImport"
+	RefactorRewriteSafeDelete         protocol.CodeActionKind = "refactor.rewrite.safeDelete"
+	RefactorRewriteSwitchToIf         protocol.CodeActionKind = "refactor.rewrite.switchToIf"
 
 	// refactor.inline
-	RefactorInlineCall protocol.CodeActionKind = "refactor.inline.call"
+	RefactorInlineCall     protocol.CodeActionKind = "refactor.inline.call"
+	RefactorInlineConstant protocol.CodeActionKind = "refactor.inline.constant"
+	RefactorInlineVariable protocol.CodeActionKind = "refactor.inline.variable"
 
 	// refactor.extract
 	RefactorExtractConstant    protocol.CodeActionKind = "refactor.extract.constant"
 	RefactorExtractConstantAll protocol.CodeActionKind = "refactor.extract.constant-all"
 	RefactorExtractFunction    protocol.CodeActionKind = "refactor.extract.function"
+	RefactorExtractInterface   protocol.CodeActionKind = "refactor.extract.interface"
 	RefactorExtractMethod      protocol.CodeActionKind = "refactor.extract.method"
 	RefactorExtractVariable    protocol.CodeActionKind = "refactor.extract.variable"
 	RefactorExtractVariableAll protocol.CodeActionKind = "refactor.extract.variable-all"
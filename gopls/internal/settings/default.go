@@ -54,17 +54,26 @@ func DefaultOptions(overrides ...func(*Options)) *Options {
 						GoDoc:                            true,
 						GoFreeSymbols:                    true,
 						GoplsDocFeatures:                 true,
+						GenerateStringer:                 true,
+						RefactorRewriteAddTags:           true,
 						RefactorRewriteChangeQuote:       true,
 						RefactorRewriteFillStruct:        true,
 						RefactorRewriteFillSwitch:        true,
+						RefactorRewriteIfToSwitch:        true,
 						RefactorRewriteInvertIf:          true,
 						RefactorRewriteJoinLines:         true,
+						RefactorRewriteRemoveTags:        true,
 						RefactorRewriteRemoveUnusedParam: true,
+						RefactorRewriteSafeDelete:        true,
 						RefactorRewriteSplitLines:        true,
+						RefactorRewriteSwitchToIf:        true,
 						RefactorInlineCall:               true,
+						RefactorInlineConstant:           true,
+						RefactorInlineVariable:           true,
 						RefactorExtractConstant:          true,
 						RefactorExtractConstantAll:       true,
 						RefactorExtractFunction:          true,
+						RefactorExtractInterface:         true,
 						RefactorExtractMethod:            true,
 						RefactorExtractVariable:          true,
 						RefactorExtractVariableAll:       true,
@@ -129,6 +138,9 @@ func DefaultOptions(overrides ...func(*Options)) *Options {
 						CodeLensRunGovulncheck:    false, // TODO(hyangah): enable
 					},
 				},
+				FormattingOptions: FormattingOptions{
+					StructTagTransform: StructTagCamelCase,
+				},
 			},
 			InternalOptions: InternalOptions{
 				CompleteUnimported:          true,
@@ -78,9 +78,11 @@ type ClientOptions struct {
 	RelatedInformationSupported                bool
 	CompletionTags                             bool
 	CompletionDeprecated                       bool
+	CompletionResolveOptions                   []string
 	SupportedResourceOperations                []protocol.ResourceOperationKind
 	CodeActionResolveOptions                   []string
 	ShowDocumentSupported                      bool
+	TextDocumentContentSupported               bool
 	// SupportedWorkDoneProgressFormats specifies the formats supported by the
 	// client for handling workdone progress metadata.
 	SupportedWorkDoneProgressFormats map[WorkDoneProgressStyle]bool
@@ -431,8 +433,32 @@ type FormattingOptions struct {
 
 	// Gofumpt indicates if we should run gofumpt formatting.
 	Gofumpt bool
+
+	// StructTagTransform determines how the name of a struct field is
+	// transformed to form the value of a struct tag added by the
+	// `refactor.rewrite.addTags` code action. For example, a field
+	// named `UserID` is given the tag `json:"userID"` by "camelcase",
+	// and `json:"user_id"` by "snakecase".
+	StructTagTransform StructTagTransform `status:"experimental"`
 }
 
+// A StructTagTransform determines the value of an added struct tag
+// for a given field name.
+type StructTagTransform string
+
+const (
+	// StructTagCamelCase forms values such as "userID".
+	StructTagCamelCase StructTagTransform = "camelcase"
+	// StructTagPascalCase forms values such as "UserID".
+	StructTagPascalCase StructTagTransform = "pascalcase"
+	// StructTagSnakeCase forms values such as "user_id".
+	StructTagSnakeCase StructTagTransform = "snakecase"
+	// StructTagKebabCase forms values such as "user-id".
+	StructTagKebabCase StructTagTransform = "kebabcase"
+	// StructTagKeep uses the field name unchanged, "UserID".
+	StructTagKeep StructTagTransform = "keep"
+)
+
 // Note: DiagnosticOptions must be comparable with reflect.DeepEqual.
 type DiagnosticOptions struct {
 	// Analyses specify analyses that the user would like to enable or disable.
@@ -931,6 +957,9 @@ func (o *Options) ForClientCapabilities(clientInfo *protocol.ClientInfo, caps pr
 	if caps.Window.ShowDocument != nil {
 		o.ShowDocumentSupported = caps.Window.ShowDocument.Support
 	}
+	// Check if the client can display documents served by
+	// workspace/textDocumentContent.
+	o.TextDocumentContentSupported = caps.Workspace.TextDocumentContent != nil
 	// Check if the client supports configuration messages.
 	o.ConfigurationSupported = caps.Workspace.Configuration
 	o.DynamicConfigurationSupported = caps.Workspace.DidChangeConfiguration.DynamicRegistration
@@ -966,6 +995,11 @@ func (o *Options) ForClientCapabilities(clientInfo *protocol.ClientInfo, caps pr
 		o.CompletionDeprecated = true
 	}
 
+	// Check which completion item properties the client can resolve lazily.
+	if caps.TextDocument.Completion.CompletionItem.ResolveSupport != nil {
+		o.CompletionResolveOptions = caps.TextDocument.Completion.CompletionItem.ResolveSupport.Properties
+	}
+
 	// Check if the client supports code actions resolving.
 	if caps.TextDocument.CodeAction.DataSupport && caps.TextDocument.CodeAction.ResolveSupport != nil {
 		o.CodeActionResolveOptions = caps.TextDocument.CodeAction.ResolveSupport.Properties
@@ -1204,6 +1238,14 @@ func (o *Options) setOne(name string, value any) (applied []CounterPath, _ error
 	case "gofumpt":
 		return setBool(&o.Gofumpt, value)
 
+	case "structTagTransform":
+		return setEnum(&o.StructTagTransform, value,
+			StructTagCamelCase,
+			StructTagPascalCase,
+			StructTagSnakeCase,
+			StructTagKebabCase,
+			StructTagKeep)
+
 	case "completeFunctionCalls":
 		return setBool(&o.CompleteFunctionCalls, value)
 
@@ -24,6 +24,7 @@ type completer struct {
 	offset int // offset of the start of the Token
 	ctx    protocol.CompletionContext
 	syms   map[string]symbol
+	typed  *typeInfo // types of dot and variables, if known
 }
 
 func Completion(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pos protocol.Position, context protocol.CompletionContext) (*protocol.CompletionList, error) {
@@ -53,6 +54,11 @@ func Completion(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, p
 		ctx:    context,
 		syms:   syms,
 	}
+	if dt, err := loadDataTypes(ctx, snapshot); err == nil {
+		// The action being completed is often malformed,
+		// so check the template without it.
+		c.typed = dt.check(ctx, fh.URI(), withoutAction(p, start))
+	}
 	return c.complete()
 }
 
@@ -104,6 +110,24 @@ func inTemplate(fc *Parsed, pos protocol.Position) int {
 	return -1
 }
 
+// withoutAction returns the template p with the action that starts
+// at offset start replaced by blanks, preserving offsets.
+func withoutAction(p *Parsed, start int) *Parsed {
+	end := start
+	for _, tk := range p.tokens {
+		if tk.Start == start {
+			end = tk.End
+		}
+	}
+	buf := bytes.Clone(p.buf)
+	for i := start; i < end; i++ {
+		if buf[i] != '\n' {
+			buf[i] = ' '
+		}
+	}
+	return parseBuffer(buf)
+}
+
 var (
 	keywords = []string{"if", "with", "else", "block", "range", "template", "end}}", "end"}
 	globals  = []string{"and", "call", "html", "index", "slice", "js", "len", "not", "or",
@@ -131,6 +155,13 @@ func (c *completer) complete() (*protocol.CompletionList, error) {
 		return nil, nil // if this happens, why were we called?
 	}
 	pattern := words[len(words)-1]
+	if c.typed != nil && (pattern[0] == '.' || pattern[0] == '$') {
+		// Offer the fields and methods of the type of the chain.
+		if items, ok := c.typed.typedCompletions(sofar, start); ok {
+			ans.Items = items
+			return ans, nil
+		}
+	}
 	if pattern[0] == '$' {
 		// should we also return a raw "$"?
 		for _, s := range c.syms {
@@ -7,6 +7,8 @@ package template
 import (
 	"context"
 	"fmt"
+	"go/token"
+	"go/types"
 	"regexp"
 	"strconv"
 	"time"
@@ -15,6 +17,7 @@ import (
 	"golang.org/x/tools/gopls/internal/file"
 	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/gopls/internal/protocol/semtok"
+	"golang.org/x/tools/internal/event"
 )
 
 // line number (1-based) and message
@@ -23,15 +26,30 @@ var errRe = regexp.MustCompile(`template.*:(\d+): (.*)`)
 // Diagnostics returns parse errors. There is only one per file.
 // The errors are not always helpful. For instance { {end}}
 // will likely point to the end of the file.
-func Diagnostics(snapshot *cache.Snapshot) map[protocol.DocumentURI][]*cache.Diagnostic {
+//
+// For a template whose data has a known Go type (see typed.go),
+// Diagnostics also reports references to fields and methods that
+// the type does not have.
+func Diagnostics(ctx context.Context, snapshot *cache.Snapshot) map[protocol.DocumentURI][]*cache.Diagnostic {
 	diags := make(map[protocol.DocumentURI][]*cache.Diagnostic)
-	for uri, fh := range snapshot.Templates() {
-		diags[uri] = diagnoseOne(fh)
+	tmpls := snapshot.Templates()
+	if len(tmpls) == 0 {
+		return diags
+	}
+	dt, err := loadDataTypes(ctx, snapshot)
+	if err != nil {
+		event.Error(ctx, "finding template data types", err)
+	}
+	for uri, fh := range tmpls {
+		diags[uri] = diagnoseOne(ctx, dt, fh)
 	}
 	return diags
 }
 
-func diagnoseOne(fh file.Handle) []*cache.Diagnostic {
+// diagnoseOne returns the diagnostics for a template file.
+// If dt is non-nil, it also checks the template against the
+// types of its data.
+func diagnoseOne(ctx context.Context, dt *dataTypes, fh file.Handle) []*cache.Diagnostic {
 	// no need for skipTemplate check, as Diagnose is called on the
 	// snapshot's template files
 	buf, err := fh.Content()
@@ -44,7 +62,20 @@ func diagnoseOne(fh file.Handle) []*cache.Diagnostic {
 	}
 	p := parseBuffer(buf)
 	if p.ParseErr == nil {
-		return nil
+		if dt == nil {
+			return nil
+		}
+		var diags []*cache.Diagnostic
+		for _, e := range dt.check(ctx, fh.URI(), p).errors {
+			diags = append(diags, &cache.Diagnostic{
+				URI:      fh.URI(),
+				Range:    p.Range(e.start, e.length),
+				Severity: protocol.SeverityError,
+				Source:   cache.TemplateError,
+				Message:  e.msg,
+			})
+		}
+		return diags
 	}
 	unknownError := func(msg string) []*cache.Diagnostic {
 		s := fmt.Sprintf("malformed template error %q: %s", p.ParseErr.Error(), msg)
@@ -81,12 +112,27 @@ func diagnoseOne(fh file.Handle) []*cache.Diagnostic {
 // Definition finds the definitions of the symbol at loc. It
 // does not understand scoping (if any) in templates. This code is
 // for definitions, type definitions, and implementations.
-// Results only for variables and templates.
-func Definition(snapshot *cache.Snapshot, fh file.Handle, loc protocol.Position) ([]protocol.Location, error) {
-	x, _, err := symAtPosition(fh, loc)
+// Results only for variables and templates, and for fields and
+// methods of Go types when the type of the template's data is known.
+func Definition(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, loc protocol.Position) ([]protocol.Location, error) {
+	x, p, err := symAtPosition(fh, loc)
 	if err != nil {
 		return nil, err
 	}
+	if x.kind == protocol.Method {
+		// If the data types can't be loaded, fall back to
+		// the search for template symbols of the same name.
+		dt, err := loadDataTypes(ctx, snapshot)
+		if err != nil {
+			event.Error(ctx, "finding template data types", err)
+		} else if ref := dt.check(ctx, fh.URI(), p).refAt(p.FromPosition(loc)); ref != nil {
+			goLoc, err := objectLocation(ctx, snapshot, ref.fset, ref.obj)
+			if err != nil {
+				return nil, err
+			}
+			return []protocol.Location{goLoc}, nil
+		}
+	}
 	sym := x.name
 	ans := []protocol.Location{}
 	// PJW: this is probably a pattern to abstract
@@ -102,6 +148,25 @@ func Definition(snapshot *cache.Snapshot, fh file.Handle, loc protocol.Position)
 	return ans, nil
 }
 
+// objectLocation returns the location of the declaration of the Go
+// symbol obj, whose position is recorded in fset.
+func objectLocation(ctx context.Context, snapshot *cache.Snapshot, fset *token.FileSet, obj types.Object) (protocol.Location, error) {
+	tokFile := fset.File(obj.Pos())
+	if tokFile == nil {
+		return protocol.Location{}, fmt.Errorf("no position for %s", obj.Name())
+	}
+	fh, err := snapshot.ReadFile(ctx, protocol.URIFromPath(tokFile.Name()))
+	if err != nil {
+		return protocol.Location{}, err
+	}
+	content, err := fh.Content()
+	if err != nil {
+		return protocol.Location{}, err
+	}
+	m := protocol.NewMapper(fh.URI(), content)
+	return m.PosLocation(tokFile, obj.Pos(), obj.Pos()+token.Pos(len(obj.Name())))
+}
+
 func Hover(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, position protocol.Position) (*protocol.Hover, error) {
 	sym, p, err := symAtPosition(fh, position)
 	if sym == nil || err != nil {
@@ -35,6 +35,36 @@ func (s symbol) String() string {
 // for FieldNode or VariableNode (or ChainNode?)
 func (p *Parsed) fields(flds []string, x parse.Node) []symbol {
 	ans := []symbol{}
+	at := p.fieldsStart(flds, x)
+	if at < 0 {
+		return ans
+	}
+	for _, f := range flds {
+		at += 1 // .
+		kind := protocol.Method
+		if f[0] == '$' {
+			kind = protocol.Variable
+		}
+		sym := symbol{name: f, kind: kind, start: at, length: utf8.RuneCount([]byte(f))}
+		if kind == protocol.Variable && len(p.stack) > 1 {
+			if pipe, ok := p.stack[len(p.stack)-2].(*parse.PipeNode); ok {
+				for _, y := range pipe.Decl {
+					if x == y {
+						sym.vardef = true
+					}
+				}
+			}
+		}
+		ans = append(ans, sym)
+		at += len(f)
+	}
+	return ans
+}
+
+// fieldsStart returns the offset in buf at which the names flds of
+// the FieldNode, VariableNode, or ChainNode x start, or -1 if they
+// cannot be found.
+func (p *Parsed) fieldsStart(flds []string, x parse.Node) int {
 	// guessing that there are no embedded blanks allowed. The doc is unclear
 	lookfor := ""
 	switch x.(type) {
@@ -57,11 +87,11 @@ func (p *Parsed) fields(flds []string, x parse.Node) []symbol {
 		// context.Background() is used because we don't have access
 		// to any other context. [we could, but it would be complicated]
 		event.Log(context.Background(), fmt.Sprintf("%T unexpected in fields()", x))
-		return nil
+		return -1
 	}
 	if len(lookfor) == 0 {
 		event.Log(context.Background(), fmt.Sprintf("no strings in fields() %#v", x))
-		return nil
+		return -1
 	}
 	startsAt := int(x.Position())
 	ix := bytes.Index(p.buf[startsAt:], []byte(lookfor)) // HasPrefix? PJW?
@@ -70,30 +100,10 @@ func (p *Parsed) fields(flds []string, x parse.Node) []symbol {
 		startsAt -= len(flds[0]) + 1
 		ix = bytes.Index(p.buf[startsAt:], []byte(lookfor)) // ix might be 1? PJW
 		if ix < 0 {
-			return ans
+			return -1
 		}
 	}
-	at := ix + startsAt
-	for _, f := range flds {
-		at += 1 // .
-		kind := protocol.Method
-		if f[0] == '$' {
-			kind = protocol.Variable
-		}
-		sym := symbol{name: f, kind: kind, start: at, length: utf8.RuneCount([]byte(f))}
-		if kind == protocol.Variable && len(p.stack) > 1 {
-			if pipe, ok := p.stack[len(p.stack)-2].(*parse.PipeNode); ok {
-				for _, y := range pipe.Decl {
-					if x == y {
-						sym.vardef = true
-					}
-				}
-			}
-		}
-		ans = append(ans, sym)
-		at += len(f)
-	}
-	return ans
+	return ix + startsAt
 }
 
 func (p *Parsed) findSymbols() {
@@ -0,0 +1,542 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package template
+
+// This file associates templates with the Go types of the data they
+// render, so that the field and method chains of a template such as
+// {{.User.Name}} can be checked, completed, and navigated.
+//
+// A template is associated with the type of its data (its initial dot)
+// in one of two ways:
+//
+//  1. by a directive comment in the template file naming a Go type,
+//     such as {{/* gopls:type example.com/views.Page */}};
+//  2. by calls in workspace packages that execute it, such as
+//     t.ExecuteTemplate(w, "page.tmpl", data), or t.Execute(w, data)
+//     where t was created by template.ParseFiles("page.tmpl").
+//
+// A template executed with data of different types is not associated
+// with any of them.
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"go/types"
+	"path"
+	"regexp"
+	"strings"
+	"text/template/parse"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/types/typeutil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/protocol"
+)
+
+// typeDirectiveRe matches a directive comment declaring the type of
+// the data of a template file: {{/* gopls:type [*]PKGPATH.NAME */}}.
+var typeDirectiveRe = regexp.MustCompile(`\{\{-?\s*/\*\s*gopls:type\s+(\*?)(\S+)\.(\w+)\s*\*/\s*-?\}\}`)
+
+// A dataType is the Go type of the data of a template.
+type dataType struct {
+	typ  types.Type
+	fset *token.FileSet // positions of the symbols of typ
+}
+
+// dataTypes holds the types of the data of the templates of a snapshot.
+type dataTypes struct {
+	snapshot *cache.Snapshot
+	byName   map[string]*dataType // by template name; nil type if ambiguous
+}
+
+// loadDataTypes returns the types of the data of the templates
+// executed by the workspace packages of the snapshot.
+func loadDataTypes(ctx context.Context, snapshot *cache.Snapshot) (*dataTypes, error) {
+	calls, err := snapshot.TemplateDataTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dt := &dataTypes{snapshot: snapshot, byName: make(map[string]*dataType, len(calls))}
+	for name, t := range calls {
+		dt.byName[name] = &dataType{typ: t.Type, fset: t.FileSet}
+	}
+	return dt, nil
+}
+
+// directiveType returns the type named by the gopls:type directive
+// of the template file p, if any, along with its position in p.
+func (dt *dataTypes) directiveType(ctx context.Context, p *Parsed) (*dataType, *typeError) {
+	m := typeDirectiveRe.FindSubmatchIndex(p.buf)
+	if m == nil {
+		return nil, nil
+	}
+	star, pkgPath, name := string(p.buf[m[2]:m[3]]), string(p.buf[m[4]:m[5]]), string(p.buf[m[6]:m[7]])
+	errorf := func(format string, args ...any) *typeError {
+		return &typeError{
+			start:  m[4],
+			length: utf8.RuneCount(p.buf[m[4]:m[7]]),
+			msg:    fmt.Sprintf(format, args...),
+		}
+	}
+
+	var mp *metadata.Package
+	for _, candidate := range dt.snapshot.MetadataGraph().Packages {
+		if string(candidate.PkgPath) == pkgPath && candidate.ForTest == "" {
+			mp = candidate
+			break
+		}
+	}
+	if mp == nil {
+		return nil, errorf("gopls:type: package %q not found", pkgPath)
+	}
+	pkgs, err := dt.snapshot.TypeCheck(ctx, mp.ID)
+	if err != nil {
+		return nil, errorf("gopls:type: %v", err)
+	}
+	tname, ok := pkgs[0].Types().Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, errorf("gopls:type: no type %s in package %q", name, pkgPath)
+	}
+	t := tname.Type()
+	if star != "" {
+		t = types.NewPointer(t)
+	}
+	return &dataType{typ: t, fset: pkgs[0].FileSet()}, nil
+}
+
+// typeInfo holds the results of checking a template file against
+// the types of its data.
+type typeInfo struct {
+	errors []typeError
+	refs   []fieldRef
+	scopes []scope
+}
+
+// A typeError is an invalid field or method reference in a template.
+type typeError struct {
+	start, length int // in buf; length in runes
+	msg           string
+}
+
+// A fieldRef is a reference in a template to a Go field or method.
+type fieldRef struct {
+	start, length int // in buf; length in runes
+	obj           types.Object
+	fset          *token.FileSet
+}
+
+// A scope is a span of a template within which dot and the variables
+// have known types.
+type scope struct {
+	start, end int // in buf
+	dot        types.Type
+	vars       map[string]types.Type
+}
+
+// check checks the field and method chains of the template file p,
+// with the specified URI, against the types of the data of its
+// templates.
+func (dt *dataTypes) check(ctx context.Context, uri protocol.DocumentURI, p *Parsed) *typeInfo {
+	info := new(typeInfo)
+	if p.ParseErr != nil {
+		return info
+	}
+	dir, derr := dt.directiveType(ctx, p)
+	if derr != nil {
+		info.errors = append(info.errors, *derr)
+	}
+	for _, t := range p.named {
+		var data *dataType
+		if t.Name() == "" {
+			// The file itself, named by its base name.
+			data = dir
+			if data == nil {
+				data = dt.byName[path.Base(uri.Path())]
+			}
+		} else {
+			data = dt.byName[t.Name()]
+		}
+		if data == nil || data.typ == nil || t.Tree == nil {
+			continue
+		}
+		c := &checker{p: p, info: info, fset: data.fset}
+		vars := map[string]types.Type{"$": data.typ}
+		end := len(p.buf)
+		if t.Name() == "" {
+			// Declarations are visible in the whole file.
+			info.scopes = append(info.scopes, scope{start: 0, end: end, dot: data.typ, vars: vars})
+		}
+		c.walk(t.Root, data.typ, vars, end)
+	}
+	return info
+}
+
+// scopeAt returns the innermost scope enclosing the offset, or nil.
+func (info *typeInfo) scopeAt(offset int) *scope {
+	var inner *scope
+	for i, s := range info.scopes {
+		if s.start <= offset && offset <= s.end {
+			if inner == nil || s.end-s.start <= inner.end-inner.start {
+				inner = &info.scopes[i]
+			}
+		}
+	}
+	return inner
+}
+
+// refAt returns the field reference at the offset, or nil.
+func (info *typeInfo) refAt(offset int) *fieldRef {
+	for i, r := range info.refs {
+		if r.start <= offset && offset < r.start+r.length {
+			return &info.refs[i]
+		}
+	}
+	return nil
+}
+
+// A checker walks the parse tree of a template, computing the types
+// of dot and the variables, and resolving field and method names.
+// A nil type is unknown: it is not checked.
+type checker struct {
+	p    *Parsed
+	info *typeInfo
+	fset *token.FileSet
+}
+
+// walk checks node n, which ends at offset end, given the types of
+// dot and the variables in scope.
+func (c *checker) walk(n parse.Node, dot types.Type, vars map[string]types.Type, end int) {
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		c.info.scopes = append(c.info.scopes, scope{start: int(n.Pos), end: end, dot: dot, vars: vars})
+		for i, m := range n.Nodes {
+			e := end
+			if i+1 < len(n.Nodes) {
+				e = int(n.Nodes[i+1].Position())
+			}
+			c.walk(m, dot, vars, e)
+		}
+
+	case *parse.ActionNode:
+		c.declare(n.Pipe, c.pipe(n.Pipe, dot, vars), vars)
+
+	case *parse.IfNode:
+		inner := clone(vars)
+		c.declare(n.Pipe, c.pipe(n.Pipe, dot, inner), inner)
+		c.branches(&n.BranchNode, dot, inner, dot, end)
+
+	case *parse.WithNode:
+		inner := clone(vars)
+		t := c.pipe(n.Pipe, dot, inner)
+		c.declare(n.Pipe, t, inner)
+		c.branches(&n.BranchNode, t, inner, dot, end)
+
+	case *parse.RangeNode:
+		inner := clone(vars)
+		key, elem := rangeTypes(c.pipe(n.Pipe, dot, inner))
+		switch len(n.Pipe.Decl) {
+		case 1:
+			inner[n.Pipe.Decl[0].Ident[0]] = elem
+		case 2:
+			inner[n.Pipe.Decl[0].Ident[0]] = key
+			inner[n.Pipe.Decl[1].Ident[0]] = elem
+		}
+		c.branches(&n.BranchNode, elem, inner, dot, end)
+
+	case *parse.TemplateNode:
+		c.pipe(n.Pipe, dot, vars)
+	}
+}
+
+// branches checks the body of an if, range, or with node, with dot
+// of type bodyDot, and its else branch, with dot of type elseDot.
+func (c *checker) branches(n *parse.BranchNode, bodyDot types.Type, vars map[string]types.Type, elseDot types.Type, end int) {
+	bodyEnd := end
+	if n.ElseList != nil {
+		bodyEnd = int(n.ElseList.Pos)
+	}
+	c.walk(n.List, bodyDot, vars, bodyEnd)
+	if n.ElseList != nil {
+		c.walk(n.ElseList, elseDot, clone(vars), end)
+	}
+}
+
+// declare records the type of the variables declared by pipe.
+func (c *checker) declare(pipe *parse.PipeNode, t types.Type, vars map[string]types.Type) {
+	if pipe == nil {
+		return
+	}
+	for _, v := range pipe.Decl {
+		vars[v.Ident[0]] = t
+	}
+}
+
+// pipe checks the pipeline and returns the type of its value.
+func (c *checker) pipe(pipe *parse.PipeNode, dot types.Type, vars map[string]types.Type) types.Type {
+	if pipe == nil {
+		return nil
+	}
+	var t types.Type
+	for _, cmd := range pipe.Cmds {
+		t = c.command(cmd, dot, vars)
+	}
+	return t
+}
+
+// command checks the command and returns the type of its value.
+func (c *checker) command(cmd *parse.CommandNode, dot types.Type, vars map[string]types.Type) types.Type {
+	for _, arg := range cmd.Args[1:] {
+		c.operand(arg, dot, vars)
+	}
+	if id, ok := cmd.Args[0].(*parse.IdentifierNode); ok {
+		// A call of a function.
+		switch id.Ident {
+		case "len":
+			return types.Typ[types.Int]
+		case "html", "js", "print", "printf", "println", "urlquery":
+			return types.Typ[types.String]
+		case "eq", "ge", "gt", "le", "lt", "ne", "not":
+			return types.Typ[types.Bool]
+		}
+		return nil
+	}
+	return c.operand(cmd.Args[0], dot, vars)
+}
+
+// operand checks the operand and returns the type of its value.
+func (c *checker) operand(n parse.Node, dot types.Type, vars map[string]types.Type) types.Type {
+	switch n := n.(type) {
+	case *parse.DotNode:
+		return dot
+	case *parse.FieldNode:
+		return c.selectors(dot, n.Ident, c.offsets(n.Ident, n))
+	case *parse.VariableNode:
+		offsets := c.offsets(n.Ident, n)
+		return c.selectors(vars[n.Ident[0]], n.Ident[1:], offsets[1:])
+	case *parse.ChainNode:
+		return c.selectors(c.operand(n.Node, dot, vars), n.Field, c.offsets(n.Field, n))
+	case *parse.PipeNode:
+		return c.pipe(n, dot, vars)
+	case *parse.StringNode:
+		return types.Typ[types.String]
+	case *parse.BoolNode:
+		return types.Typ[types.Bool]
+	}
+	return nil
+}
+
+// offsets returns the offsets in buf of the names of the fields or
+// variable of node x, or -1 if unknown.
+func (c *checker) offsets(names []string, x parse.Node) []int {
+	offsets := make([]int, len(names))
+	at := c.p.fieldsStart(names, x)
+	for i, name := range names {
+		if at < 0 {
+			offsets[i] = -1
+			continue
+		}
+		if _, ok := x.(*parse.VariableNode); !ok || i > 0 {
+			at++ // .
+		}
+		offsets[i] = at
+		at += len(name)
+	}
+	return offsets
+}
+
+// selectors resolves the chain of field or method names, at the
+// specified offsets, starting from a value of type t, and returns the
+// type of the result.
+func (c *checker) selectors(t types.Type, names []string, offsets []int) types.Type {
+	for i, name := range names {
+		t = c.selector(t, name, offsets[i])
+	}
+	return t
+}
+
+// selector resolves the field or method name, at the specified
+// offset, of a value of type t, and returns the type of the result,
+// following the rules of text/template.
+func (c *checker) selector(t types.Type, name string, offset int) types.Type {
+	if t == nil {
+		return nil
+	}
+	length := utf8.RuneCountInString(name)
+	if token.IsExported(name) {
+		obj, _, _ := types.LookupFieldOrMethod(t, true, nil, name)
+		switch obj := obj.(type) {
+		case *types.Var:
+			c.ref(offset, length, obj)
+			return obj.Type()
+		case *types.Func:
+			c.ref(offset, length, obj)
+			if res := obj.Signature().Results(); res.Len() > 0 {
+				return res.At(0).Type()
+			}
+			return nil
+		}
+	}
+	under := t.Underlying()
+	if ptr, ok := under.(*types.Pointer); ok {
+		under = ptr.Elem().Underlying()
+	}
+	switch under := under.(type) {
+	case *types.Map:
+		return under.Elem()
+	case *types.Interface:
+		return nil // dynamic
+	}
+	if _, ok := types.Unalias(t).(*types.TypeParam); ok {
+		return nil
+	}
+	if offset >= 0 {
+		c.info.errors = append(c.info.errors, typeError{
+			start:  offset,
+			length: length,
+			msg:    fmt.Sprintf("can't evaluate field %s in type %s", name, types.TypeString(t, packageName)),
+		})
+	}
+	return nil
+}
+
+// ref records a reference to the field or method obj.
+func (c *checker) ref(offset, length int, obj types.Object) {
+	if offset >= 0 {
+		c.info.refs = append(c.info.refs, fieldRef{start: offset, length: length, obj: obj, fset: c.fset})
+	}
+}
+
+// rangeTypes returns the types of the key (or index) and element of
+// a range over a value of type t.
+func rangeTypes(t types.Type) (key, elem types.Type) {
+	if t == nil {
+		return nil, nil
+	}
+	under := t.Underlying()
+	if ptr, ok := under.(*types.Pointer); ok {
+		under = ptr.Elem().Underlying()
+	}
+	switch under := under.(type) {
+	case *types.Slice:
+		return types.Typ[types.Int], under.Elem()
+	case *types.Array:
+		return types.Typ[types.Int], under.Elem()
+	case *types.Map:
+		return under.Key(), under.Elem()
+	case *types.Chan:
+		return under.Elem(), under.Elem()
+	case *types.Basic:
+		if under.Info()&types.IsInteger != 0 {
+			return t, t
+		}
+	}
+	return nil, nil
+}
+
+// members returns the fields and methods of a value of type t that a
+// template may select, or nil if unknown.
+func members(t types.Type) []types.Object {
+	if t == nil {
+		return nil
+	}
+	var objs []types.Object
+	seen := make(map[string]bool)
+	add := func(obj types.Object) {
+		if obj.Exported() && !seen[obj.Name()] {
+			seen[obj.Name()] = true
+			objs = append(objs, obj)
+		}
+	}
+	for _, sel := range typeutil.IntuitiveMethodSet(t, nil) {
+		add(sel.Obj())
+	}
+	// Add the fields, including promoted ones, breadth first.
+	var visit func(t types.Type, depth int)
+	visit = func(t types.Type, depth int) {
+		if ptr, ok := t.Underlying().(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		s, ok := t.Underlying().(*types.Struct)
+		if !ok || depth > 5 {
+			return
+		}
+		for f := range s.Fields() {
+			add(f)
+		}
+		for f := range s.Fields() {
+			if f.Embedded() {
+				visit(f.Type(), depth+1)
+			}
+		}
+	}
+	visit(t, 0)
+	return objs
+}
+
+// packageName is a [types.Qualifier] that qualifies symbols by their
+// package name, as in the messages of text/template.
+func packageName(pkg *types.Package) string { return pkg.Name() }
+
+// clone returns a copy of the map of variables.
+func clone(vars map[string]types.Type) map[string]types.Type {
+	res := make(map[string]types.Type, len(vars))
+	for k, v := range vars {
+		res[k] = v
+	}
+	return res
+}
+
+// typedCompletions returns the completions of the chain of field or
+// method names that ends the text before the cursor at offset, such
+// as ".User.Na" or "$x.", or false if its type is unknown.
+func (info *typeInfo) typedCompletions(sofar []byte, offset int) ([]protocol.CompletionItem, bool) {
+	chain := chainRe.Find(sofar)
+	if chain == nil {
+		return nil, false
+	}
+	s := info.scopeAt(offset)
+	if s == nil {
+		return nil, false
+	}
+	names := strings.Split(string(chain), ".")
+	t := s.dot
+	if names[0] != "" {
+		t = s.vars[names[0]]
+	}
+	for _, name := range names[1 : len(names)-1] {
+		t = (&checker{info: new(typeInfo)}).selector(t, name, -1)
+	}
+	objs := members(t)
+	if objs == nil {
+		return nil, false
+	}
+	prefix := strings.ToLower(names[len(names)-1])
+	items := []protocol.CompletionItem{}
+	for _, obj := range objs {
+		if !strings.HasPrefix(strings.ToLower(obj.Name()), prefix) {
+			continue
+		}
+		item := protocol.CompletionItem{
+			Label:  obj.Name(),
+			Kind:   protocol.FieldCompletion,
+			Detail: types.TypeString(obj.Type(), packageName),
+		}
+		if _, ok := obj.(*types.Func); ok {
+			item.Kind = protocol.MethodCompletion
+			item.Detail = types.ObjectString(obj, packageName)
+		}
+		items = append(items, item)
+	}
+	return items, true
+}
+
+// chainRe matches a chain of field or method names at the end of the
+// text before the cursor.
+var chainRe = regexp.MustCompile(`(\$\w*)?(\.\w*)+$`)
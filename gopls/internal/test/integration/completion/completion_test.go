@@ -7,6 +7,7 @@ package completion
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"sort"
 	"strings"
 	"testing"
@@ -507,6 +508,97 @@ func doit() {
 	})
 }
 
+func TestCompletionResolve(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- main.go --
+package main
+
+// Hello says hello.
+func Hello() {}
+
+// Goodbye says goodbye.
+//
+// Deprecated: say hello instead.
+func Goodbye() {}
+
+func main() {
+	Hell
+	Goodb
+	_ = bytes.NewBuf
+}
+`
+	const capabilities = `{ "textDocument": { "completion": { "completionItem": {
+		"resolveSupport": { "properties": ["documentation", "additionalTextEdits"] },
+		"tagSupport": { "valueSet": [1] }
+	} } } }`
+	WithOptions(
+		CapabilitiesJSON([]byte(capabilities)),
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("main.go")
+		env.Await(env.DoneWithOpen())
+
+		find := func(loc protocol.Location, label string) protocol.CompletionItem {
+			t.Helper()
+			for _, item := range env.Completion(loc).Items {
+				if item.Label == label {
+					return item
+				}
+			}
+			t.Fatalf("no completion item with label %q", label)
+			return protocol.CompletionItem{}
+		}
+		resolve := func(item protocol.CompletionItem) *protocol.CompletionItem {
+			t.Helper()
+			if item.Data == nil {
+				t.Fatalf("completion item %q has no data to resolve", item.Label)
+			}
+			resolved, err := env.Editor.Server.ResolveCompletionItem(env.Ctx, &item)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return resolved
+		}
+
+		// Documentation is computed lazily.
+		item := find(env.RegexpSearch("main.go", "\tHell()"), "Hello")
+		if item.Documentation != nil {
+			t.Errorf("Hello: got documentation %v before resolve, want none", item.Documentation.Value)
+		}
+		resolved := resolve(item)
+		if resolved.Documentation == nil || !strings.Contains(fmt.Sprint(resolved.Documentation.Value), "Hello says hello.") {
+			t.Errorf("Hello: got resolved documentation %v, want doc comment", resolved.Documentation)
+		}
+
+		// But the deprecation status is not, as it affects how the
+		// item is displayed in the list.
+		item = find(env.RegexpSearch("main.go", "\tGoodb()"), "Goodbye")
+		if item.Documentation != nil {
+			t.Errorf("Goodbye: got documentation %v before resolve, want none", item.Documentation.Value)
+		}
+		if want := []protocol.CompletionItemTag{protocol.ComplDeprecated}; !reflect.DeepEqual(item.Tags, want) {
+			t.Errorf("Goodbye: got tags %v before resolve, want %v", item.Tags, want)
+		}
+
+		// So are the edits that add an import.
+		item = find(env.RegexpSearch("main.go", "NewBuf()"), "NewBuffer")
+		if len(item.AdditionalTextEdits) > 0 {
+			t.Errorf("NewBuffer: got import edits %v before resolve, want none", item.AdditionalTextEdits)
+		}
+		resolved = resolve(item)
+		var newText []string
+		for _, edit := range resolved.AdditionalTextEdits {
+			newText = append(newText, edit.NewText)
+		}
+		if got := strings.Join(newText, ""); !strings.Contains(got, `"bytes"`) {
+			t.Errorf("NewBuffer: got resolved import edits %q, want import of bytes", got)
+		}
+	})
+}
+
 func TestUnimportedCompletion_VSCodeIssue1489(t *testing.T) {
 	const src = `
 -- go.mod --
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"golang.org/x/tools/gopls/internal/protocol"
@@ -159,6 +160,74 @@ func TestDiagnosticClearingOnEdit(t *testing.T) {
 	})
 }
 
+func TestWorkspaceDiagnostics(t *testing.T) {
+	WithOptions(
+		Settings{
+			"pullDiagnostics": true,
+		},
+	).Run(t, badPackage, func(t *testing.T, env *Env) {
+		// pull requests workspace diagnostics with the given previous
+		// result IDs, and returns the full reports' item counts and
+		// the set of unchanged files, both keyed by base name.
+		// It records the reported document versions in versions.
+		versions := make(map[string]*int32)
+		pull := func(previous []protocol.PreviousResultID) (full map[string]int, unchanged map[string]bool, ids []protocol.PreviousResultID) {
+			full = make(map[string]int)
+			unchanged = make(map[string]bool)
+			for _, item := range env.WorkspaceDiagnostics(previous).Items {
+				// Unchanged reports unmarshal as (empty) full
+				// reports, so we must inspect the kind.
+				report, ok := item.Value.(protocol.WorkspaceFullDocumentDiagnosticReport)
+				if !ok {
+					t.Fatalf("unexpected report type %T", item.Value)
+				}
+				base := filepath.Base(report.URI.Path())
+				versions[base] = report.Version
+				switch report.Kind {
+				case string(protocol.DiagnosticFull):
+					full[base] = len(report.Items)
+				case string(protocol.DiagnosticUnchanged):
+					unchanged[base] = true
+				default:
+					t.Fatalf("unexpected report kind %q", report.Kind)
+				}
+				ids = append(ids, protocol.PreviousResultID{URI: report.URI, Value: report.ResultID})
+			}
+			return full, unchanged, ids
+		}
+
+		// The files need not be open.
+		full, _, ids := pull(nil)
+		for _, f := range []string{"a.go", "b.go"} {
+			if got := full[f]; got != 1 {
+				t.Errorf("workspace/diagnostic reported %d diagnostics for %s, want 1", got, f)
+			}
+			if v := versions[f]; v != nil {
+				t.Errorf("workspace/diagnostic reported version %d for unopened %s, want null", *v, f)
+			}
+		}
+
+		// Nothing has changed.
+		full, unchanged, ids := pull(ids)
+		if len(full) > 0 || !unchanged["a.go"] || !unchanged["b.go"] {
+			t.Errorf("workspace/diagnostic reported full=%v unchanged=%v, want all unchanged", full, unchanged)
+		}
+
+		// Fix the error by editing the const name in b.go to `b`.
+		env.OpenFile("b.go")
+		env.RegexpReplace("b.go", "(a) = 2", "b")
+		full, _, _ = pull(ids)
+		for _, f := range []string{"a.go", "b.go"} {
+			if got, ok := full[f]; !ok || got != 0 {
+				t.Errorf("workspace/diagnostic reported (%d, %t) diagnostics for %s, want full report with 0", got, ok, f)
+			}
+		}
+		if versions["a.go"] != nil || versions["b.go"] == nil {
+			t.Errorf("workspace/diagnostic reported versions a.go=%v b.go=%v, want only b.go versioned", versions["a.go"], versions["b.go"])
+		}
+	})
+}
+
 func TestDiagnosticClearingOnDelete_Issue37049(t *testing.T) {
 	Run(t, badPackage, func(t *testing.T, env *Env) {
 		env.OpenFile("a.go")
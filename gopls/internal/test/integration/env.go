@@ -85,6 +85,8 @@ func (a *Awaiter) Hooks() fake.ClientHooks {
 		OnShowMessageRequest:     a.onShowMessageRequest,
 		OnRegisterCapability:     a.onRegisterCapability,
 		OnUnregisterCapability:   a.onUnregisterCapability,
+
+		OnTextDocumentContentRefresh: a.onTextDocumentContentRefresh,
 	}
 }
 
@@ -96,6 +98,7 @@ type State struct {
 	showDocument       []*protocol.ShowDocumentParams
 	showMessage        []*protocol.ShowMessageParams
 	showMessageRequest []*protocol.ShowMessageRequestParams
+	contentRefreshes   []protocol.URI
 
 	registrations          []*protocol.RegistrationParams
 	registeredCapabilities map[string]protocol.Registration
@@ -150,6 +153,15 @@ func (a *Awaiter) onShowDocument(_ context.Context, params *protocol.ShowDocumen
 	return nil
 }
 
+func (a *Awaiter) onTextDocumentContentRefresh(_ context.Context, params *protocol.TextDocumentContentRefreshParams) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.state.contentRefreshes = append(a.state.contentRefreshes, params.URI)
+	a.checkConditionsLocked()
+	return nil
+}
+
 // ListenToShownDocuments registers a listener to incoming showDocument
 // notifications. Call the resulting func to deregister the listener and
 // receive all notifications that have occurred since the listener was
@@ -241,6 +241,37 @@ func ShownDocuments(into *[]*protocol.ShowDocumentParams) Expectation {
 	}
 }
 
+// RefreshedTextDocumentContent asserts that the client has received a
+// workspace/textDocumentContent/refresh request for the given URI.
+func RefreshedTextDocumentContent(uri protocol.URI) Expectation {
+	check := func(s State) (Verdict, string) {
+		if slices.Contains(s.contentRefreshes, uri) {
+			return Met, ""
+		}
+		return Unmet, fmt.Sprintf("no textDocumentContent/refresh request received for %s", uri)
+	}
+	return Expectation{
+		Check:       check,
+		Description: fmt.Sprintf("received workspace/textDocumentContent/refresh for URI %s", uri),
+	}
+}
+
+// NoRefreshedTextDocumentContent asserts that the client has not
+// received a workspace/textDocumentContent/refresh request for the
+// given URI.
+func NoRefreshedTextDocumentContent(uri protocol.URI) Expectation {
+	check := func(s State) (Verdict, string) {
+		if slices.Contains(s.contentRefreshes, uri) {
+			return Unmeetable, fmt.Sprintf("received textDocumentContent/refresh request for %s", uri)
+		}
+		return Met, ""
+	}
+	return Expectation{
+		Check:       check,
+		Description: fmt.Sprintf("no workspace/textDocumentContent/refresh received for URI %s", uri),
+	}
+}
+
 // NoShownMessage asserts that the editor has not received a ShowMessage.
 func NoShownMessage(containing string) Expectation {
 	check := func(s State) (Verdict, string) {
@@ -30,6 +30,8 @@ type ClientHooks struct {
 	OnShowMessageRequest     func(context.Context, *protocol.ShowMessageRequestParams) error
 	OnRegisterCapability     func(context.Context, *protocol.RegistrationParams) error
 	OnUnregisterCapability   func(context.Context, *protocol.UnregistrationParams) error
+
+	OnTextDocumentContentRefresh func(context.Context, *protocol.TextDocumentContentRefreshParams) error
 }
 
 // Client is an implementation of the [protocol.Client] interface
@@ -73,7 +75,10 @@ func (c *Client) SemanticTokensRefresh(context.Context) error { return nil }
 
 func (c *Client) LogTrace(context.Context, *protocol.LogTraceParams) error { return nil }
 
-func (c *Client) TextDocumentContentRefresh(context.Context, *protocol.TextDocumentContentRefreshParams) error {
+func (c *Client) TextDocumentContentRefresh(ctx context.Context, params *protocol.TextDocumentContentRefreshParams) error {
+	if c.hooks.OnTextDocumentContentRefresh != nil {
+		return c.hooks.OnTextDocumentContentRefresh(ctx, params)
+	}
 	return nil
 }
 
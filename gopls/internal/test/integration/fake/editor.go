@@ -1087,6 +1087,18 @@ func (e *Editor) Diagnostics(ctx context.Context, path string) ([]protocol.Diagn
 	return report.Items, nil
 }
 
+// WorkspaceDiagnostics issues a workspace/diagnostic request, passing the
+// given result IDs from a previous request.
+func (e *Editor) WorkspaceDiagnostics(ctx context.Context, previous []protocol.PreviousResultID) (*protocol.WorkspaceDiagnosticReport, error) {
+	if e.Server == nil {
+		return nil, errors.New("not connected")
+	}
+	params := &protocol.WorkspaceDiagnosticParams{
+		PreviousResultIds: protocol.NonNilSlice(previous),
+	}
+	return e.Server.DiagnosticWorkspace(ctx, params)
+}
+
 // GetQuickFixes returns the available quick fix code actions.
 func (e *Editor) GetQuickFixes(ctx context.Context, loc protocol.Location, diagnostics []protocol.Diagnostic) ([]protocol.CodeAction, error) {
 	return e.CodeActions(ctx, loc, diagnostics, protocol.QuickFix, protocol.SourceFixAll)
@@ -1496,6 +1508,38 @@ func (e *Editor) RenameFile(ctx context.Context, oldPath, newPath string) error
 	return nil
 }
 
+// MoveFile simulates the user moving a file or directory in the
+// editor's file tree. Unlike RenameFile, it first sends a
+// workspace/willRenameFiles request and applies the resulting edits,
+// then performs the renaming, then sends a workspace/didRenameFiles
+// notification.
+func (e *Editor) MoveFile(ctx context.Context, oldPath, newPath string) error {
+	params := &protocol.RenameFilesParams{
+		Files: []protocol.FileRename{{
+			OldURI: string(e.sandbox.Workdir.URI(oldPath)),
+			NewURI: string(e.sandbox.Workdir.URI(newPath)),
+		}},
+	}
+	if e.Server != nil {
+		wsedit, err := e.Server.WillRenameFiles(ctx, params)
+		if err != nil {
+			return err
+		}
+		if wsedit != nil {
+			if err := e.applyWorkspaceEdit(ctx, wsedit); err != nil {
+				return err
+			}
+		}
+	}
+	if err := e.RenameFile(ctx, oldPath, newPath); err != nil {
+		return err
+	}
+	if e.Server != nil {
+		return e.Server.DidRenameFiles(ctx, params)
+	}
+	return nil
+}
+
 // renameBuffers renames in-memory buffers affected by the renaming of
 // oldPath->newPath, returning the resulting text documents that must be closed
 // and opened over the LSP.
@@ -1563,9 +1607,24 @@ func (e *Editor) applyWorkspaceEdit(ctx context.Context, wsedit *protocol.Worksp
 			return e.RenameFile(ctx, old, new)
 
 		case change.CreateFile != nil:
+			// Create an empty file on disk, rather than a buffer, so
+			// that a subsequent TextDocumentEdit, which must refer to
+			// version 0 of the new file, opens it (see
+			// applyTextDocumentEdit).
 			path := uriToPath(change.CreateFile.URI)
-			if err := e.CreateBuffer(ctx, path, ""); err != nil {
-				return err // e.g. already exists
+			if _, err := e.sandbox.Workdir.ReadFile(path); err == nil {
+				opts := change.CreateFile.Options
+				switch {
+				case opts != nil && opts.Overwrite:
+					_ = e.CloseBuffer(ctx, path) // returns error if not open
+				case opts != nil && opts.IgnoreIfExists:
+					continue
+				default:
+					return fmt.Errorf("creating %s: file already exists", path)
+				}
+			}
+			if err := e.sandbox.Workdir.WriteFile(ctx, path, ""); err != nil {
+				return err
 			}
 
 		case change.DeleteFile != nil:
@@ -59,3 +59,51 @@ func main() {
 		t.Errorf("got text %q, want %q", got, want)
 	}
 }
+
+func TestApplyWorkspaceEditCreateFile(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		path      string
+		opts      *protocol.CreateFileOptions
+		wantErr   bool
+		wantEmpty bool // whether the file is empty afterwards
+	}{
+		{"new", "new.go", nil, false, true},
+		{"exists", "main.go", nil, true, false},
+		{"ignore", "main.go", &protocol.CreateFileOptions{IgnoreIfExists: true}, false, false},
+		{"overwrite", "main.go", &protocol.CreateFileOptions{Overwrite: true}, false, true},
+		{"overwrite wins", "main.go", &protocol.CreateFileOptions{Overwrite: true, IgnoreIfExists: true}, false, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ws, err := NewSandbox(&SandboxConfig{Files: UnpackTxt(exampleProgram)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ws.Close()
+			ctx := context.Background()
+			editor := NewEditor(ws, EditorConfig{})
+			err = editor.applyWorkspaceEdit(ctx, &protocol.WorkspaceEdit{
+				DocumentChanges: []protocol.DocumentChange{{
+					CreateFile: &protocol.CreateFile{
+						Kind:    "create",
+						URI:     ws.Workdir.URI(test.path),
+						Options: test.opts,
+					},
+				}},
+			})
+			if (err != nil) != test.wantErr {
+				t.Fatalf("applyWorkspaceEdit returned error %v, want error: %t", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := ws.Workdir.ReadFile(test.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if empty := len(got) == 0; empty != test.wantEmpty {
+				t.Errorf("%s contains %q, want empty: %t", test.path, got, test.wantEmpty)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+// TestChangeSignature exercises the gopls.change_signature command with
+// signature changes that have no code action.
+func TestChangeSignature(t *testing.T) {
+	const files = `
+-- go.mod --
+module example.com
+
+go 1.21
+
+-- a/a.go --
+package a
+
+import "context"
+
+var _ context.Context
+
+func F(x, y int) (int, error) {
+	return x + y, nil
+}
+
+type I interface {
+	M(x int) error
+}
+
+type T struct{}
+
+func (T) M(x int) error { return nil }
+
+type U struct{}
+
+func (U) M(x int) error { return nil }
+
+-- b/b.go --
+package b
+
+import "example.com/a"
+
+func _() {
+	n, err := a.F(1, 2)
+	_, _ = n, err
+	a.T{}.M(1)
+	a.U{}.M(2)
+}
+`
+	// old and new refer to fields of the old and new signature.
+	old := func(i int) command.ChangeSignatureParam { return command.ChangeSignatureParam{OldIndex: i} }
+
+	tests := []struct {
+		name       string
+		re         string // regexp for location in a/a.go
+		newParams  []command.ChangeSignatureParam
+		newResults []command.ChangeSignatureParam
+		interfaces bool              // UpdateInterfaces
+		want       map[string]string // substrings of each file
+	}{
+		{
+			name:      "add parameter",
+			re:        "F",
+			newParams: []command.ChangeSignatureParam{old(0), old(1), {NewField: "z int", Default: "3"}},
+			want: map[string]string{
+				"a/a.go": "func F(x, y, z int) (int, error) {",
+				"b/b.go": "n, err := a.F(1, 2, 3)",
+			},
+		},
+		{
+			name:      "rename and reorder parameters",
+			re:        "F",
+			newParams: []command.ChangeSignatureParam{{OldIndex: 1, Name: "b"}, old(0)},
+			want: map[string]string{
+				"a/a.go": "func F(b, x int) (int, error) {\n\treturn x + b, nil",
+				"b/b.go": "n, err := a.F(2, 1)",
+			},
+		},
+		{
+			name:       "reorder results",
+			re:         "F",
+			newParams:  []command.ChangeSignatureParam{old(0), old(1)},
+			newResults: []command.ChangeSignatureParam{old(1), {NewField: "bool", Default: "true"}, old(0)},
+			want: map[string]string{
+				"a/a.go": "func F(x, y int) (error, bool, int) {\n\treturn nil, true, x + y",
+			},
+		},
+		{
+			name:       "remove result",
+			re:         "F",
+			newParams:  []command.ChangeSignatureParam{old(0), old(1)},
+			newResults: []command.ChangeSignatureParam{old(0)},
+			want: map[string]string{
+				"a/a.go": "func F(x, y int) int {\n\treturn x + y\n}",
+				"b/b.go": "n, err := a.F(1, 2), error(nil)",
+			},
+		},
+		{
+			name:       "add parameter to interface method",
+			re:         `\(T\) (M)`,
+			newParams:  []command.ChangeSignatureParam{{NewField: "ctx context.Context", Default: "context.TODO()"}, old(0)},
+			interfaces: true,
+			want: map[string]string{
+				"a/a.go": "M(ctx context.Context, x int) error\n}",
+				"b/b.go": "a.U{}.M(context.TODO(), 2)",
+			},
+		},
+		{
+			name:      "rename parameter of method only",
+			re:        `\(T\) (M)`,
+			newParams: []command.ChangeSignatureParam{{OldIndex: 0, Name: "y"}},
+			want: map[string]string{
+				"a/a.go": "M(x int) error\n}\n\ntype T struct{}\n\nfunc (T) M(y int) error",
+				"b/b.go": "a.U{}.M(2)",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			Run(t, files, func(t *testing.T, env *Env) {
+				env.OpenFile("a/a.go")
+				env.OpenFile("b/b.go")
+				loc := env.RegexpSearch("a/a.go", test.re)
+				cmd := command.NewChangeSignatureCommand("Change signature", command.ChangeSignatureArgs{
+					Location:         loc,
+					NewParams:        test.newParams,
+					NewResults:       test.newResults,
+					UpdateInterfaces: test.interfaces,
+				})
+				env.ExecuteCommand(&protocol.ExecuteCommandParams{
+					Command:   cmd.Command,
+					Arguments: cmd.Arguments,
+				}, nil)
+				for name, want := range test.want {
+					if got := env.BufferText(name); !strings.Contains(got, want) {
+						t.Errorf("%s does not contain %q:\n%s", name, want, got)
+					}
+				}
+				env.AfterChange(NoDiagnostics())
+			})
+		})
+	}
+}
+
+// TestChangeSignatureConflicts checks that gopls.change_signature reports
+// the references that it cannot update.
+func TestChangeSignatureConflicts(t *testing.T) {
+	const files = `
+-- go.mod --
+module example.com
+
+go 1.21
+
+-- a/a.go --
+package a
+
+type I interface {
+	M(x int)
+}
+
+type T struct{}
+
+func (T) M(x int) {}
+
+func _(i I) {
+	i.M(1)
+	f := T{}.M
+	f(2)
+}
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		cmd := command.NewChangeSignatureCommand("Change signature", command.ChangeSignatureArgs{
+			Location:         env.RegexpSearch("a/a.go", `\(T\) (M)`),
+			NewParams:        []command.ChangeSignatureParam{{NewField: "y int"}, {OldIndex: 0}},
+			UpdateInterfaces: true,
+		})
+		err := env.Editor.ExecuteCommand(env.Ctx, &protocol.ExecuteCommandParams{
+			Command:   cmd.Command,
+			Arguments: cmd.Arguments,
+		}, nil)
+		if err == nil {
+			t.Fatalf("change signature succeeded unexpectedly:\n%s", env.BufferText("a/a.go"))
+		}
+		for _, want := range []string{
+			"a.go:12:4: dynamic call",
+			"a.go:13:11: non-call function reference",
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("error %q does not contain %q", err, want)
+			}
+		}
+	})
+}
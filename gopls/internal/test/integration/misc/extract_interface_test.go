@@ -0,0 +1,196 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+const extractInterfaceFiles = `
+-- go.mod --
+module example.com
+
+go 1.21
+
+-- a/a.go --
+package a
+
+import "example.com/dep"
+
+type Store struct{ Size int }
+
+func (s *Store) Get(key string) (dep.Value, bool) { return 0, false }
+
+func (s Store) Len() int { return s.Size }
+
+func (s *Store) Clone() *Store { return s }
+
+-- dep/dep.go --
+package dep
+
+type Value int
+
+-- c/c.go --
+package c
+
+import "example.com/a"
+
+func Use(s *a.Store) int {
+	s.Get("k")
+	return s.Len()
+}
+
+func UseValue(s a.Store) int {
+	return s.Len()
+}
+
+func UseField(s *a.Store) int {
+	return s.Size
+}
+
+func _() {
+	Use(&a.Store{})
+}
+
+-- d/d.go --
+package d
+
+import "example.com/a"
+
+func Len(s a.Store) int { return s.Len() }
+`
+
+// TestExtractInterfaceToConsumer exercises the gopls.extract_interface
+// command with a destination in a package that uses the concrete type.
+func TestExtractInterfaceToConsumer(t *testing.T) {
+	Run(t, extractInterfaceFiles, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		env.OpenFile("c/c.go")
+		cmd := command.NewExtractInterfaceCommand("Extract interface", command.ExtractInterfaceArgs{
+			Location: env.RegexpSearch("a/a.go", "Store"),
+			Name:     "Getter",
+			Methods:  []string{"Get", "Len"},
+			Dest:     env.Sandbox.Workdir.URI("c/c.go"),
+			UseSites: []protocol.Location{env.RegexpSearch("c/c.go", `Use\((s)`)},
+		})
+		env.ExecuteCommand(&protocol.ExecuteCommandParams{
+			Command:   cmd.Command,
+			Arguments: cmd.Arguments,
+		}, nil)
+		got := env.BufferText("c/c.go")
+		for _, want := range []string{
+			`import (
+	"example.com/a"
+	"example.com/dep"
+)`,
+			"func Use(s Getter) int {",
+			`type Getter interface {
+	Get(key string) (dep.Value, bool)
+	Len() int
+}`,
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("c/c.go does not contain %q:\n%s", want, got)
+			}
+		}
+		env.AfterChange(NoDiagnostics())
+	})
+}
+
+// TestExtractInterfaceRemovesImport checks that gopls.extract_interface
+// deletes imports that are unused after changing the use sites.
+func TestExtractInterfaceRemovesImport(t *testing.T) {
+	Run(t, extractInterfaceFiles, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		env.OpenFile("d/d.go")
+		cmd := command.NewExtractInterfaceCommand("Extract interface", command.ExtractInterfaceArgs{
+			Location: env.RegexpSearch("a/a.go", "Store"),
+			Name:     "Lener",
+			Methods:  []string{"Len"},
+			Dest:     env.Sandbox.Workdir.URI("d/d.go"),
+			UseSites: []protocol.Location{env.RegexpSearch("d/d.go", `s a.Store`)},
+		})
+		env.ExecuteCommand(&protocol.ExecuteCommandParams{
+			Command:   cmd.Command,
+			Arguments: cmd.Arguments,
+		}, nil)
+		const want = `package d
+
+func Len(s Lener) int { return s.Len() }
+
+type Lener interface {
+	Len() int
+}
+`
+		if got := env.BufferText("d/d.go"); got != want {
+			t.Errorf("d/d.go = %q, want %q", got, want)
+		}
+		env.AfterChange(NoDiagnostics())
+	})
+}
+
+// TestExtractInterfaceErrors checks that gopls.extract_interface
+// rejects use sites that cannot be changed to the new interface.
+func TestExtractInterfaceErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    command.ExtractInterfaceArgs
+		useSite string // regexp for location in c/c.go
+		wantErr string
+	}{
+		{
+			name:    "field access",
+			args:    command.ExtractInterfaceArgs{Dest: "c/c.go"},
+			useSite: `UseField\((s)`,
+			wantErr: "c.go:15:9: parameter s is used other than to call methods of StoreInterface",
+		},
+		{
+			name:    "pointer receiver",
+			args:    command.ExtractInterfaceArgs{Methods: []string{"Get", "Len"}, Dest: "c/c.go"},
+			useSite: `UseValue\((s)`,
+			wantErr: "a.Store does not implement StoreInterface (method Get has pointer receiver)",
+		},
+		{
+			name:    "import cycle",
+			args:    command.ExtractInterfaceArgs{Dest: "dep/dep.go"},
+			wantErr: "importing example.com/a would create an import cycle",
+		},
+		{
+			name:    "unknown method",
+			args:    command.ExtractInterfaceArgs{Methods: []string{"Put"}},
+			wantErr: "Store has no exported method Put",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			Run(t, extractInterfaceFiles, func(t *testing.T, env *Env) {
+				env.OpenFile("a/a.go")
+				args := test.args
+				args.Location = env.RegexpSearch("a/a.go", "Store")
+				if args.Dest != "" {
+					args.Dest = env.Sandbox.Workdir.URI(string(args.Dest))
+				}
+				if test.useSite != "" {
+					env.OpenFile("c/c.go")
+					args.UseSites = []protocol.Location{env.RegexpSearch("c/c.go", test.useSite)}
+				}
+				cmd := command.NewExtractInterfaceCommand("Extract interface", args)
+				err := env.Editor.ExecuteCommand(env.Ctx, &protocol.ExecuteCommandParams{
+					Command:   cmd.Command,
+					Arguments: cmd.Arguments,
+				}, nil)
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("ExtractInterface returned error %v, want %q", err, test.wantErr)
+				}
+			})
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/gopls/internal/test/compare"
 	. "golang.org/x/tools/gopls/internal/test/integration"
 )
@@ -388,3 +389,140 @@ func _() {
 		env.FormatBuffer("foo.go") // golang/go#61692: must not panic
 	})
 }
+
+func TestRangeFormatting(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- a.go --
+package a
+
+func f(  ) {
+	x :=  1
+	y :=   2
+	_, _ = x, y
+}
+
+func g(  ) {}
+-- a.go.golden --
+package a
+
+func f(  ) {
+	x := 1
+	y :=   2
+	_, _ = x, y
+}
+
+func g(  ) {}
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.go")
+		loc := env.RegexpSearch("a.go", "x :=")
+		params := &protocol.DocumentRangeFormattingParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: loc.URI},
+			Range:        loc.Range,
+		}
+		edits, err := env.Editor.Server.RangeFormatting(env.Ctx, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env.EditBuffer("a.go", edits...)
+		got := env.BufferText("a.go")
+		want := env.ReadWorkspaceFile("a.go.golden")
+		if got != want {
+			t.Errorf("unexpected range formatting result:\n%s", compare.Text(want, got))
+		}
+	})
+}
+
+func TestRangeFormattingImports(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- a.go --
+package a
+
+import (
+	"os"
+	"fmt"
+)
+
+func f(  ) { fmt.Println() }
+-- a.go.golden --
+package a
+
+import (
+	"fmt"
+)
+
+func f(  ) { fmt.Println() }
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.go")
+		loc := env.RegexpSearch("a.go", `import \((?:.|\n)*?\n\)`)
+		params := &protocol.DocumentRangeFormattingParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: loc.URI},
+			Range:        loc.Range,
+		}
+		edits, err := env.Editor.Server.RangeFormatting(env.Ctx, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env.EditBuffer("a.go", edits...)
+		got := env.BufferText("a.go")
+		want := env.ReadWorkspaceFile("a.go.golden")
+		if got != want {
+			t.Errorf("unexpected range formatting result:\n%s", compare.Text(want, got))
+		}
+	})
+}
+
+func TestOnTypeFormatting(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- a.go --
+package a
+
+var v  =  1
+
+func f(  ) {
+	x :=  1
+	_ = x
+}
+-- a.go.golden --
+package a
+
+var v  =  1
+
+func f() {
+	x := 1
+	_ = x
+}
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.go")
+		loc := env.RegexpSearch("a.go", `\n}()`)
+		params := &protocol.DocumentOnTypeFormattingParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: loc.URI},
+			Position:     loc.Range.Start,
+			Ch:           "}",
+		}
+		edits, err := env.Editor.Server.OnTypeFormatting(env.Ctx, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env.EditBuffer("a.go", edits...)
+		got := env.BufferText("a.go")
+		want := env.ReadWorkspaceFile("a.go.golden")
+		if got != want {
+			t.Errorf("unexpected on-type formatting result:\n%s", compare.Text(want, got))
+		}
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/gopls/internal/protocol"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+func TestInlineValues(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- a.go --
+package a
+
+var global int
+
+type point struct{ x, y int }
+
+func f(p point, n int) int {
+	sum := p.x + n
+	for i := range n {
+		sum += i
+	}
+	if n := global; n > 0 { // shadows parameter n
+		sum += n
+	}
+	later := sum // stop here
+	return later
+}
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.go")
+		fileLoc := env.RegexpSearch("a.go", `(?s)package.*`)
+		stopLoc := env.RegexpSearch("a.go", "later := sum")
+		values, err := env.Editor.Server.InlineValue(env.Ctx, &protocol.InlineValueParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: fileLoc.URI},
+			Range:        fileLoc.Range,
+			Context: protocol.InlineValueContext{
+				StoppedLocation: protocol.Range{Start: stopLoc.Range.Start, End: stopLoc.Range.Start},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The LSP InlineValue union cannot be reliably decoded
+		// (a variable lookup is indistinguishable from an
+		// expression without an explicit Expression), so describe
+		// each value by its JSON fields and the text of its range.
+		content := env.BufferText("a.go")
+		mapper := protocol.NewMapper(fileLoc.URI, []byte(content))
+		var got []string
+		for _, v := range values {
+			data, err := json.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fields struct {
+				Range      protocol.Range
+				Expression string
+			}
+			if err := json.Unmarshal(data, &fields); err != nil {
+				t.Fatal(err)
+			}
+			start, end, err := mapper.RangeOffsets(fields.Range)
+			if err != nil {
+				t.Fatal(err)
+			}
+			line := fields.Range.Start.Line + 1
+			if fields.Expression != "" {
+				got = append(got, fmt.Sprintf("%d: expr %s", line, fields.Expression))
+			} else {
+				got = append(got, fmt.Sprintf("%d: var %s", line, content[start:end]))
+			}
+		}
+		want := []string{
+			"7: var p",
+			"7: var n",
+			"8: var sum",
+			"8: expr p.x",
+			"8: var p",
+			"8: var n",
+			"9: var n",
+			"10: var sum",
+			"13: var sum",
+			"15: var sum",
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("InlineValue mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
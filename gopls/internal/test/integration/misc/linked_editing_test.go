@@ -0,0 +1,110 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/gopls/internal/protocol"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+func TestLinkedEditingRange(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- a.go --
+package a
+
+var global int
+
+type T struct {
+	Name string ` + "`json:\"name,omitempty\" yaml:\"name\" xml:\"other\"`" + `
+}
+
+func f[P any](x P, n int) (result int) {
+	count := n + global
+outer:
+	for range count {
+		count--
+		break outer
+	}
+	result = count
+	return
+}
+
+func g() int {
+	type L struct{ n int }
+	type E struct{ L }
+	var e E
+	return e.L.n
+}
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.go")
+		content := env.BufferText("a.go")
+		mapper := protocol.NewMapper(env.Sandbox.Workdir.URI("a.go"), []byte(content))
+
+		const identPattern = `[A-Za-z_\u0080-\uFFFF][A-Za-z0-9_\u0080-\uFFFF]*`
+		tests := []struct {
+			re          string // regexp locating the cursor
+			want        []string
+			wordPattern string
+		}{
+			{`(count) :=`, []string{"10:2", "12:12", "13:3", "16:11"}, identPattern},
+			{`(outer):`, []string{"11:1", "14:9"}, identPattern},
+			{`f\[(P)`, []string{"9:8", "9:17"}, identPattern},
+			{`(result) int`, []string{"9:28", "16:2"}, identPattern},
+			{`yaml:"(name)`, []string{"6:21", "6:43"}, `[^\s",]+`},
+			{`n \+ (global)`, nil, ""},   // package-level
+			{`(Name) string`, nil, ""},   // field
+			{`xml:"(other)`, nil, ""},    // only one occurrence
+			{`type (L) struct`, nil, ""}, // local type, named by embedded field e.L
+		}
+		for _, test := range tests {
+			loc := env.RegexpSearch("a.go", test.re)
+			got, err := env.Editor.Server.LinkedEditingRange(env.Ctx, &protocol.LinkedEditingRangeParams{
+				TextDocumentPositionParams: protocol.LocationTextDocumentPositionParams(loc),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got == nil {
+				if test.want != nil {
+					t.Errorf("LinkedEditingRange(%q) = nil, want %v", test.re, test.want)
+				}
+				continue
+			}
+			// Each linked range has the same text as the identifier
+			// or tag name at the cursor.
+			locStart, locEnd, err := mapper.RangeOffsets(loc.Range)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantText := content[locStart:locEnd]
+			var ranges []string
+			for _, rng := range got.Ranges {
+				start, end, err := mapper.RangeOffsets(rng)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if text := content[start:end]; text != wantText {
+					t.Errorf("LinkedEditingRange(%q): range has text %q, want %q", test.re, text, wantText)
+				}
+				ranges = append(ranges, fmt.Sprintf("%d:%d", rng.Start.Line+1, rng.Start.Character+1))
+			}
+			if diff := cmp.Diff(test.want, ranges); diff != "" {
+				t.Errorf("LinkedEditingRange(%q) ranges mismatch (-want +got):\n%s", test.re, diff)
+			}
+			if got.WordPattern != test.wordPattern {
+				t.Errorf("LinkedEditingRange(%q).WordPattern = %q, want %q", test.re, got.WordPattern, test.wordPattern)
+			}
+		}
+	})
+}
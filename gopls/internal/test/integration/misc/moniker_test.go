@@ -0,0 +1,145 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/gopls/internal/protocol"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+func TestMoniker(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- a/a.go --
+package a
+
+type T struct{ F int }
+
+func (T) M() {}
+
+func helper() {}
+-- b/b.go --
+package b
+
+import (
+	"fmt"
+
+	"mod.com/a"
+)
+
+func Use[P any](x a.T) int {
+	x.M()
+	fmt.Println(x)
+	var p P
+	_ = p
+	return len("") + x.F
+}
+
+func unexported() {}
+
+func init() {}
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("b/b.go")
+		tests := []struct {
+			re   string // regexp locating the cursor
+			want []string
+		}{
+			{`a\.(T)`, []string{"import global mod.com:mod.com/a:T"}},
+			{`x\.(M)`, []string{"import global mod.com:mod.com/a:T.M0"}},
+			{`x\.(F)`, []string{"import global mod.com:mod.com/a:T.UF0"}},
+			{`fmt\.(Println)`, []string{"import global std:fmt:Println"}},
+			{`(fmt)\.Println`, []string{"import global std:fmt"}},
+			{`"mod.com/(a)"`, []string{"import global mod.com:mod.com/a"}},
+			{`func (Use)`, []string{"export global mod.com:mod.com/b:Use"}},
+			{`func (unexported)`, []string{"local global mod.com:mod.com/b:unexported"}},
+			{`(len)`, []string{"import global std:builtin:len"}},
+			{`Use\[(P)`, []string{"local document mod.com/b:P@53"}},
+			{`var (p)`, []string{"local document mod.com/b:p@101"}},
+			{`(x) a\.T`, []string{"local document mod.com/b:x@60"}},
+			{`func (init)`, []string{"local document mod.com/b:init@164"}},
+			{`(package) b`, nil},
+		}
+		for _, test := range tests {
+			loc := env.RegexpSearch("b/b.go", test.re)
+			monikers, err := env.Editor.Server.Moniker(env.Ctx, &protocol.MonikerParams{
+				TextDocumentPositionParams: protocol.LocationTextDocumentPositionParams(loc),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, m := range monikers {
+				if m.Scheme != "go" {
+					t.Errorf("Moniker(%q): got scheme %q, want %q", test.re, m.Scheme, "go")
+				}
+				got = append(got, fmt.Sprintf("%s %s %s", *m.Kind, m.Unique, m.Identifier))
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Moniker(%q) mismatch (-want +got):\n%s", test.re, diff)
+			}
+		}
+	})
+}
+
+// TestMonikerGOPATH checks that the monikers of symbols in packages
+// that belong to no module are not global, since their package paths
+// are not globally unique, whereas those of the standard library are.
+func TestMonikerGOPATH(t *testing.T) {
+	const files = `
+-- a/a.go --
+package a
+
+import (
+	"fmt"
+
+	"b"
+)
+
+func A() { fmt.Println(b.B) }
+-- b/b.go --
+package b
+
+var B = 1
+`
+	WithOptions(
+		InGOPATH(),
+		EnvVars{"GO111MODULE": "off"},
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		tests := []struct {
+			re   string // regexp locating the cursor
+			want []string
+		}{
+			{`fmt\.(Println)`, []string{"import global std:fmt:Println"}},
+			{`b\.(B)`, []string{"import document b:B"}},
+			{`(b)\.B`, []string{"import document b"}},
+			{`func (A)`, []string{"export document a:A"}},
+		}
+		for _, test := range tests {
+			loc := env.RegexpSearch("a/a.go", test.re)
+			monikers, err := env.Editor.Server.Moniker(env.Ctx, &protocol.MonikerParams{
+				TextDocumentPositionParams: protocol.LocationTextDocumentPositionParams(loc),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, m := range monikers {
+				got = append(got, fmt.Sprintf("%s %s %s", *m.Kind, m.Unique, m.Identifier))
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Moniker(%q) mismatch (-want +got):\n%s", test.re, diff)
+			}
+		}
+	})
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+const moveToPackageFiles = `
+-- go.mod --
+module example.com
+
+go 1.21
+
+-- a/a.go --
+package a
+
+import "fmt"
+
+// Greeting returns a greeting.
+func Greeting(name string) string {
+	return fmt.Sprintf(format, name)
+}
+
+const format = "Hello, %s!"
+
+func Print() {
+	fmt.Println(Greeting("world"))
+}
+
+var scale = 2
+
+func Double(x int) int { return scale * x }
+
+type T struct{ n int }
+
+func (t T) N() int { return t.n }
+
+func Get(t T) int { return t.n }
+
+-- b/b.go --
+package b
+
+import "example.com/a"
+
+func B() string { return a.Greeting("b") }
+
+-- c/c.go --
+package c
+
+import "example.com/a"
+
+func C() int { return a.Double(1) }
+`
+
+// moveToPackage executes the gopls.move_to_package command to move
+// the declarations matching re in a/a.go to directory dir.
+func moveToPackage(env *Env, re, dir string) error {
+	cmd := command.NewMoveToPackageCommand("Move to package", command.MoveToPackageArgs{
+		Location: env.RegexpSearch("a/a.go", re),
+		Dest:     env.Sandbox.Workdir.URI(dir),
+	})
+	return env.Editor.ExecuteCommand(env.Ctx, &protocol.ExecuteCommandParams{
+		Command:   cmd.Command,
+		Arguments: cmd.Arguments,
+	}, nil)
+}
+
+// TestMoveToNewPackage checks that gopls.move_to_package creates a
+// new package and updates the references to the moved declarations.
+func TestMoveToNewPackage(t *testing.T) {
+	Run(t, moveToPackageFiles, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		if err := moveToPackage(env, `(?s)// Greeting.*%s!"`, "greet"); err != nil {
+			t.Fatal(err)
+		}
+		for file, want := range map[string]string{
+			"a/a.go": `package a
+
+import (
+	"example.com/greet"
+	"fmt"
+)
+
+func Print() {
+	fmt.Println(greet.Greeting("world"))
+}
+`,
+			"b/b.go": `package b
+
+import "example.com/greet"
+
+func B() string { return greet.Greeting("b") }
+`,
+			"greet/greeting.go": `package greet
+
+import "fmt"
+
+// Greeting returns a greeting.
+func Greeting(name string) string {
+	return fmt.Sprintf(format, name)
+}
+
+const format = "Hello, %s!"
+`,
+		} {
+			got := env.BufferText(file)
+			if file == "a/a.go" {
+				got = got[:strings.Index(got, "var scale")]
+				want += "\n"
+			}
+			if got != want {
+				t.Errorf("%s = %q, want %q", file, got, want)
+			}
+		}
+		env.SaveBuffer("greet/greeting.go")
+		env.SaveBuffer("a/a.go")
+		env.SaveBuffer("b/b.go")
+		env.AfterChange(NoDiagnostics())
+	})
+}
+
+// TestMoveToExistingPackage checks that gopls.move_to_package exports
+// the declarations referenced across the package boundary, and removes
+// qualifiers from references within the destination package.
+func TestMoveToExistingPackage(t *testing.T) {
+	Run(t, moveToPackageFiles, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		if err := moveToPackage(env, `func Double`, "c"); err != nil {
+			t.Fatal(err)
+		}
+		for file, want := range map[string]string{
+			"c/c.go": `package c
+
+func C() int { return Double(1) }
+`,
+			"c/double.go": `package c
+
+import "example.com/a"
+
+func Double(x int) int { return a.Scale * x }
+`,
+		} {
+			if got := env.BufferText(file); got != want {
+				t.Errorf("%s = %q, want %q", file, got, want)
+			}
+		}
+		if got := env.BufferText("a/a.go"); !strings.Contains(got, "var Scale = 2\n\ntype T") {
+			t.Errorf("a/a.go does not export Scale:\n%s", got)
+		}
+		env.SaveBuffer("c/double.go")
+		env.SaveBuffer("c/c.go")
+		env.SaveBuffer("a/a.go")
+		env.AfterChange(NoDiagnostics())
+	})
+}
+
+// TestMoveToPackageErrors checks that gopls.move_to_package rejects
+// moves that would break the program.
+func TestMoveToPackageErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		re      string // regexp for selection in a/a.go
+		dir     string
+		wantErr string
+	}{
+		{"import cycle", `func Greeting`, "greet", "package example.com/a would import example.com/greet, which depends on example.com/a, creating an import cycle"},
+		{"method", `func \(t T\) N`, "greet", "cannot move method N without its receiver type T"},
+		{"type", `type`, "greet", "cannot move type T without its method N"},
+		{"unexported field", `func Get`, "greet", "a.go:24:30: cannot move declarations: reference to unexported n would cross the package boundary"},
+		{"internal", `func Double`, "b/internal/x", "package example.com/c may not import internal package example.com/b/internal/x"},
+		{"same directory", `func Double`, "a", "declarations are already in directory"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			Run(t, moveToPackageFiles, func(t *testing.T, env *Env) {
+				env.OpenFile("a/a.go")
+				err := moveToPackage(env, test.re, test.dir)
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("MoveToPackage returned error %v, want %q", err, test.wantErr)
+				}
+			})
+		})
+	}
+}
+
+// TestMoveToPackageDeletesImport checks that gopls.move_to_package
+// deletes the imports that become unused in the source file, removing
+// the parentheses around a sole remaining import.
+func TestMoveToPackageDeletesImport(t *testing.T) {
+	const files = `
+-- go.mod --
+module example.com
+
+go 1.21
+
+-- a/a.go --
+package a
+
+import (
+	"fmt"
+	"strings"
+)
+
+func Upper(s string) string { return strings.ToUpper(s) }
+
+func Print() { fmt.Println("a") }
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		if err := moveToPackage(env, `func Upper`, "upper"); err != nil {
+			t.Fatal(err)
+		}
+		const want = `package a
+
+import "fmt"
+
+func Print() { fmt.Println("a") }
+`
+		if got := env.BufferText("a/a.go"); got != want {
+			t.Errorf("a/a.go = %q, want %q", got, want)
+		}
+	})
+}
@@ -919,3 +919,101 @@ func checkTestdata(t *testing.T, env *Env) {
 		}
 	}
 }
+
+func TestMoveDirectory(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.18
+-- lib/a.go --
+package lib
+
+import "mod.com/lib/nested"
+
+const A = 1 + nested.B
+-- lib/nested/a.go --
+package nested
+
+const B = 1
+-- util/util.go --
+package helpers
+
+const H = 1
+-- other/other.go --
+package other
+
+import (
+	"mod.com/lib"
+	"mod.com/lib/nested"
+	"mod.com/util"
+)
+
+const C = lib.A + nested.B + helpers.H
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.MoveFile("lib", "libx")
+		env.MoveFile("util", "tools")
+
+		env.RegexpSearch("libx/a.go", "package libx")
+		env.RegexpSearch("libx/a.go", `import "mod.com/libx/nested"`)
+		env.RegexpSearch("other/other.go", `"mod.com/libx"`)
+		env.RegexpSearch("other/other.go", `"mod.com/libx/nested"`)
+		env.RegexpSearch("other/other.go", `libx.A \+ nested.B`)
+
+		// The package clause of a package not named after its
+		// directory is left alone.
+		env.RegexpSearch("other/other.go", `"mod.com/tools"`)
+		env.RegexpSearch("other/other.go", `helpers.H`)
+		env.OpenFile("tools/util.go")
+		env.RegexpSearch("tools/util.go", "package helpers")
+
+		env.AfterChange(NoDiagnostics())
+	})
+}
+
+func TestWillDeleteFiles(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.18
+-- lib/a.go --
+package lib
+
+const A = 1
+-- lib/b.go --
+package lib
+
+const B = A
+-- lib/unused.go --
+package lib
+
+const unused = 0
+-- other/other.go --
+package other
+
+import "mod.com/lib"
+
+const C = lib.B
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		willDelete := func(path string) {
+			params := &protocol.DeleteFilesParams{
+				Files: []protocol.FileDelete{{URI: string(env.Sandbox.Workdir.URI(path))}},
+			}
+			if _, err := env.Editor.Server.WillDeleteFiles(env.Ctx, params); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		willDelete("lib/unused.go")
+		env.Await(NoShownMessage("break references"))
+
+		willDelete("lib/a.go")
+		env.Await(ShownMessage("A (referenced from b.go)"))
+
+		willDelete("lib")
+		env.Await(ShownMessage("B (referenced from other.go)"))
+	})
+}
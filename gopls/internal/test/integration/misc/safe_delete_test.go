@@ -0,0 +1,129 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+const safeDeleteFiles = `
+-- go.mod --
+module example.com
+
+go 1.21
+
+-- a/a.go --
+package a
+
+// Helper is used only by tests.
+func Helper() int { return 1 }
+
+func Other() int { return 2 }
+
+-- a/a_test.go --
+package a
+
+import "testing"
+
+func TestHelper(t *testing.T) {
+	if Helper() != 1 {
+		t.Fatal("wrong")
+	}
+}
+
+func TestOther(t *testing.T) {
+	if Other() != 2 {
+		t.Fatal("wrong")
+	}
+}
+
+-- a/x_test.go --
+package a_test
+
+import (
+	"fmt"
+
+	"example.com/a"
+)
+
+func ExampleHelper() {
+	fmt.Println(a.Helper())
+}
+
+-- a/y_test.go --
+package a
+
+func other() int { return Other() }
+`
+
+// safeDelete executes the gopls.safe_delete command to delete the
+// declaration whose name matches re in a/a.go.
+func safeDelete(env *Env, re string, deleteTests bool) error {
+	cmd := command.NewSafeDeleteCommand("Safely delete", command.SafeDeleteArgs{
+		Location:    env.RegexpSearch("a/a.go", re),
+		DeleteTests: deleteTests,
+	})
+	return env.Editor.ExecuteCommand(env.Ctx, &protocol.ExecuteCommandParams{
+		Command:   cmd.Command,
+		Arguments: cmd.Arguments,
+	}, nil)
+}
+
+// TestSafeDeleteWithTests checks that gopls.safe_delete deletes the
+// tests that use a declaration, and the imports they leave unused.
+func TestSafeDeleteWithTests(t *testing.T) {
+	Run(t, safeDeleteFiles, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		err := safeDelete(env, `func (Helper)`, false)
+		if err == nil || !strings.Contains(err.Error(), "cannot delete func Helper, which is referenced only by tests:") {
+			t.Fatalf("SafeDelete returned error %v, want references in tests", err)
+		}
+
+		if err := safeDelete(env, `func (Helper)`, true); err != nil {
+			t.Fatal(err)
+		}
+		for file, want := range map[string]string{
+			"a/a.go": `package a
+
+func Other() int { return 2 }
+
+`,
+			"a/a_test.go": `package a
+
+import "testing"
+
+func TestOther(t *testing.T) {
+	if Other() != 2 {
+		t.Fatal("wrong")
+	}
+}
+
+`,
+			"a/x_test.go": `package a_test
+`,
+		} {
+			if got := env.BufferText(file); got != want {
+				t.Errorf("%s = %q, want %q", file, got, want)
+			}
+		}
+	})
+}
+
+// TestSafeDeleteNonTestReference checks that gopls.safe_delete does
+// not delete functions in test files other than tests.
+func TestSafeDeleteNonTestReference(t *testing.T) {
+	Run(t, safeDeleteFiles, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		err := safeDelete(env, `func (Other)`, true)
+		if err == nil || !strings.Contains(err.Error(), "y_test.go:3:27, outside a test function") {
+			t.Errorf("SafeDelete returned error %v, want reference outside a test function", err)
+		}
+	})
+}
@@ -5,6 +5,7 @@
 package misc
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -236,3 +237,94 @@ const bad = `
 		}
 	})
 }
+
+func TestSemanticTokensDelta(t *testing.T) {
+	const src = `
+-- go.mod --
+module example.com
+
+go 1.21
+-- main.go --
+package main
+
+func main() {
+	x := 1
+	_ = x
+}
+`
+	WithOptions(
+		Modes(Default),
+		Settings{"semanticTokens": true},
+	).Run(t, src, func(t *testing.T, env *Env) {
+		env.OpenFile("main.go")
+		uri := env.Sandbox.Workdir.URI("main.go")
+		full, err := env.Editor.Server.SemanticTokensFull(env.Ctx, &protocol.SemanticTokensParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if full.ResultID == "" {
+			t.Fatal("SemanticTokensFull returned no result ID")
+		}
+
+		env.RegexpReplace("main.go", "x := 1", "x, y := 1, 2\n\t_ = y")
+		deltaParams := &protocol.SemanticTokensDeltaParams{
+			TextDocument:     protocol.TextDocumentIdentifier{URI: uri},
+			PreviousResultID: full.ResultID,
+		}
+		delta := semanticTokensDelta(t, env, deltaParams)
+		if delta.Edits == nil {
+			t.Fatalf("SemanticTokensFullDelta returned full tokens, want a delta")
+		}
+		if len(delta.Edits) != 1 {
+			t.Fatalf("got %d edits, want 1", len(delta.Edits))
+		}
+
+		// Applying the delta to the previous result must yield the
+		// same tokens as a full request.
+		edit := delta.Edits[0]
+		var got []uint32
+		got = append(got, full.Data[:edit.Start]...)
+		got = append(got, edit.Data...)
+		got = append(got, full.Data[edit.Start+edit.DeleteCount:]...)
+		want, err := env.Editor.Server.SemanticTokensFull(env.Ctx, &protocol.SemanticTokensParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want.Data, got); diff != "" {
+			t.Errorf("delta applied to previous tokens (-want +got):\n%s", diff)
+		}
+
+		// An unknown result ID yields the full tokens.
+		deltaParams.PreviousResultID = "unknown"
+		delta = semanticTokensDelta(t, env, deltaParams)
+		if delta.Edits != nil || len(delta.Data) == 0 {
+			t.Errorf("SemanticTokensFullDelta with unknown ID returned %+v, want full tokens", delta)
+		}
+	})
+}
+
+// semanticTokensDelta calls semanticTokens/full/delta and decodes its
+// result, which is either a SemanticTokens or a SemanticTokensDelta.
+func semanticTokensDelta(t *testing.T, env *Env, params *protocol.SemanticTokensDeltaParams) (res struct {
+	ResultID string                        `json:"resultId"`
+	Data     []uint32                      `json:"data"`
+	Edits    []protocol.SemanticTokensEdit `json:"edits"`
+}) {
+	t.Helper()
+	result, err := env.Editor.Server.SemanticTokensFullDelta(env.Ctx, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &res); err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	"golang.org/x/tools/gopls/internal/settings"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+// TestGenerateStringerVariants checks the -linecomment and -trimprefix
+// variants of the source.generateStringer code action.
+func TestGenerateStringerVariants(t *testing.T) {
+	const files = `
+-- go.mod --
+module example.com
+
+go 1.21
+
+-- a/fruit.go --
+package a
+
+type Fruit int
+
+const (
+	FruitApple  Fruit = iota // apple
+	FruitBanana
+)
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a/fruit.go")
+		loc := env.RegexpSearch("a/fruit.go", `type (Fruit)`)
+		var titles []string
+		for _, action := range env.CodeAction(loc, nil, protocol.CodeActionInvoked) {
+			if action.Kind == settings.GenerateStringer {
+				titles = append(titles, action.Title)
+			}
+		}
+		want := []string{
+			"Generate String method for Fruit",
+			"Generate String method for Fruit using line comments",
+			"Generate String method for Fruit trimming prefix Fruit",
+		}
+		if !slices.Equal(titles, want) {
+			t.Fatalf("got code actions %q, want %q", titles, want)
+		}
+
+		cmd := command.NewGenerateStringerCommand("", command.GenerateStringerArgs{
+			Location:   loc,
+			TrimPrefix: "Fruit",
+		})
+		if err := env.Editor.ExecuteCommand(env.Ctx, &protocol.ExecuteCommandParams{
+			Command:   cmd.Command,
+			Arguments: cmd.Arguments,
+		}, nil); err != nil {
+			t.Fatal(err)
+		}
+		got := env.BufferText("a/fruit_string.go")
+		for _, want := range []string{
+			`// Code generated by "stringer -type=Fruit -trimprefix=Fruit"; DO NOT EDIT.`,
+			`const _Fruit_name = "AppleBanana"`,
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("fruit_string.go does not contain %q:\n%s", want, got)
+			}
+		}
+	})
+}
+
+// TestStaleStringer checks that the stalestringer analyzer reports a
+// type whose String method was generated before a constant was added,
+// and that its fix regenerates the method.
+func TestStaleStringer(t *testing.T) {
+	const files = `
+-- go.mod --
+module example.com
+
+go 1.21
+
+-- a/pill.go --
+package a
+
+type Pill int
+
+const (
+	Placebo Pill = iota
+	Aspirin
+	Ibuprofen
+)
+
+-- a/pill_string.go --
+// Code generated by "stringer -type=Pill"; DO NOT EDIT.
+
+package a
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[Placebo-0]
+	_ = x[Aspirin-1]
+}
+
+const _Pill_name = "PlaceboAspirin"
+
+var _Pill_index = [...]uint8{0, 7, 14}
+
+func (i Pill) String() string {
+	if i < 0 || i >= Pill(len(_Pill_index)-1) {
+		return "Pill(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _Pill_name[_Pill_index[i]:_Pill_index[i+1]]
+}
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a/pill.go")
+		env.OpenFile("a/pill_string.go")
+		var d protocol.PublishDiagnosticsParams
+		env.AfterChange(
+			Diagnostics(env.AtRegexp("a/pill.go", `type (Pill)`), WithMessage("is stale")),
+			ReadDiagnostics("a/pill.go", &d),
+		)
+		env.ApplyQuickFixes("a/pill.go", d.Diagnostics)
+		env.AfterChange(NoDiagnostics(ForFile("a/pill.go")))
+		if got, want := env.BufferText("a/pill_string.go"), `_Pill_name = "PlaceboAspirinIbuprofen"`; !strings.Contains(got, want) {
+			t.Errorf("pill_string.go does not contain %q:\n%s", want, got)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+const structTagFiles = `
+-- go.mod --
+module example.com
+
+go 1.21
+
+-- a/a.go --
+package a
+
+type T struct {
+	UserID   int    ` + "`json:\"id,omitempty\" db:\"user_id\"`" + `
+	HTTPAddr string ` + "`json:\"addr\"`" + `
+}
+`
+
+// modifyTags executes the gopls.modify_tags command on the struct T
+// in a/a.go.
+func modifyTags(t *testing.T, env *Env, args command.ModifyTagsArgs) {
+	args.Location = env.RegexpSearch("a/a.go", `type (T)`)
+	cmd := command.NewModifyTagsCommand("Modify struct tags", args)
+	if err := env.Editor.ExecuteCommand(env.Ctx, &protocol.ExecuteCommandParams{
+		Command:   cmd.Command,
+		Arguments: cmd.Arguments,
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestModifyTags checks the keys, options, and name transforms of
+// the gopls.modify_tags command.
+func TestModifyTags(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		args command.ModifyTagsArgs
+		want string
+	}{
+		{
+			name: "add with transform",
+			args: command.ModifyTagsArgs{Add: []string{"yaml", "db"}, Transform: "snakecase"},
+			want: "UserID   int    `json:\"id,omitempty\" db:\"user_id\" yaml:\"user_id\"`\n" +
+				"\tHTTPAddr string `json:\"addr\" yaml:\"http_addr\" db:\"http_addr\"`",
+		},
+		{
+			name: "overwrite",
+			args: command.ModifyTagsArgs{Add: []string{"json"}, Overwrite: true, Transform: "pascalcase"},
+			want: "UserID   int    `json:\"UserID,omitempty\" db:\"user_id\"`\n" +
+				"\tHTTPAddr string `json:\"HTTPAddr\"`",
+		},
+		{
+			name: "remove",
+			args: command.ModifyTagsArgs{Remove: []string{"db"}},
+			want: "UserID   int    `json:\"id,omitempty\"`\n" +
+				"\tHTTPAddr string `json:\"addr\"`",
+		},
+		{
+			name: "options",
+			args: command.ModifyTagsArgs{AddOptions: []string{"json=string", "db=omitempty"}, RemoveOptions: []string{"omitempty"}},
+			want: "UserID   int    `json:\"id,string\" db:\"user_id\"`\n" +
+				"\tHTTPAddr string `json:\"addr,string\"`",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			Run(t, structTagFiles, func(t *testing.T, env *Env) {
+				env.OpenFile("a/a.go")
+				modifyTags(t, env, test.args)
+				want := "package a\n\ntype T struct {\n\t" + test.want + "\n}\n"
+				if got := env.BufferText("a/a.go"); got != want {
+					t.Errorf("got:\n%s\nwant:\n%s", got, want)
+				}
+			})
+		})
+	}
+}
+
+// TestModifyTagsTransformSetting checks that gopls.modify_tags uses
+// the structTagTransform setting when no transform is specified.
+func TestModifyTagsTransformSetting(t *testing.T) {
+	WithOptions(
+		Settings{"structTagTransform": "kebabcase"},
+	).Run(t, structTagFiles, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		modifyTags(t, env, command.ModifyTagsArgs{Add: []string{"toml"}})
+		want := "package a\n\ntype T struct {\n" +
+			"\tUserID   int    `json:\"id,omitempty\" db:\"user_id\" toml:\"user-id\"`\n" +
+			"\tHTTPAddr string `json:\"addr\" toml:\"http-addr\"`\n" +
+			"}\n"
+		if got := env.BufferText("a/a.go"); got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+}
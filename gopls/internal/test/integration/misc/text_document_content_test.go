@@ -0,0 +1,142 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/settings"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+	"golang.org/x/tools/internal/testenv"
+)
+
+// textDocumentContentCapabilities declares client support for
+// workspace/textDocumentContent, which causes gopls to serve its
+// reports as text documents instead of web pages.
+const textDocumentContentCapabilities = `{"workspace": {"textDocumentContent": {}}}`
+
+// TestTextDocumentContent exercises the package documentation,
+// free symbols, and assembly reports served through
+// workspace/textDocumentContent.
+func TestTextDocumentContent(t *testing.T) {
+	testenv.NeedsGoCommand1Point(t, 22) // for up-to-date assembly listing
+
+	const files = `
+-- go.mod --
+module example.com
+
+-- a/a.go --
+// Package a is a test package.
+package a
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// A is a constant.
+const A = 1
+
+type G[T any] int
+
+// F is a method.
+func (G[T]) F(x int) {}
+
+func f(buf bytes.Buffer, greeting string) {
+/* « */
+	fmt.Fprintf(&buf, "%s", greeting)
+	buf.WriteByte(0)
+/* » */
+	println(A)
+}
+
+// EOF
+`
+	WithOptions(
+		CapabilitiesJSON([]byte(textDocumentContentCapabilities)),
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+
+		// openReport invokes the code action of the specified kind
+		// at loc, and returns the URI of the document it opens.
+		openReport := func(loc protocol.Location, kind protocol.CodeActionKind) protocol.URI {
+			actions := env.CodeAction(loc, nil, 0)
+			action, err := codeActionByKind(actions, kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			params := &protocol.ExecuteCommandParams{
+				Command:   action.Command.Command,
+				Arguments: action.Command.Arguments,
+			}
+			var result any
+			collectDocs := env.Awaiter.ListenToShownDocuments()
+			env.ExecuteCommand(params, &result)
+			doc := shownDocument(t, collectDocs(), "gopls:")
+			if doc == nil {
+				t.Fatalf("no showDocument call had 'gopls:' prefix")
+			}
+			if doc.External {
+				t.Errorf("showDocument(%s) is external, want internal", doc.URI)
+			}
+			return doc.URI
+		}
+		content := func(uri protocol.URI) []byte {
+			text, err := env.Editor.Server.TextDocumentContent(env.Ctx, &protocol.TextDocumentContentParams{URI: uri})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return []byte(*text)
+		}
+
+		// Package documentation.
+		docURI := openReport(env.Sandbox.Workdir.EntireFile("a/a.go"), settings.GoDoc)
+		doc := content(docURI)
+		checkMatch(t, true, doc, `package a // import "example.com/a"`)
+		checkMatch(t, true, doc, `Package a is a test package.`)
+		checkMatch(t, true, doc, `const A = 1\n    A is a constant.`)
+		checkMatch(t, true, doc, `func \(G\[T\]\) F\(x int\)\n    F is a method.`)
+		checkMatch(t, false, doc, `func f`) // unexported
+
+		// Edits to the package cause the client to refresh the document.
+		env.RegexpReplace("a/a.go", "// EOF", "func NewFunc() {}")
+		env.Await(RefreshedTextDocumentContent(docURI))
+		checkMatch(t, true, content(docURI), `func NewFunc\(\)`)
+
+		// The URI of the documentation of a symbol has a fragment.
+		constURI := openReport(env.RegexpSearch("a/a.go", "const (A)"), settings.GoDoc)
+		if want := "#A"; !strings.HasSuffix(constURI, want) {
+			t.Errorf("doc URI = %s, want suffix %s", constURI, want)
+		}
+		content(constURI)
+
+		// A document of a view that no longer exists is forgotten.
+		cfg := env.Editor.Config()
+		cfg.Settings = map[string]any{"analyses": map[string]any{"unusedparams": false}}
+		env.ChangeConfiguration(cfg)
+		env.RegexpReplace("a/a.go", "func NewFunc", "func NewFunc2")
+		env.AfterChange(NoRefreshedTextDocumentContent(constURI))
+
+		// Free symbols.
+		symbolsURI := openReport(env.RegexpSearch("a/a.go", "«((?:.|\n)*)»"), settings.GoFreeSymbols)
+		symbols := content(symbolsURI)
+		checkMatch(t, true, symbols, `import "fmt" // for Fprintf`)
+		checkMatch(t, true, symbols, `var buf bytes.Buffer`)
+		checkMatch(t, true, symbols, `func buf.WriteByte func\(c byte\) error`)
+		checkMatch(t, true, symbols, `var greeting string`)
+		checkMatch(t, false, symbols, `const A`) // not in selection
+
+		// Assembly.
+		asmURI := openReport(env.RegexpSearch("a/a.go", "println"), settings.GoAssembly)
+		asm := content(asmURI)
+		checkMatch(t, true, asm, `TEXT.*example.com/a.f`)
+		if runtime.GOARCH == "amd64" || runtime.GOARCH == "arm64" {
+			checkMatch(t, true, asm, `CALL	runtime.printlock`)
+		}
+	})
+}
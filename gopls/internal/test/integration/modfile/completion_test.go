@@ -0,0 +1,141 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modfile
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+const completionProxy = `
+-- example.com@v1.2.3/go.mod --
+module example.com
+
+go 1.12
+-- example.com@v1.2.3/blah/blah.go --
+package blah
+
+const Name = "Blah"
+-- example.com@v1.4.0/go.mod --
+module example.com
+
+go 1.12
+-- example.com@v1.4.0/blah/blah.go --
+package blah
+
+const Name = "Blah"
+-- example.org/other@v0.1.0/go.mod --
+module example.org/other
+
+go 1.12
+-- example.org/other@v0.1.0/other.go --
+package other
+`
+
+func TestModFileCompletion(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+
+require example.com v1.2.3
+-- main.go --
+package main
+
+import "example.com/blah"
+
+var _ = blah.Name
+`
+	WithOptions(
+		ProxyFiles(completionProxy),
+		WriteGoSum("."),
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("go.mod")
+		labels := func(content, re string) []string {
+			env.SetBufferContent("go.mod", content)
+			var labels []string
+			for _, item := range env.Completion(env.RegexpSearch("go.mod", re)).Items {
+				labels = append(labels, item.Label)
+			}
+			return labels
+		}
+		tests := []struct {
+			content, re string
+			want        []string
+		}{
+			{"module mod.com\n\nrequire example.\n", `example\.()`, []string{"example.com", "example.org/other"}},
+			{"module mod.com\n\nrequire (\n\texample.o\n)\n", `example\.o()`, []string{"example.org/other"}},
+			{"module mod.com\n\nrequire example.com v\n", `example.com v()`, []string{"v1.4.0", "v1.2.3"}},
+			{"module mod.com\n\nreplace example.com v1.2.3 => example.com v1.4\n", `=> example.com v1.4()`, []string{"v1.4.0"}},
+			{"module mod.com\n\nreplace example.com => ./\n", `=> \./()`, nil},
+			{"module mod.com\n\nrequ\n", `requ()`, []string{"require"}},
+		}
+		for _, test := range tests {
+			if got := labels(test.content, test.re); !slices.Equal(got, test.want) {
+				t.Errorf("completions at %q in %q = %v, want %v", test.re, test.content, got, test.want)
+			}
+		}
+
+		// The go directive offers the version of the local toolchain.
+		for _, v := range labels("module mod.com\n\ngo 1.\n", `go 1\.()`) {
+			if !strings.HasPrefix(v, "1.") {
+				t.Errorf("go directive completion %q does not start with 1.", v)
+			}
+		}
+	})
+}
+
+func TestModFileDefinition(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+
+require (
+	example.com v1.2.3
+	example.org/other v0.1.0
+)
+
+replace example.org/other => ./other
+-- main.go --
+package main
+
+import "example.com/blah"
+
+var _ = blah.Name
+-- other/go.mod --
+module example.org/other
+
+go 1.12
+-- other/other.go --
+package other
+`
+	WithOptions(
+		ProxyFiles(completionProxy),
+		WriteGoSum("."),
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("go.mod")
+
+		// A required module in the module cache.
+		loc := env.GoToDefinition(env.RegexpSearch("go.mod", `example\.com`))
+		if got, want := filepath.ToSlash(loc.URI.Path()), "/example.com@v1.2.3/go.mod"; !strings.HasSuffix(got, want) {
+			t.Errorf("Definition(example.com) = %s, want suffix %s", got, want)
+		}
+
+		// A required module with a local replacement.
+		for _, re := range []string{`(example\.org)/other v0`, `=> \./(other)`} {
+			loc := env.GoToDefinition(env.RegexpSearch("go.mod", re))
+			if got, want := env.Sandbox.Workdir.URIToPath(loc.URI), "other/go.mod"; got != want {
+				t.Errorf("Definition(%s) = %s, want %s", re, got, want)
+			}
+		}
+	})
+}
@@ -6,6 +6,7 @@ package template
 
 import (
 	"os"
+	"slices"
 	"strings"
 	"testing"
 
@@ -255,3 +256,94 @@ Hello {{
 }
 
 // Hover needs tests
+
+func TestTypedTemplates(t *testing.T) {
+	const src = `
+-- go.mod --
+module mod.com
+
+go 1.22
+-- main.go --
+package main
+
+import (
+	"html/template"
+	"os"
+	"slices"
+)
+
+type Page struct {
+	Title string
+	User  *User
+	Items []Item
+}
+
+type User struct {
+	Name, Email string
+}
+
+func (u *User) Greeting() string { return "hello " + u.Name }
+
+type Item struct{ Price int }
+
+var page = template.Must(template.ParseFiles("page.tmpl"))
+
+func main() {
+	page.Execute(os.Stdout, Page{})
+	template.Must(template.ParseFiles("user.tmpl")).ExecuteTemplate(os.Stdout, "user", &User{})
+}
+-- page.tmpl --
+<h1>{{.Title}}</h1>
+{{with .User}}{{.Name}} {{.Greeting}}{{end}}
+{{range $i, $item := .Items}}{{$item.Price}} {{.Cost}}{{end}}
+{{.User.Feild}}
+-- user.tmpl --
+{{define "user"}}{{.Email}} {{.Phone}}{{end}}
+-- item.tmpl --
+{{/* gopls:type mod.com.Item */}}
+{{.Price}} {{.Weight}}
+`
+	WithOptions(
+		Settings{"templateExtensions": []string{"tmpl"}},
+	).Run(t, src, func(t *testing.T, env *Env) {
+		env.OnceMet(
+			InitialWorkspaceLoad,
+			Diagnostics(env.AtRegexp("page.tmpl", "Cost"), WithMessage("can't evaluate field Cost in type main.Item")),
+			Diagnostics(env.AtRegexp("page.tmpl", "Feild"), WithMessage("can't evaluate field Feild in type *main.User")),
+			Diagnostics(env.AtRegexp("user.tmpl", "Phone"), WithMessage("can't evaluate field Phone in type *main.User")),
+			Diagnostics(env.AtRegexp("item.tmpl", "Weight"), WithMessage("can't evaluate field Weight in type main.Item")),
+		)
+		env.OpenFile("page.tmpl")
+		env.AfterChange(
+			NoDiagnostics(env.AtRegexp("page.tmpl", "Title")),
+			NoDiagnostics(env.AtRegexp("page.tmpl", "Greeting")),
+			NoDiagnostics(env.AtRegexp("page.tmpl", "Price")),
+		)
+
+		// Definition of a field of a nested type.
+		loc := env.GoToDefinition(env.RegexpSearch("page.tmpl", `\.(Name)`))
+		if got, want := env.Sandbox.Workdir.URIToPath(loc.URI), "main.go"; got != want {
+			t.Errorf("Definition(.Name) URI = %s, want %s", got, want)
+		}
+		if got, want := loc.Range.Start, env.RegexpSearch("main.go", `(Name), Email`).Range.Start; got != want {
+			t.Errorf("Definition(.Name) = %v, want %v", got, want)
+		}
+
+		// Completion of the fields and methods of the type of the chain.
+		completions := func(content, re string) []string {
+			env.SetBufferContent("page.tmpl", content)
+			var labels []string
+			for _, item := range env.Completion(env.RegexpSearch("page.tmpl", re)).Items {
+				labels = append(labels, item.Label)
+			}
+			slices.Sort(labels)
+			return labels
+		}
+		if got, want := completions("{{.User.}}\n", `\.User\.()`), []string{"Email", "Greeting", "Name"}; !slices.Equal(got, want) {
+			t.Errorf("completions of .User. = %v, want %v", got, want)
+		}
+		if got, want := completions("{{range .Items}}{{.P}}{{end}}\n", `\.P()`), []string{"Price"}; !slices.Equal(got, want) {
+			t.Errorf("completions of .P in range = %v, want %v", got, want)
+		}
+	})
+}
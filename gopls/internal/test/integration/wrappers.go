@@ -256,6 +256,17 @@ func (e *Env) Diagnostics(name string) []protocol.Diagnostic {
 	return diags
 }
 
+// WorkspaceDiagnostics issues a workspace/diagnostic request with the given
+// previous result IDs, calling t.Fatal on any error.
+func (e *Env) WorkspaceDiagnostics(previous []protocol.PreviousResultID) *protocol.WorkspaceDiagnosticReport {
+	e.T.Helper()
+	report, err := e.Editor.WorkspaceDiagnostics(e.Ctx, previous)
+	if err != nil {
+		e.T.Fatal(err)
+	}
+	return report
+}
+
 // GetQuickFixes returns the available quick fix code actions, calling t.Fatal
 // on any error.
 func (e *Env) GetQuickFixes(path string, diagnostics []protocol.Diagnostic) []protocol.CodeAction {
@@ -511,6 +522,14 @@ func (e *Env) RenameFile(oldPath, newPath string) {
 	}
 }
 
+// MoveFile wraps Editor.MoveFile, calling t.Fatal on any error.
+func (e *Env) MoveFile(oldPath, newPath string) {
+	e.T.Helper()
+	if err := e.Editor.MoveFile(e.Ctx, oldPath, newPath); err != nil {
+		e.T.Fatal(err)
+	}
+}
+
 // SignatureHelp wraps Editor.SignatureHelp, calling t.Fatal on error
 func (e *Env) SignatureHelp(loc protocol.Location) *protocol.SignatureHelp {
 	e.T.Helper()
@@ -244,6 +244,10 @@ Here is the list of supported action markers:
     (Failures in the computation to offer a fix do not generally result
     in LSP errors, so this marker is not appropriate for testing them.)
 
+  - rangeformat(location, golden): performs a textDocument/rangeFormatting
+    request for the given range of the enclosing file, and compares
+    against the named golden file, as for @format.
+
   - rank(location, ...string OR completionItem): executes a
     textDocument/completion request at the given location, and verifies that
     each expected completion item occurs in the results, in the expected order.
@@ -276,6 +280,14 @@ Here is the list of supported action markers:
     case the item's label is used). It checks that the resulting snippet
     matches the provided snippet.
 
+  - subtypes(src location, want ...location): makes a
+    typeHierarchy/subtypes query for the type at the src location, and
+    checks that the set of item locations matches want.
+
+  - supertypes(src location, want ...location): makes a
+    typeHierarchy/supertypes query for the type at the src location, and
+    checks that the set of item locations matches want.
+
   - symbol(golden): makes a textDocument/documentSymbol request
     for the enclosing file, formats the response with one symbol
     per line, sorts it, and compares against the named golden file.
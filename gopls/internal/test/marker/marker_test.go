@@ -596,7 +596,10 @@ var actionMarkerFuncs = map[string]func(marker){
 	"selectionrange":   actionMarkerFunc(selectionRangeMarker),
 	"signature":        actionMarkerFunc(signatureMarker),
 	"snippet":          actionMarkerFunc(snippetMarker),
+	"subtypes":         actionMarkerFunc(subtypesMarker),
+	"supertypes":       actionMarkerFunc(supertypesMarker),
 	"quickfix":         actionMarkerFunc(quickfixMarker),
+	"rangeformat":      actionMarkerFunc(rangeFormatMarker),
 	"quickfixerr":      actionMarkerFunc(quickfixErrMarker),
 	"symbol":           actionMarkerFunc(symbolMarker),
 	"token":            actionMarkerFunc(tokenMarker),
@@ -1736,6 +1739,32 @@ func formatMarker(mark marker, golden *Golden) {
 	compareGolden(mark, got, golden)
 }
 
+// rangeFormatMarker implements the @rangeformat marker.
+func rangeFormatMarker(mark marker, loc protocol.Location, golden *Golden) {
+	edits, err := mark.server().RangeFormatting(mark.ctx(), &protocol.DocumentRangeFormattingParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: loc.URI},
+		Range:        loc.Range,
+	})
+	var got []byte
+	if err != nil {
+		got = []byte(err.Error() + "\n") // all golden content is newline terminated
+	} else {
+		filename := mark.path()
+		mapper, err := mark.run.env.Editor.Mapper(filename)
+		if err != nil {
+			mark.errorf("Editor.Mapper(%s) failed: %v", filename, err)
+			return
+		}
+		got, _, err = protocol.ApplyEdits(mapper, edits)
+		if err != nil {
+			mark.errorf("ApplyProtocolEdits failed: %v", err)
+			return
+		}
+	}
+
+	compareGolden(mark, got, golden)
+}
+
 func highlightLocationMarker(mark marker, loc protocol.Location, kindName expect.Identifier) protocol.DocumentHighlight {
 	var kind protocol.DocumentHighlightKind
 	switch kindName {
@@ -2430,6 +2459,49 @@ func outgoingCallsMarker(mark marker, src protocol.Location, want ...protocol.Lo
 	callHierarchy(mark, src, getCalls, want)
 }
 
+func supertypesMarker(mark marker, src protocol.Location, want ...protocol.Location) {
+	getTypes := func(item protocol.TypeHierarchyItem) ([]protocol.TypeHierarchyItem, error) {
+		return mark.server().Supertypes(mark.ctx(), &protocol.TypeHierarchySupertypesParams{Item: item})
+	}
+	typeHierarchy(mark, src, getTypes, want)
+}
+
+func subtypesMarker(mark marker, src protocol.Location, want ...protocol.Location) {
+	getTypes := func(item protocol.TypeHierarchyItem) ([]protocol.TypeHierarchyItem, error) {
+		return mark.server().Subtypes(mark.ctx(), &protocol.TypeHierarchySubtypesParams{Item: item})
+	}
+	typeHierarchy(mark, src, getTypes, want)
+}
+
+func typeHierarchy(mark marker, src protocol.Location, getTypes func(protocol.TypeHierarchyItem) ([]protocol.TypeHierarchyItem, error), want []protocol.Location) {
+	items, err := mark.server().PrepareTypeHierarchy(mark.ctx(), &protocol.TypeHierarchyPrepareParams{
+		TextDocumentPositionParams: protocol.LocationTextDocumentPositionParams(src),
+	})
+	if err != nil {
+		mark.errorf("PrepareTypeHierarchy failed: %v", err)
+		return
+	}
+	if nitems := len(items); nitems != 1 {
+		mark.errorf("PrepareTypeHierarchy returned %d items, want exactly 1", nitems)
+		return
+	}
+	related, err := getTypes(items[0])
+	if err != nil {
+		mark.errorf("type hierarchy failed: %v", err)
+		return
+	}
+	got := []protocol.Location{}
+	for _, item := range related {
+		got = append(got, protocol.Location{URI: item.URI, Range: item.SelectionRange})
+	}
+	sort.Slice(want, func(i, j int) bool {
+		return protocol.CompareLocation(want[i], want[j]) < 0
+	})
+	if d := cmp.Diff(want, got); d != "" {
+		mark.errorf("type hierarchy: unexpected results (-want +got):\n%s", d)
+	}
+}
+
 type callHierarchyFunc = func(protocol.CallHierarchyItem) ([]protocol.Location, error)
 
 func callHierarchy(mark marker, src protocol.Location, getCalls callHierarchyFunc, want []protocol.Location) {
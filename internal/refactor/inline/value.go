@@ -0,0 +1,83 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package inline
+
+// This file exports the analyses of expressions that are needed to
+// inline local variables and constants, whose uses are replaced by
+// their initializer expressions.
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+)
+
+// Pure reports whether the expression e has the same result no matter
+// when it is evaluated relative to other expressions, so that it may
+// be moved from the declaration of a variable to each of its uses.
+//
+// Apart from single-assignment local variables, as classified by the
+// assign1 callback, e must not read any variable, call any function
+// other than a handful of pure built-ins, or receive from a channel.
+func Pure(info *types.Info, assign1 func(*types.Var) bool, e ast.Expr) bool {
+	return pure(info, assign1, e)
+}
+
+// Allocates reports whether each evaluation of the expression e may
+// create a distinct variable whose identity is observable, as do
+// new(T), make(...), &T{...}, slice and map literals, and the
+// []byte(s) and []rune(s) conversions. Such expressions are pure but
+// not duplicable: replacing two references to a variable by two
+// evaluations of its initializer would change the program.
+func Allocates(info *types.Info, e ast.Expr) bool {
+	allocates := false
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false // prune descent: the body is not evaluated
+
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				allocates = true
+			}
+
+		case *ast.CompositeLit:
+			switch info.TypeOf(n).Underlying().(type) {
+			case *types.Struct, *types.Array:
+			default:
+				allocates = true
+			}
+
+		case *ast.CallExpr:
+			if info.Types[n.Fun].IsType() {
+				// []byte(s) and []rune(s) allocate;
+				// other conversions do not.
+				if is[*types.Slice](info.TypeOf(n.Fun).Underlying()) && isBasic(info.TypeOf(n.Args[0]), types.IsString) {
+					allocates = true
+				}
+			} else if id, ok := ast.Unparen(n.Fun).(*ast.Ident); ok {
+				if b, ok := info.Uses[id].(*types.Builtin); ok && (b.Name() == "new" || b.Name() == "make") {
+					allocates = true
+				}
+			}
+		}
+		return !allocates
+	})
+	return allocates
+}
+
+// NeedsParens reports whether parens are required when the
+// expression old, whose enclosing nodes are path (innermost first,
+// starting with old itself), is replaced by new.
+func NeedsParens(path []ast.Node, old, new ast.Node) bool {
+	return needsParens(path, old, new)
+}
+
+// ConstantLiteral returns a literal expression (or for booleans, a
+// constant comparison) whose untyped value is v.
+func ConstantLiteral(v constant.Value) ast.Expr {
+	return makeLiteral(v)
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package inline_test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"golang.org/x/tools/internal/refactor/inline"
+)
+
+// TestPureAllocates is a unit test of the Pure and Allocates analyses
+// of the initializer of a local variable x.
+func TestPureAllocates(t *testing.T) {
+	var tests = []struct {
+		decl      string // Go source of the body of func f, declaring x
+		pure      bool
+		allocates bool
+	}{
+		{`x := 1 + len("hi")`, true, false},
+		{`x := y + 1`, true, false}, // y is a single-assignment parameter
+		{`x := g`, false, false},    // g is a package-level variable
+		{`x := h()`, false, false},
+		{`x := <-ch`, false, false},
+		{`x := *p`, false, false},
+		{`x := T{y}`, true, false},
+		{`x := &T{y}`, true, true},
+		{`x := []int{y}`, true, true},
+		{`x := new(int)`, true, true},
+		{`x := []byte("hi")`, true, true},
+		{`x := string("hi")`, true, false},
+		{`x := func() *T { return &T{} }`, true, false},
+	}
+	for _, test := range tests {
+		t.Run(test.decl, func(t *testing.T) {
+			const content = `package p
+
+var g int
+var ch chan int
+var p *int
+type T struct{ f int }
+func h() int
+
+func f(y int) {
+	%s
+	_ = x
+}
+`
+			fset := token.NewFileSet()
+			f, err := parser.ParseFile(fset, "p.go", fmt.Sprintf(content, test.decl), parser.SkipObjectResolution)
+			if err != nil {
+				t.Fatal(err)
+			}
+			info := &types.Info{
+				Defs:       make(map[*ast.Ident]types.Object),
+				Uses:       make(map[*ast.Ident]types.Object),
+				Types:      make(map[ast.Expr]types.TypeAndValue),
+				Selections: make(map[*ast.SelectorExpr]*types.Selection),
+			}
+			conf := &types.Config{Error: func(err error) { t.Error(err) }}
+			if _, err := conf.Check("p", fset, []*ast.File{f}, info); err != nil {
+				t.Fatal(err)
+			}
+			body := f.Decls[len(f.Decls)-1].(*ast.FuncDecl).Body
+			init := body.List[0].(*ast.AssignStmt).Rhs[0]
+			assign1 := func(*types.Var) bool { return true }
+			if got := inline.Pure(info, assign1, init); got != test.pure {
+				t.Errorf("Pure(%s) = %t, want %t", test.decl, got, test.pure)
+			}
+			if got := inline.Allocates(info, init); got != test.allocates {
+				t.Errorf("Allocates(%s) = %t, want %t", test.decl, got, test.allocates)
+			}
+		})
+	}
+}